@@ -12,6 +12,7 @@ import (
 
 	_ "github.com/lib/pq"
 
+	"avito/internal/domain"
 	"avito/internal/handlers"
 	"avito/internal/logging"
 	"avito/internal/repository"
@@ -51,19 +52,38 @@ func main() {
 	userRepo := repository.NewUserRepository(db)
 	prRepo := repository.NewPullRequestRepository(db)
 	statsRepo := repository.NewStatisticsRepository(db)
+	settingsRepo := repository.NewTeamSettingsRepository(db)
+	queueRepo := repository.NewReviewQueueRepository(db)
+	absenceRepo := repository.NewAbsenceRepository(db)
+	verdictRepo := repository.NewReviewVerdictRepository(db)
+	fallbackRepo := repository.NewTeamFallbackRepository(db)
+	codeOwnersRepo := repository.NewCodeOwnersRepository(db)
+	ruleRepo := repository.NewAssignmentRuleRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	historyRepo := repository.NewAssignmentEventRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	integrationUserRepo := repository.NewIntegrationUserRepository(db)
+	integrationProjectRepo := repository.NewIntegrationProjectRepository(db)
 	txMgr := repository.NewTransactionManager(db)
 
-	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, txMgr)
-	userService := service.NewUserService(userRepo)
-	prService := service.NewPullRequestService(prRepo, userRepo, txMgr)
+	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, settingsRepo, queueRepo, fallbackRepo, codeOwnersRepo, ruleRepo, outboxRepo, historyRepo, txMgr)
+	userService := service.NewUserService(userRepo, txMgr)
+	prService := service.NewPullRequestService(prRepo, userRepo, settingsRepo, queueRepo, verdictRepo, fallbackRepo, codeOwnersRepo, ruleRepo, outboxRepo, historyRepo, txMgr)
 	statsService := service.NewStatisticsService(statsRepo)
+	webhookService := service.NewWebhookService(webhookRepo, outboxRepo, txMgr)
+	integrationService := service.NewIntegrationService(prService, userRepo, teamRepo, integrationUserRepo, integrationProjectRepo)
+	absenceService := service.NewAbsenceService(absenceRepo, userRepo, prRepo, settingsRepo, queueRepo, outboxRepo, historyRepo, txMgr)
 
 	teamHandler := handlers.NewTeamHandler(teamService)
 	userHandler := handlers.NewUserHandler(userService, prRepo)
-	prHandler := handlers.NewPullRequestHandler(prService)
+	prHandler := handlers.NewPullRequestHandler(prService, cfg.AdminToken)
 	statsHandler := handlers.NewStatisticsHandler(statsService)
+	absenceHandler := handlers.NewAbsenceHandler(absenceService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	gitlabHandler := handlers.NewGitLabHandler(integrationService, cfg.GitLabWebhookToken)
+	githubHandler := handlers.NewGitHubHandler(integrationService, cfg.GitHubWebhookSecret)
 
-	router := handlers.Router(teamHandler, userHandler, prHandler, statsHandler)
+	router := handlers.Router(teamHandler, userHandler, prHandler, statsHandler, absenceHandler, webhookHandler, githubHandler, gitlabHandler, cfg.AdminToken)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.ServerPort),
@@ -73,6 +93,13 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(domain.WithActor(context.Background(), domain.ActorSystem))
+	defer stopJobs()
+
+	go runPeriodically(jobsCtx, cfg.ReviewQueueInterval, "review queue", prService.ProcessReviewQueues)
+	go runPeriodically(jobsCtx, cfg.AbsenceJobInterval, "absence reassignment", absenceService.ReassignStartedAbsences)
+	go runPeriodically(jobsCtx, cfg.WebhookDispatchInterval, "webhook dispatch", webhookService.Dispatch)
+
 	go func() {
 		logging.Info("Server starting on port", cfg.ServerPort)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -86,6 +113,7 @@ func main() {
 	<-quit
 
 	logging.Info("Server shutting down...")
+	stopJobs()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer shutdownCancel()
@@ -96,3 +124,20 @@ func main() {
 
 	logging.Info("Server exited")
 }
+
+// runPeriodically вызывает job с заданным интервалом, пока не отменён ctx
+func runPeriodically(ctx context.Context, interval time.Duration, name string, job func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logging.Error("Background job failed:", name, err)
+			}
+		}
+	}
+}
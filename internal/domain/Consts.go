@@ -1,10 +1,41 @@
 package domain
 
 const (
+	PRStatusDraft  = "DRAFT"
 	PRStatusOpen   = "OPEN"
 	PRStatusMerged = "MERGED"
+	PRStatusClosed = "CLOSED"
 )
 
+// Вердикты ревьювера; PENDING - ревьювер назначен, но ещё ничего не отправил
+const (
+	ReviewStateApproved         = "APPROVED"
+	ReviewStateChangesRequested = "CHANGES_REQUESTED"
+	ReviewStateCommented        = "COMMENTED"
+	ReviewStatePending          = "PENDING"
+)
+
+const (
+	ReviewerStrategyRandom      = "random"
+	ReviewerStrategyRoundRobin  = "round_robin"
+	ReviewerStrategyLeastLoaded = "least_loaded"
+)
+
+// Что делать, если у всех кандидатов исчерпан лимит открытых ревью
+const (
+	CapacityOverflowReject = "reject"
+	CapacityOverflowQueue  = "queue"
+)
+
+// Что делать с черновиками и открытыми PR удаляемой команды
+const (
+	OpenPRsClose    = "close"
+	OpenPRsReassign = "reassign"
+)
+
+// MaxReviewersLimit - верхняя граница max_reviewers в настройках команды
+const MaxReviewersLimit = 10
+
 const (
 	ErrCodeInvalidInput   = "INVALID_INPUT"
 	ErrCodeInvalidRequest = "INVALID_REQUEST"
@@ -15,4 +46,18 @@ const (
 	ErrCodeNotAssigned    = "NOT_ASSIGNED"
 	ErrCodeNoCandidate    = "NO_CANDIDATE"
 	ErrCodeNotFound       = "NOT_FOUND"
+
+	ErrCodeCapacityExhausted = "CAPACITY_EXHAUSTED"
+	ErrCodeReviewerApproved  = "REVIEWER_APPROVED"
+	ErrCodeNotApproved       = "NOT_APPROVED"
+	ErrCodeForbidden         = "FORBIDDEN"
+	ErrCodeInvalidState      = "INVALID_STATE"
+	ErrCodeUnauthorized      = "UNAUTHORIZED"
+	ErrCodeUserInTeam        = "USER_IN_TEAM"
+	ErrCodeVersionMismatch   = "VERSION_MISMATCH"
+
+	// Ошибки ограничений и конкурентного доступа БД, см. repository.translateError
+	ErrCodeAlreadyExists       = "ALREADY_EXISTS"
+	ErrCodeConstraintViolation = "CONSTRAINT_VIOLATION"
+	ErrCodeConcurrentUpdate    = "CONCURRENT_UPDATE"
 )
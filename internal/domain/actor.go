@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+const (
+	// ActorSystem - исполнитель фоновых задач
+	ActorSystem = "system"
+	// ActorAdmin - исполнитель запроса, подтверждённого токеном администратора
+	ActorAdmin = "admin"
+)
+
+type actorKey struct{}
+
+// WithActor сохраняет в контексте, от чьего имени выполняется операция
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom возвращает исполнителя операции; пусто, если он неизвестен
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
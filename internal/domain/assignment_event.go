@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Типы записей истории назначений
+const (
+	AssignmentEventAssigned   = "assigned"
+	AssignmentEventReassigned = "reassigned"
+	AssignmentEventRemoved    = "removed"
+)
+
+// AssignmentEvent - запись истории назначений PR. Для assigned заполнен ReviewerID, для removed -
+// PreviousReviewerID, для reassigned - оба. Reason - create, ready, reopen, queue, manual,
+// deactivation, absence или close.
+type AssignmentEvent struct {
+	ID                 int64
+	PullRequestID      string
+	EventType          string
+	ReviewerID         string
+	PreviousReviewerID string
+	Reason             string
+	// Actor - кто выполнил действие; пусто, если вызывающий себя не назвал
+	Actor     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// MaxAssignmentRules - сколько правил назначения может быть у команды
+const MaxAssignmentRules = 50
+
+// AssignmentRule - правило назначения ревьюверов команды: если PR подходит под When,
+// на него назначается не меньше Reviewers ревьюверов команды и ExtraReviewers ревьюверов из ExtraTeam
+type AssignmentRule struct {
+	TeamName       string
+	Name           string
+	When           PRFilter
+	Reviewers      int
+	ExtraTeam      string
+	ExtraReviewers int
+}
+
+func (r *AssignmentRule) Validate() error {
+	if r.When.IsEmpty() {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: condition cannot be empty", r.Name))
+	}
+	if r.When.MinLines != nil && r.When.MaxLines != nil && *r.When.MinLines > *r.When.MaxLines {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: min_lines cannot exceed max_lines", r.Name))
+	}
+	if r.Reviewers < 0 || r.Reviewers > MaxReviewersLimit {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: reviewers must be between 0 and %d", r.Name, MaxReviewersLimit))
+	}
+	if r.ExtraReviewers < 0 || r.ExtraReviewers > MaxReviewersLimit {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: extra_reviewers must be between 0 and %d", r.Name, MaxReviewersLimit))
+	}
+	if (r.ExtraTeam == "") != (r.ExtraReviewers == 0) {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: extra_team and extra_reviewers must be set together", r.Name))
+	}
+	if r.ExtraTeam == r.TeamName && r.ExtraTeam != "" {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: extra_team cannot be the rule's own team", r.Name))
+	}
+	if r.Reviewers == 0 && r.ExtraReviewers == 0 {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("rule %s: action must set reviewers or extra_reviewers", r.Name))
+	}
+	return nil
+}
+
+// MatchingRules возвращает сработавшие на PR правила в порядке приоритета
+func MatchingRules(rules []AssignmentRule, pr *PullRequest) []AssignmentRule {
+	var fired []AssignmentRule
+	for _, rule := range rules {
+		if rule.When.Matches(pr) {
+			fired = append(fired, rule)
+		}
+	}
+	return fired
+}
+
+// IsEmpty - фильтр не задаёт ни одного условия
+func (f *PRFilter) IsEmpty() bool {
+	return f.Repository == "" && f.TargetBranch == "" && len(f.Labels) == 0 && f.MinLines == nil && f.MaxLines == nil
+}
+
+// Matches проверяет PR по фильтру так же, как отбор в статистике
+func (f *PRFilter) Matches(pr *PullRequest) bool {
+	if f.Repository != "" && pr.Repository != f.Repository {
+		return false
+	}
+	if f.TargetBranch != "" && pr.TargetBranch != f.TargetBranch {
+		return false
+	}
+	for _, label := range f.Labels {
+		if !slices.Contains(pr.Labels, label) {
+			return false
+		}
+	}
+	lines := pr.LinesChanged()
+	if f.MinLines != nil && lines < *f.MinLines {
+		return false
+	}
+	if f.MaxLines != nil && lines > *f.MaxLines {
+		return false
+	}
+	return true
+}
+
+// RuleFiring - срабатывание правила на PR: копия действия правила и назначенные по нему ревьюверы
+type RuleFiring struct {
+	PullRequestID  string
+	TeamName       string
+	RuleName       string
+	Reviewers      int
+	ExtraTeam      string
+	ExtraReviewers int
+	// AssignedReviewers - ревьюверы из ExtraTeam, назначенные этим правилом
+	AssignedReviewers []string
+	// Source - при каком назначении сработало правило: create, ready, reopen
+	Source  string
+	FiredAt time.Time
+}
@@ -0,0 +1,68 @@
+package domain
+
+import "testing"
+
+func TestAssignmentRule_Validate(t *testing.T) {
+	lines := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		rule    AssignmentRule
+		wantErr bool
+	}{
+		{"extra team", AssignmentRule{TeamName: "backend", Name: "security", When: PRFilter{Labels: []string{"security"}}, ExtraTeam: "security", ExtraReviewers: 1}, false},
+		{"more reviewers", AssignmentRule{TeamName: "backend", Name: "large", When: PRFilter{MinLines: lines(1000)}, Reviewers: 3}, false},
+		{"empty condition", AssignmentRule{TeamName: "backend", Name: "all", Reviewers: 3}, true},
+		{"empty action", AssignmentRule{TeamName: "backend", Name: "noop", When: PRFilter{Repository: "org/api"}}, true},
+		{"min above max", AssignmentRule{TeamName: "backend", Name: "range", When: PRFilter{MinLines: lines(10), MaxLines: lines(5)}, Reviewers: 1}, true},
+		{"too many reviewers", AssignmentRule{TeamName: "backend", Name: "many", When: PRFilter{Repository: "org/api"}, Reviewers: MaxReviewersLimit + 1}, true},
+		{"extra team without count", AssignmentRule{TeamName: "backend", Name: "sec", When: PRFilter{Repository: "org/api"}, ExtraTeam: "security"}, true},
+		{"own team as extra", AssignmentRule{TeamName: "backend", Name: "self", When: PRFilter{Repository: "org/api"}, ExtraTeam: "backend", ExtraReviewers: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rule.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchingRules(t *testing.T) {
+	lines := func(v int) *int { return &v }
+	rules := []AssignmentRule{
+		{Name: "security", When: PRFilter{Labels: []string{"security"}}},
+		{Name: "large", When: PRFilter{MinLines: lines(1000)}},
+		{Name: "api-main", When: PRFilter{Repository: "org/api", TargetBranch: "main"}},
+	}
+
+	tests := []struct {
+		name string
+		meta PRMetadata
+		want []string
+	}{
+		{"none", PRMetadata{Repository: "org/web", Additions: 10}, nil},
+		{"label", PRMetadata{Labels: []string{"bug", "security"}}, []string{"security"}},
+		{"size boundary", PRMetadata{Additions: 600, Deletions: 400}, []string{"large"}},
+		{"all in priority order", PRMetadata{Repository: "org/api", TargetBranch: "main", Labels: []string{"security"}, Additions: 5000}, []string{"security", "large", "api-main"}},
+		{"repository without branch", PRMetadata{Repository: "org/api", TargetBranch: "release"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, rule := range MatchingRules(rules, &PullRequest{PRMetadata: tt.meta}) {
+				got = append(got, rule.Name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("MatchingRules() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("MatchingRules() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CodeOwnersRule - строка CODEOWNERS: шаблон пути и его владельцы (id пользователей)
+type CodeOwnersRule struct {
+	Line    int
+	Pattern string
+	Owners  []string
+	re      *regexp.Regexp
+}
+
+// CodeOwners - разобранный файл CODEOWNERS команды. Как и в GitHub, для пути действует
+// последнее подходящее правило; правило без владельцев снимает владение.
+type CodeOwners struct {
+	Rules []CodeOwnersRule
+}
+
+// ParseCodeOwners разбирает файл в формате CODEOWNERS. Владелец записывается как @user_id или user_id.
+func ParseCodeOwners(content string) (*CodeOwners, error) {
+	owners := &CodeOwners{Rules: []CodeOwnersRule{}}
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = strings.TrimSpace(line[:idx])
+		}
+
+		fields := strings.Fields(line)
+		rule := CodeOwnersRule{Line: i + 1, Pattern: fields[0], Owners: []string{}}
+
+		re, err := codeOwnersPatternRegexp(rule.Pattern)
+		if err != nil {
+			return nil, NewAppError(ErrCodeInvalidInput, fmt.Sprintf("CODEOWNERS line %d: invalid pattern %q", rule.Line, rule.Pattern))
+		}
+		rule.re = re
+
+		for _, owner := range fields[1:] {
+			owner = strings.TrimPrefix(owner, "@")
+			if owner == "" || strings.ContainsAny(owner, "/@") {
+				return nil, NewAppError(ErrCodeInvalidInput, fmt.Sprintf("CODEOWNERS line %d: owner %q must be a user id", rule.Line, owner))
+			}
+			rule.Owners = append(rule.Owners, owner)
+		}
+
+		owners.Rules = append(owners.Rules, rule)
+	}
+
+	return owners, nil
+}
+
+// Match возвращает правило, действующее для пути, или nil
+func (c *CodeOwners) Match(path string) *CodeOwnersRule {
+	path = strings.TrimPrefix(path, "/")
+	for i := len(c.Rules) - 1; i >= 0; i-- {
+		if c.Rules[i].re.MatchString(path) {
+			return &c.Rules[i]
+		}
+	}
+	return nil
+}
+
+// OwnersOf возвращает владельцев затронутых путей вместе с правилом, по которому каждый из них
+// стал владельцем (первое совпадение в порядке путей)
+func (c *CodeOwners) OwnersOf(paths []string) map[string]*CodeOwnersRule {
+	result := make(map[string]*CodeOwnersRule)
+	for _, path := range paths {
+		rule := c.Match(path)
+		if rule == nil {
+			continue
+		}
+		for _, owner := range rule.Owners {
+			if _, ok := result[owner]; !ok {
+				result[owner] = rule
+			}
+		}
+	}
+	return result
+}
+
+// codeOwnersPatternRegexp переводит шаблон в стиле gitignore в регулярное выражение:
+// ведущий или внутренний '/' привязывает шаблон к корню, завершающий '/' - только каталог,
+// '*' и '?' не переходят через '/', '**' - любое число каталогов.
+// Шаблон без завершающего '/' совпадает и с файлом, и со всем содержимым одноимённого каталога.
+func codeOwnersPatternRegexp(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		// "/" - весь репозиторий
+		return regexp.Compile(`^.*$`)
+	}
+
+	var b strings.Builder
+	if anchored {
+		b.WriteString("^")
+	} else {
+		b.WriteString("^(?:.*/)?")
+	}
+
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		}
+	}
+
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(b.String())
+}
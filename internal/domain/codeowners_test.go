@@ -0,0 +1,78 @@
+package domain
+
+import "testing"
+
+func TestCodeOwnersPatternMatching(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "internal/service/pr_service.go", true},
+		{"*.go", "README.md", false},
+		{"/docs/", "docs/api/index.md", true},
+		{"/docs/", "internal/docs/readme.md", false},
+		{"docs/", "docs/readme.md", true},
+		{"migrations", "migrations/000001_init_schema.up.sql", true},
+		{"migrations", "tools/migrations/run.sh", true},
+		{"internal/service", "internal/service/pr_service.go", true},
+		{"internal/service", "cmd/internal/service/x.go", false},
+		{"internal/*.go", "internal/app.go", true},
+		{"internal/*.go", "internal/service/app.go", false},
+		{"internal/**/*.go", "internal/service/app.go", true},
+		{"internal/**/*.go", "internal/app.go", true},
+		{"/build/logs/**", "build/logs/a/b.log", true},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"*", "anything/at/all.txt", true},
+	}
+
+	for _, tt := range tests {
+		owners, err := ParseCodeOwners(tt.pattern + " @owner")
+		if err != nil {
+			t.Fatalf("ParseCodeOwners(%q) error = %v", tt.pattern, err)
+		}
+		if got := owners.Match(tt.path) != nil; got != tt.want {
+			t.Errorf("pattern %q path %q: match = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCodeOwners_LastMatchWins(t *testing.T) {
+	owners, err := ParseCodeOwners(`
+# Owners of everything
+*                     @lead
+/internal/service/    @alice @bob   # business logic
+/internal/service/generated/
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owners.Rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %d", len(owners.Rules))
+	}
+
+	rule := owners.Match("internal/service/pr_service.go")
+	if rule == nil || rule.Line != 4 || len(rule.Owners) != 2 {
+		t.Fatalf("Expected line 4 with two owners, got %+v", rule)
+	}
+
+	if rule := owners.Match("internal/service/generated/mock.go"); rule == nil || len(rule.Owners) != 0 {
+		t.Errorf("Expected ownerless rule to unset owners, got %+v", rule)
+	}
+
+	byOwner := owners.OwnersOf([]string{"README.md", "internal/service/pr_service.go", "internal/service/generated/mock.go"})
+	if len(byOwner) != 3 || byOwner["lead"].Line != 3 || byOwner["alice"].Line != 4 {
+		t.Errorf("Unexpected owners %+v", byOwner)
+	}
+}
+
+func TestParseCodeOwners_InvalidOwner(t *testing.T) {
+	if _, err := ParseCodeOwners("*.go @org/backend"); err == nil {
+		t.Error("Expected error for team owner")
+	}
+	if _, err := ParseCodeOwners("*.go dev@example.com"); err == nil {
+		t.Error("Expected error for email owner")
+	}
+}
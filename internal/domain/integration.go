@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// Внешние системы, из которых принимаются события о PR
+const (
+	IntegrationGitHub = "github"
+	IntegrationGitLab = "gitlab"
+)
+
+// Действия над PR, в которые переводятся события внешних систем
+const (
+	IntegrationActionOpen   = "open"
+	IntegrationActionReady  = "ready"
+	IntegrationActionClose  = "close"
+	IntegrationActionReopen = "reopen"
+	IntegrationActionMerge  = "merge"
+)
+
+// IntegrationUserMapping связывает логин во внешней системе с пользователем сервиса
+type IntegrationUserMapping struct {
+	Provider      string
+	ExternalLogin string
+	UserID        string
+	CreatedAt     time.Time
+}
+
+// IntegrationProjectMapping направляет PR проекта внешней системы на ревью команде TeamName
+type IntegrationProjectMapping struct {
+	Provider  string
+	Project   string
+	TeamName  string
+	CreatedAt time.Time
+}
+
+// IntegrationCommand - событие внешней системы, переведённое в действие над PR.
+// Пустой Action - событие не требует действий, причина в Reason.
+type IntegrationCommand struct {
+	Provider      string
+	Action        string
+	PullRequestID string
+	// Project - путь проекта во внешней системе, по нему выбирается команда ревьюверов
+	Project     string
+	Name        string
+	AuthorLogin string
+	Draft       bool
+	Reason      string
+	// Metadata - сведения о PR из события; сохраняются при создании PR
+	Metadata PRMetadata
+}
+
+// IntegrationResult - итог обработки события: PR == nil, если событие проигнорировано
+type IntegrationResult struct {
+	Action string
+	PR     *PullRequest
+	Reason string
+}
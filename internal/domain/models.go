@@ -1,12 +1,29 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type User struct {
 	ID       string
 	Username string
 	TeamName string
 	IsActive bool
+	// MaxOpenReviews - личный лимит открытых ревью, nil - используется лимит команды
+	MaxOpenReviews *int
+}
+
+// Absence - период отсутствия пользователя, в который он не назначается ревьювером
+type Absence struct {
+	ID        int64
+	UserID    string
+	StartsAt  time.Time
+	EndsAt    time.Time
+	Reason    string
+	CreatedAt time.Time
+	// ReviewsReassignedAt - когда открытые ревью пользователя были переданы другим
+	ReviewsReassignedAt *time.Time
 }
 
 type PullRequest struct {
@@ -17,18 +34,229 @@ type PullRequest struct {
 	AssignedReviewers []string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	ClosedAt          *time.Time
+	// PendingReviewers - сколько ревьюверов ждут в очереди, пока у кого-то освободится место
+	PendingReviewers int
+	// Decisions заполняется только операциями, которые выбирали ревьюверов
+	Decisions []ReviewerDecision
+	// Reviews - последний вердикт каждого назначенного ревьювера
+	Reviews []ReviewVerdict
+	// MergeForced - PR смержен администратором без нужных одобрений
+	MergeForced bool
+	// MergeForcedBy - проверенный исполнитель принудительного мержа (токен администратора или провайдер интеграции)
+	MergeForcedBy string
+	// ReviewTeam - команда, из которой назначаются ревьюверы; пустая - команда автора
+	ReviewTeam string
+	// ChangedFiles - затронутые пути, по ним ревьюверы подбираются из владельцев в CODEOWNERS
+	ChangedFiles []string
+	// FiredRules заполняется только операциями, которые выбирали ревьюверов
+	FiredRules []RuleFiring
+	// Version увеличивается при каждом изменении PR или состава его ревьюверов
+	Version int64
+	PRMetadata
+}
+
+// PRMetadata - сведения о PR из системы контроля версий
+type PRMetadata struct {
+	Repository   string
+	SourceBranch string
+	TargetBranch string
+	URL          string
+	Labels       []string
+	Additions    int
+	Deletions    int
+	// ChangedFilesCount - число изменённых файлов; может быть больше len(ChangedFiles), если список не передан целиком
+	ChangedFilesCount int
+}
+
+// LinesChanged - размер PR в строках
+func (m *PRMetadata) LinesChanged() int {
+	return m.Additions + m.Deletions
+}
+
+// PRFilter - отбор PR по метаданным; пустые поля не ограничивают выборку
+type PRFilter struct {
+	Repository   string
+	TargetBranch string
+	// Labels - PR должен иметь все перечисленные метки
+	Labels   []string
+	MinLines *int
+	MaxLines *int
+}
+
+// ReviewVerdict - решение ревьювера по PR; история хранится целиком
+type ReviewVerdict struct {
+	ID            int64
+	PullRequestID string
+	ReviewerID    string
+	State         string
+	Body          string
+	CreatedAt     time.Time
+}
+
+// LatestReviews возвращает состояние каждого назначенного ревьювера в порядке назначения.
+// latest - последние вердикты по PR; ревьюверы без вердикта получают PENDING.
+func LatestReviews(assigned []string, latest []ReviewVerdict) []ReviewVerdict {
+	byReviewer := make(map[string]ReviewVerdict, len(latest))
+	for _, v := range latest {
+		byReviewer[v.ReviewerID] = v
+	}
+
+	reviews := make([]ReviewVerdict, len(assigned))
+	for i, reviewerID := range assigned {
+		if v, ok := byReviewer[reviewerID]; ok {
+			reviews[i] = v
+			continue
+		}
+		reviews[i] = ReviewVerdict{ReviewerID: reviewerID, State: ReviewStatePending}
+	}
+	return reviews
+}
+
+// ReviewerDecision - почему был выбран ревьювер: стратегия и загрузка на момент выбора
+type ReviewerDecision struct {
+	ReviewerID  string
+	Strategy    string
+	OpenReviews int
+	// FallbackTeam - запасная команда, из которой взят ревьювер; пусто для своей команды
+	FallbackTeam string
+	// CodeOwnersRule - правило CODEOWNERS, по которому ревьювер выбран как владелец затронутого пути
+	CodeOwnersRule *CodeOwnersRule
+	// AssignmentRule - правило назначения, которое добавило ревьювера из другой команды
+	AssignmentRule string
 }
 
 type PullRequestShort struct {
-	ID       string
-	Name     string
-	AuthorID string
-	Status   string
+	ID        string
+	Name      string
+	AuthorID  string
+	Status    string
+	CreatedAt time.Time
 }
 
 type Team struct {
-	Name    string
-	Members []TeamMember
+	Name string
+	// ReviewerStrategy хранится в настройках команды, пустая при создании - стратегия по умолчанию
+	ReviewerStrategy string
+	Members          []TeamMember
+}
+
+// TeamSettings - политика назначения ревьюверов команды
+type TeamSettings struct {
+	TeamName         string
+	MinReviewers     int
+	MaxReviewers     int
+	ReviewerStrategy string
+	// AllowNoReviewers разрешает создать PR, если в команде нет ни одного кандидата
+	AllowNoReviewers bool
+	// DefaultMaxOpenReviews - лимит открытых ревью для участников без личного лимита, nil - без лимита
+	DefaultMaxOpenReviews *int
+	CapacityOverflow      string
+	// RequiredApprovals - сколько одобрений нужно для мержа, 0 - мерж без проверки
+	RequiredApprovals int
+	// LargePRLines - PR, изменивший больше строк, считается большим; 0 - правило выключено
+	LargePRLines int
+	// LargePRReviewers - сколько ревьюверов назначать на большой PR
+	LargePRReviewers int
+}
+
+// DefaultTeamSettings - настройки команды, для которой политика не задавалась
+func DefaultTeamSettings(teamName string) TeamSettings {
+	return TeamSettings{
+		TeamName:         teamName,
+		MinReviewers:     1,
+		MaxReviewers:     2,
+		ReviewerStrategy: ReviewerStrategyRandom,
+		AllowNoReviewers: true,
+		CapacityOverflow: CapacityOverflowReject,
+	}
+}
+
+// Validate проверяет согласованность настроек
+func (s *TeamSettings) Validate() error {
+	if s.MinReviewers < 0 {
+		return NewAppError(ErrCodeInvalidInput, "min_reviewers cannot be negative")
+	}
+	if s.MaxReviewers < s.MinReviewers {
+		return NewAppError(ErrCodeInvalidInput, "max_reviewers cannot be less than min_reviewers")
+	}
+	if s.MaxReviewers > MaxReviewersLimit {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("max_reviewers too large (max %d)", MaxReviewersLimit))
+	}
+	if s.DefaultMaxOpenReviews != nil && *s.DefaultMaxOpenReviews < 0 {
+		return NewAppError(ErrCodeInvalidInput, "default_max_open_reviews cannot be negative")
+	}
+	if s.CapacityOverflow != CapacityOverflowReject && s.CapacityOverflow != CapacityOverflowQueue {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("unknown capacity_overflow %q", s.CapacityOverflow))
+	}
+	if s.RequiredApprovals < 0 {
+		return NewAppError(ErrCodeInvalidInput, "required_approvals cannot be negative")
+	}
+	if s.RequiredApprovals > s.MaxReviewers {
+		return NewAppError(ErrCodeInvalidInput, "required_approvals cannot exceed max_reviewers")
+	}
+	if s.LargePRLines < 0 {
+		return NewAppError(ErrCodeInvalidInput, "large_pr_lines cannot be negative")
+	}
+	if s.LargePRReviewers < 0 || s.LargePRReviewers > MaxReviewersLimit {
+		return NewAppError(ErrCodeInvalidInput, fmt.Sprintf("large_pr_reviewers must be between 0 and %d", MaxReviewersLimit))
+	}
+	if s.LargePRLines > 0 && s.LargePRReviewers == 0 {
+		return NewAppError(ErrCodeInvalidInput, "large_pr_reviewers is required when large_pr_lines is set")
+	}
+	return nil
+}
+
+// ForPullRequest возвращает настройки с учётом размера PR: на большой PR назначается
+// до LargePRReviewers ревьюверов вместо MaxReviewers. Минимум не меняется, чтобы небольшая
+// команда могла открыть большой PR.
+func (s TeamSettings) ForPullRequest(pr *PullRequest) *TeamSettings {
+	if s.LargePRLines > 0 && pr.LinesChanged() > s.LargePRLines {
+		s.MaxReviewers = max(s.MaxReviewers, s.LargePRReviewers)
+	}
+	return &s
+}
+
+// CheckApprovals проверяет, можно ли мержить PR с такими состояниями ревьюверов:
+// одобрений не меньше RequiredApprovals и ни у кого не висит CHANGES_REQUESTED
+func (s *TeamSettings) CheckApprovals(reviews []ReviewVerdict) error {
+	approvals := 0
+	for _, v := range reviews {
+		switch v.State {
+		case ReviewStateApproved:
+			approvals++
+		case ReviewStateChangesRequested:
+			return NewAppError(ErrCodeNotApproved, fmt.Sprintf("reviewer %s requested changes", v.ReviewerID))
+		}
+	}
+
+	if approvals < s.RequiredApprovals {
+		return NewAppError(ErrCodeNotApproved, fmt.Sprintf("PR needs %d approvals, has %d", s.RequiredApprovals, approvals))
+	}
+	return nil
+}
+
+// QueuesOnOverflow - откладывать ли назначение, когда у всех кандидатов исчерпан лимит
+func (s *TeamSettings) QueuesOnOverflow() bool {
+	return s.CapacityOverflow == CapacityOverflowQueue
+}
+
+// CapacityError - ошибка для случая, когда кандидаты есть, но все заняты
+func (s *TeamSettings) CapacityError() error {
+	return NewAppError(ErrCodeCapacityExhausted, fmt.Sprintf("all reviewers in team %s are at their open review limit", s.TeamName))
+}
+
+// CheckReviewerCount проверяет, что найденного числа ревьюверов достаточно для PR.
+// candidates - сколько кандидатов было в команде: AllowNoReviewers действует, только если их нет,
+// а не когда все кандидаты заняты.
+func (s *TeamSettings) CheckReviewerCount(found, candidates int) error {
+	if found == 0 && candidates == 0 && s.AllowNoReviewers {
+		return nil
+	}
+	if found == 0 || found < s.MinReviewers {
+		return NewAppError(ErrCodeNoCandidate, fmt.Sprintf("not enough active reviewers in team %s: need %d, found %d", s.TeamName, s.MinReviewers, found))
+	}
+	return nil
 }
 
 type TeamMember struct {
@@ -37,6 +265,32 @@ type TeamMember struct {
 	IsActive bool
 }
 
+// ReviewCandidate - активный пользователь, которого можно назначить ревьювером,
+// вместе с данными о его текущей загрузке
+type ReviewCandidate struct {
+	User           User
+	OpenReviews    int
+	LastAssignedAt *time.Time
+}
+
+// HasCapacity - можно ли назначить кандидату ещё одно ревью с учётом личного лимита или лимита команды
+func (c *ReviewCandidate) HasCapacity(teamDefault *int) bool {
+	limit := c.User.MaxOpenReviews
+	if limit == nil {
+		limit = teamDefault
+	}
+	return limit == nil || c.OpenReviews < *limit
+}
+
+// QueuedReview - отложенное место ревьювера на PR
+type QueuedReview struct {
+	ID                int64
+	PullRequestID     string
+	AuthorID          string
+	TeamName          string
+	AssignedReviewers []string
+}
+
 type AssignmentStat struct {
 	ID    string
 	Count int
@@ -45,10 +299,12 @@ type AssignmentStat struct {
 type Statistics struct {
 	AssignmentsByUser []AssignmentStat
 	AssignmentsByPR   []AssignmentStat
-	TotalPRs          int
-	TotalAssignments  int
-	ActiveUsers       int
-	Teams             int
+	// OpenAssignmentsByUser - текущая загрузка: назначения только на OPEN PR
+	OpenAssignmentsByUser []AssignmentStat
+	TotalPRs              int
+	TotalAssignments      int
+	ActiveUsers           int
+	Teams                 int
 }
 
 type ReviewAssignment struct {
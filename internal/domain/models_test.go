@@ -0,0 +1,97 @@
+package domain
+
+import "testing"
+
+func TestTeamSettings_ForPullRequest(t *testing.T) {
+	settings := DefaultTeamSettings("backend")
+	settings.LargePRLines = 500
+	settings.LargePRReviewers = 3
+
+	tests := []struct {
+		name      string
+		additions int
+		deletions int
+		want      int
+	}{
+		{"small", 100, 50, 2},
+		{"at threshold", 300, 200, 2},
+		{"large", 400, 101, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := &PullRequest{PRMetadata: PRMetadata{Additions: tt.additions, Deletions: tt.deletions}}
+			got := settings.ForPullRequest(pr)
+			if got.MaxReviewers != tt.want {
+				t.Errorf("MaxReviewers = %d, want %d", got.MaxReviewers, tt.want)
+			}
+			if got.MinReviewers != settings.MinReviewers {
+				t.Errorf("MinReviewers changed to %d", got.MinReviewers)
+			}
+		})
+	}
+
+	if settings.MaxReviewers != 2 {
+		t.Errorf("ForPullRequest must not modify the team settings, got MaxReviewers = %d", settings.MaxReviewers)
+	}
+
+	disabled := DefaultTeamSettings("backend")
+	pr := &PullRequest{PRMetadata: PRMetadata{Additions: 10000}}
+	if got := disabled.ForPullRequest(pr); got.MaxReviewers != disabled.MaxReviewers {
+		t.Errorf("Expected rule disabled by default, got MaxReviewers = %d", got.MaxReviewers)
+	}
+}
+
+func TestTeamSettings_CheckApprovals(t *testing.T) {
+	approved := ReviewVerdict{ReviewerID: "alice", State: ReviewStateApproved}
+	changes := ReviewVerdict{ReviewerID: "bob", State: ReviewStateChangesRequested}
+	pending := ReviewVerdict{ReviewerID: "carol", State: ReviewStatePending}
+
+	tests := []struct {
+		name     string
+		required int
+		reviews  []ReviewVerdict
+		wantErr  bool
+	}{
+		{"default policy without verdicts", 0, []ReviewVerdict{pending}, false},
+		{"default policy with changes requested", 0, []ReviewVerdict{approved, changes}, true},
+		{"enough approvals", 1, []ReviewVerdict{approved, pending}, false},
+		{"not enough approvals", 2, []ReviewVerdict{approved, pending}, true},
+		{"approvals with changes requested", 1, []ReviewVerdict{approved, changes}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := DefaultTeamSettings("backend")
+			settings.RequiredApprovals = tt.required
+			err := settings.CheckApprovals(tt.reviews)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckApprovals() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTeamSettings_CheckReviewerCount(t *testing.T) {
+	settings := DefaultTeamSettings("backend")
+
+	tests := []struct {
+		name       string
+		found      int
+		candidates int
+		wantErr    bool
+	}{
+		{"no candidates in team", 0, 0, false},
+		{"all candidates busy", 0, 2, true},
+		{"enough reviewers", 1, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := settings.CheckReviewerCount(tt.found, tt.candidates)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckReviewerCount(%d, %d) = %v, wantErr %v", tt.found, tt.candidates, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Действия над PR. Переходы между статусами и допустимость операций с ревьюверами
+// задаются только таблицей prLifecycle.
+const (
+	PRActionReady    = "ready"
+	PRActionClose    = "close"
+	PRActionReopen   = "reopen"
+	PRActionMerge    = "merge"
+	PRActionReview   = "review"
+	PRActionReassign = "reassign"
+	// PRActionUpdate - изменение названия и метаданных PR
+	PRActionUpdate = "update"
+)
+
+// prLifecycle: статус -> действие -> статус после действия
+var prLifecycle = map[string]map[string]string{
+	PRStatusDraft: {
+		PRActionReady:  PRStatusOpen,
+		PRActionClose:  PRStatusClosed,
+		PRActionUpdate: PRStatusDraft,
+	},
+	PRStatusOpen: {
+		PRActionMerge:    PRStatusMerged,
+		PRActionClose:    PRStatusClosed,
+		PRActionReview:   PRStatusOpen,
+		PRActionReassign: PRStatusOpen,
+		PRActionUpdate:   PRStatusOpen,
+	},
+	PRStatusClosed: {
+		PRActionReopen: PRStatusOpen,
+		PRActionUpdate: PRStatusClosed,
+	},
+	PRStatusMerged: {
+		// Повторный мерж идемпотентен
+		PRActionMerge: PRStatusMerged,
+	},
+}
+
+// NextPRStatus возвращает статус PR после действия или ошибку, если действие в этом статусе запрещено
+func NextPRStatus(status, action string) (string, error) {
+	if next, ok := prLifecycle[status][action]; ok {
+		return next, nil
+	}
+	if status == PRStatusMerged {
+		return "", NewAppError(ErrCodePRMerged, fmt.Sprintf("cannot %s merged PR", action))
+	}
+	return "", NewAppError(ErrCodeInvalidState, fmt.Sprintf("cannot %s PR in status %s", action, status))
+}
+
+// CheckAction проверяет, что действие допустимо в текущем статусе PR, не меняя его
+func (pr *PullRequest) CheckAction(action string) error {
+	_, err := NextPRStatus(pr.Status, action)
+	return err
+}
+
+// CheckVersion сверяет версию PR с ожидаемой клиентом; expected == 0 - проверка не нужна
+func (pr *PullRequest) CheckVersion(expected int64) error {
+	if expected != 0 && expected != pr.Version {
+		return NewAppError(ErrCodeVersionMismatch, fmt.Sprintf("PR has been modified: current version %d, expected %d", pr.Version, expected))
+	}
+	return nil
+}
+
+// Apply выполняет переход по действию и проставляет связанные с ним отметки времени
+func (pr *PullRequest) Apply(action string, at time.Time) error {
+	next, err := NextPRStatus(pr.Status, action)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case next == PRStatusMerged && pr.Status != PRStatusMerged:
+		pr.MergedAt = &at
+	case next == PRStatusClosed:
+		pr.ClosedAt = &at
+	case action == PRActionReopen:
+		pr.ClosedAt = nil
+	}
+
+	pr.Status = next
+	return nil
+}
+
+// NeedsReviewers - после перехода в этот статус PR нужно назначить ревьюверов
+func NeedsReviewers(action string) bool {
+	return action == PRActionReady || action == PRActionReopen
+}
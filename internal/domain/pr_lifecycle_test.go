@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextPRStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		action  string
+		want    string
+		wantErr string
+	}{
+		{PRStatusDraft, PRActionReady, PRStatusOpen, ""},
+		{PRStatusDraft, PRActionClose, PRStatusClosed, ""},
+		{PRStatusDraft, PRActionMerge, "", ErrCodeInvalidState},
+		{PRStatusDraft, PRActionReview, "", ErrCodeInvalidState},
+		{PRStatusOpen, PRActionMerge, PRStatusMerged, ""},
+		{PRStatusOpen, PRActionClose, PRStatusClosed, ""},
+		{PRStatusOpen, PRActionReassign, PRStatusOpen, ""},
+		{PRStatusOpen, PRActionReady, "", ErrCodeInvalidState},
+		{PRStatusClosed, PRActionReopen, PRStatusOpen, ""},
+		{PRStatusClosed, PRActionMerge, "", ErrCodeInvalidState},
+		{PRStatusMerged, PRActionMerge, PRStatusMerged, ""},
+		{PRStatusMerged, PRActionReassign, "", ErrCodePRMerged},
+		{PRStatusMerged, PRActionClose, "", ErrCodePRMerged},
+		{PRStatusClosed, PRActionUpdate, PRStatusClosed, ""},
+		{PRStatusMerged, PRActionUpdate, "", ErrCodePRMerged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status+"_"+tt.action, func(t *testing.T) {
+			got, err := NextPRStatus(tt.status, tt.action)
+			if tt.wantErr != "" {
+				appErr, ok := err.(*AppError)
+				if !ok || appErr.Code != tt.wantErr {
+					t.Fatalf("NextPRStatus() error = %v, want code %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NextPRStatus() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPullRequest_Apply(t *testing.T) {
+	now := time.Now()
+	pr := &PullRequest{Status: PRStatusOpen}
+
+	if err := pr.Apply(PRActionClose, now); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if pr.Status != PRStatusClosed || pr.ClosedAt == nil {
+		t.Fatalf("Expected CLOSED with closed_at, got %+v", pr)
+	}
+
+	if err := pr.Apply(PRActionReopen, now); err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	if pr.Status != PRStatusOpen || pr.ClosedAt != nil {
+		t.Fatalf("Expected OPEN without closed_at, got %+v", pr)
+	}
+
+	if err := pr.Apply(PRActionMerge, now); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	mergedAt := pr.MergedAt
+	if err := pr.Apply(PRActionMerge, now.Add(time.Hour)); err != nil {
+		t.Fatalf("repeated merge: %v", err)
+	}
+	if pr.MergedAt != mergedAt {
+		t.Error("Repeated merge must keep the original merged_at")
+	}
+
+	if err := pr.Apply(PRActionReopen, now); err == nil {
+		t.Error("Expected merged PR to reject reopen")
+	}
+}
+
+func TestPullRequest_CheckVersion(t *testing.T) {
+	pr := &PullRequest{Version: 3}
+
+	if err := pr.CheckVersion(0); err != nil {
+		t.Errorf("Expected no check without expected version, got %v", err)
+	}
+	if err := pr.CheckVersion(3); err != nil {
+		t.Errorf("Expected matching version to pass, got %v", err)
+	}
+
+	err := pr.CheckVersion(2)
+	appErr, ok := err.(*AppError)
+	if !ok || appErr.Code != ErrCodeVersionMismatch {
+		t.Errorf("Expected VERSION_MISMATCH for stale version, got %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package domain
+
+import "time"
+
+// Сортировка списка PR; PR идут от новых к старым
+const (
+	PRSortCreatedAt = "created_at"
+	PRSortMergedAt  = "merged_at"
+)
+
+// Ограничения размера страницы списка PR
+const (
+	DefaultPRPageSize = 20
+	MaxPRPageSize     = 100
+)
+
+// PRListQuery - выборка PR для списка; пустые поля не ограничивают выборку.
+// При сортировке по merged_at в список попадают только смерженные PR.
+type PRListQuery struct {
+	PRFilter
+	Status     string
+	AuthorID   string
+	ReviewerID string
+	// TeamName - команда, ревьюящая PR: review_team, а если она не задана - команда автора
+	TeamName     string
+	NameContains string
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time
+	MergedFrom   *time.Time
+	MergedTo     *time.Time
+	SortBy       string
+	Limit        int
+	// After - позиция, после которой начинается страница
+	After *PRCursor
+}
+
+// PRCursor - позиция в списке PR: значение поля сортировки и id последнего PR страницы
+type PRCursor struct {
+	SortBy string
+	Value  time.Time
+	ID     string
+}
+
+// CursorOf возвращает позицию PR в списке с сортировкой sortBy
+func CursorOf(pr *PullRequest, sortBy string) *PRCursor {
+	cursor := &PRCursor{SortBy: sortBy, Value: pr.CreatedAt, ID: pr.ID}
+	if sortBy == PRSortMergedAt && pr.MergedAt != nil {
+		cursor.Value = *pr.MergedAt
+	}
+	return cursor
+}
+
+// PRPage - страница списка PR; Next == nil - страница последняя
+type PRPage struct {
+	PullRequests []PullRequest
+	Next         *PRCursor
+}
+
+// ReviewQuery - выборка PR, назначенных ревьюверу, от новых к старым; Limit 0 - без ограничения
+type ReviewQuery struct {
+	ReviewerID string
+	Status     string
+	Limit      int
+	After      *PRCursor
+}
+
+// ReviewPage - страница PR ревьювера; Total - число PR с учётом статуса, но без учёта страницы
+type ReviewPage struct {
+	PullRequests []PullRequestShort
+	Total        int
+	Next         *PRCursor
+}
@@ -0,0 +1,132 @@
+package domain
+
+import "time"
+
+// События, которые сервис публикует через outbox
+const (
+	EventPRCreated          = "pr.created"
+	EventPRReady            = "pr.ready"
+	EventPRClosed           = "pr.closed"
+	EventPRReopened         = "pr.reopened"
+	EventPRMerged           = "pr.merged"
+	EventPRUpdated          = "pr.updated"
+	EventReviewersAssigned  = "reviewers.assigned"
+	EventReviewerReassigned = "reviewer.reassigned"
+	EventReviewerRemoved    = "reviewer.removed"
+)
+
+// EventTypes - все известные типы событий, допустимые в фильтре подписки
+var EventTypes = []string{
+	EventPRCreated, EventPRReady, EventPRClosed, EventPRReopened, EventPRMerged, EventPRUpdated,
+	EventReviewersAssigned, EventReviewerReassigned, EventReviewerRemoved,
+}
+
+func IsEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Повторные попытки доставки: задержка удваивается от WebhookRetryBaseDelay до WebhookRetryMaxDelay,
+// после WebhookMaxAttempts неудачных попыток доставка считается проваленной
+const (
+	WebhookMaxAttempts    = 8
+	WebhookRetryBaseDelay = 10 * time.Second
+	WebhookRetryMaxDelay  = time.Hour
+)
+
+// WebhookRetryDelay - задержка перед следующей попыткой после attempt неудачных
+func WebhookRetryDelay(attempt int) time.Duration {
+	delay := WebhookRetryBaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= WebhookRetryMaxDelay {
+			return WebhookRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+type WebhookSubscription struct {
+	ID     int64
+	URL    string
+	Secret string
+	// EventTypes - фильтр событий, пустой - все события
+	EventTypes []string
+	IsActive   bool
+	CreatedAt  time.Time
+}
+
+// Accepts - нужно ли доставлять подписке событие этого типа
+func (s *WebhookSubscription) Accepts(eventType string) bool {
+	if !s.IsActive {
+		return false
+	}
+	if len(s.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxEvent - событие, записанное в транзакции изменения
+type OutboxEvent struct {
+	ID        int64
+	EventType string
+	Payload   []byte
+	CreatedAt time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        int64
+	EventType      string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus *int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookDispatch - взятая в работу доставка вместе с данными для отправки
+type WebhookDispatch struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+	Event    OutboxEvent
+}
+
+// RecordAttempt обновляет доставку по результату попытки: statusCode 0 - ответа не было
+func (d *WebhookDelivery) RecordAttempt(statusCode int, errMsg string, now time.Time) {
+	if statusCode != 0 {
+		d.ResponseStatus = &statusCode
+	}
+	d.LastError = errMsg
+
+	switch {
+	case errMsg == "" && statusCode >= 200 && statusCode < 300:
+		d.Status = DeliveryStatusDelivered
+		d.DeliveredAt = &now
+	case d.Attempts >= WebhookMaxAttempts:
+		d.Status = DeliveryStatusFailed
+	default:
+		d.Status = DeliveryStatusPending
+		d.NextAttemptAt = now.Add(WebhookRetryDelay(d.Attempts))
+	}
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{4, 80 * time.Second},
+		{10, time.Hour},
+		{100, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := WebhookRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("WebhookRetryDelay(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDelivery_RecordAttempt(t *testing.T) {
+	now := time.Now()
+
+	delivered := WebhookDelivery{Status: DeliveryStatusPending, Attempts: 1}
+	delivered.RecordAttempt(204, "", now)
+	if delivered.Status != DeliveryStatusDelivered || delivered.DeliveredAt == nil {
+		t.Errorf("Expected delivered, got %+v", delivered)
+	}
+
+	retried := WebhookDelivery{Status: DeliveryStatusPending, Attempts: 2}
+	retried.RecordAttempt(500, "unexpected response status 500", now)
+	if retried.Status != DeliveryStatusPending || !retried.NextAttemptAt.Equal(now.Add(20*time.Second)) {
+		t.Errorf("Expected retry in 20s, got %+v", retried)
+	}
+	if retried.ResponseStatus == nil || *retried.ResponseStatus != 500 {
+		t.Errorf("Expected response status 500, got %v", retried.ResponseStatus)
+	}
+
+	failed := WebhookDelivery{Status: DeliveryStatusPending, Attempts: WebhookMaxAttempts}
+	failed.RecordAttempt(0, "connection refused", now)
+	if failed.Status != DeliveryStatusFailed || failed.ResponseStatus != nil {
+		t.Errorf("Expected failed without response, got %+v", failed)
+	}
+}
+
+func TestWebhookSubscription_Accepts(t *testing.T) {
+	all := WebhookSubscription{IsActive: true}
+	if !all.Accepts(EventPRMerged) {
+		t.Error("Empty filter must accept every event")
+	}
+
+	filtered := WebhookSubscription{IsActive: true, EventTypes: []string{EventPRCreated}}
+	if !filtered.Accepts(EventPRCreated) || filtered.Accepts(EventPRMerged) {
+		t.Error("Filter must accept only listed events")
+	}
+
+	inactive := WebhookSubscription{IsActive: false}
+	if inactive.Accepts(EventPRCreated) {
+		t.Error("Inactive subscription must not accept events")
+	}
+}
@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"avito/internal/domain"
+)
+
+const maxAbsenceReasonLength = 500
+
+// CreateAbsenceRequest - период отсутствия пользователя, время в RFC 3339
+type CreateAbsenceRequest struct {
+	UserID   string    `json:"user_id"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+func (r *CreateAbsenceRequest) Validate() error {
+	if err := ValidateUserID(r.UserID); err != nil {
+		return err
+	}
+	if r.StartsAt.IsZero() || r.EndsAt.IsZero() {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "starts_at and ends_at are required")
+	}
+	if !r.EndsAt.After(r.StartsAt) {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "ends_at must be after starts_at")
+	}
+	if len(r.Reason) > maxAbsenceReasonLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "reason too long (max 500 characters)")
+	}
+	return nil
+}
+
+func (r *CreateAbsenceRequest) ToDomain() *domain.Absence {
+	return &domain.Absence{
+		UserID:   r.UserID,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+		Reason:   r.Reason,
+	}
+}
+
+type DeleteAbsenceRequest struct {
+	AbsenceID int64 `json:"absence_id"`
+}
+
+func (r *DeleteAbsenceRequest) Validate() error {
+	if r.AbsenceID <= 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "absence_id must be positive")
+	}
+	return nil
+}
+
+type AbsenceResponse struct {
+	ID                  int64      `json:"absence_id"`
+	UserID              string     `json:"user_id"`
+	StartsAt            time.Time  `json:"starts_at"`
+	EndsAt              time.Time  `json:"ends_at"`
+	Reason              string     `json:"reason"`
+	CreatedAt           time.Time  `json:"created_at"`
+	ReviewsReassignedAt *time.Time `json:"reviews_reassigned_at,omitempty"`
+}
+
+func AbsenceFromDomain(absence *domain.Absence) AbsenceResponse {
+	return AbsenceResponse{
+		ID:                  absence.ID,
+		UserID:              absence.UserID,
+		StartsAt:            absence.StartsAt,
+		EndsAt:              absence.EndsAt,
+		Reason:              absence.Reason,
+		CreatedAt:           absence.CreatedAt,
+		ReviewsReassignedAt: absence.ReviewsReassignedAt,
+	}
+}
+
+func AbsencesFromDomain(absences []domain.Absence) []AbsenceResponse {
+	result := make([]AbsenceResponse, len(absences))
+	for i := range absences {
+		result[i] = AbsenceFromDomain(&absences[i])
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package dto
+
+import (
+	"time"
+
+	"avito/internal/domain"
+)
+
+// AssignmentEvent - запись истории назначений: event - assigned, reassigned или removed,
+// reason - create, ready, reopen, queue, manual, deactivation, absence, removal, transfer, team_deletion или close
+type AssignmentEvent struct {
+	Event              string    `json:"event"`
+	ReviewerID         string    `json:"reviewer_id,omitempty"`
+	PreviousReviewerID string    `json:"previous_reviewer_id,omitempty"`
+	Reason             string    `json:"reason"`
+	Actor              string    `json:"actor,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func AssignmentEventsFromDomain(events []domain.AssignmentEvent) []AssignmentEvent {
+	result := make([]AssignmentEvent, len(events))
+	for i, e := range events {
+		result[i] = AssignmentEvent{
+			Event:              e.EventType,
+			ReviewerID:         e.ReviewerID,
+			PreviousReviewerID: e.PreviousReviewerID,
+			Reason:             e.Reason,
+			Actor:              e.Actor,
+			CreatedAt:          e.CreatedAt,
+		}
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+const maxRuleNameLength = 100
+
+// TeamRules - правила назначения ревьюверов команды в порядке приоритета; список заменяется целиком
+type TeamRules struct {
+	TeamName string           `json:"team_name"`
+	Rules    []AssignmentRule `json:"rules"`
+}
+
+// AssignmentRule - правило "если PR подходит под when, назначить then".
+// Условия when объединяются через И; метки сравниваются без учёта регистра.
+type AssignmentRule struct {
+	Name string              `json:"name"`
+	When AssignmentCondition `json:"when"`
+	Then AssignmentAction    `json:"then"`
+}
+
+type AssignmentCondition struct {
+	Repository   string   `json:"repository,omitempty"`
+	TargetBranch string   `json:"target_branch,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	MinLines     *int     `json:"min_lines,omitempty"`
+	MaxLines     *int     `json:"max_lines,omitempty"`
+}
+
+// AssignmentAction - reviewers поднимает число ревьюверов своей команды до указанного,
+// extra_reviewers ревьюверов из extra_team назначаются сверх него
+type AssignmentAction struct {
+	Reviewers      int    `json:"reviewers,omitempty"`
+	ExtraTeam      string `json:"extra_team,omitempty"`
+	ExtraReviewers int    `json:"extra_reviewers,omitempty"`
+}
+
+// Validate проверяет запрос на замену правил; пустой список снимает все правила
+func (r *TeamRules) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	if r.Rules == nil {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "rules is required")
+	}
+	if len(r.Rules) > domain.MaxAssignmentRules {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("too many rules (max %d)", domain.MaxAssignmentRules))
+	}
+
+	seen := make(map[string]struct{}, len(r.Rules))
+	for _, rule := range r.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+		if _, ok := seen[rule.Name]; ok {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("duplicate rule %s", rule.Name))
+		}
+		seen[rule.Name] = struct{}{}
+	}
+	return nil
+}
+
+func (r *AssignmentRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "rule name cannot be empty")
+	}
+	if len(r.Name) > maxRuleNameLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("rule name too long (max %d characters)", maxRuleNameLength))
+	}
+	if !nameRegex.MatchString(r.Name) {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "rule name contains invalid characters")
+	}
+	if err := validateRef("repository", r.When.Repository); err != nil {
+		return err
+	}
+	if err := validateRef("target_branch", r.When.TargetBranch); err != nil {
+		return err
+	}
+	if err := ValidateLabels(r.When.Labels); err != nil {
+		return err
+	}
+	if r.When.MinLines != nil {
+		if err := validateCount("min_lines", *r.When.MinLines); err != nil {
+			return err
+		}
+	}
+	if r.When.MaxLines != nil {
+		if err := validateCount("max_lines", *r.When.MaxLines); err != nil {
+			return err
+		}
+	}
+	if r.Then.ExtraTeam != "" {
+		if err := ValidateTeamName(r.Then.ExtraTeam); err != nil {
+			return err
+		}
+	}
+
+	rule := r.ToDomain()
+	return rule.Validate()
+}
+
+func (r *AssignmentRule) ToDomain() domain.AssignmentRule {
+	return domain.AssignmentRule{
+		Name: r.Name,
+		When: domain.PRFilter{
+			Repository:   r.When.Repository,
+			TargetBranch: r.When.TargetBranch,
+			Labels:       normalizeLabels(r.When.Labels),
+			MinLines:     r.When.MinLines,
+			MaxLines:     r.When.MaxLines,
+		},
+		Reviewers:      r.Then.Reviewers,
+		ExtraTeam:      r.Then.ExtraTeam,
+		ExtraReviewers: r.Then.ExtraReviewers,
+	}
+}
+
+func (r *TeamRules) ToDomain() []domain.AssignmentRule {
+	rules := make([]domain.AssignmentRule, len(r.Rules))
+	for i := range r.Rules {
+		rules[i] = r.Rules[i].ToDomain()
+		rules[i].TeamName = r.TeamName
+	}
+	return rules
+}
+
+func TeamRulesFromDomain(teamName string, rules []domain.AssignmentRule) TeamRules {
+	result := TeamRules{TeamName: teamName, Rules: make([]AssignmentRule, len(rules))}
+	for i, rule := range rules {
+		result.Rules[i] = AssignmentRule{
+			Name: rule.Name,
+			When: AssignmentCondition{
+				Repository:   rule.When.Repository,
+				TargetBranch: rule.When.TargetBranch,
+				Labels:       rule.When.Labels,
+				MinLines:     rule.When.MinLines,
+				MaxLines:     rule.When.MaxLines,
+			},
+			Then: AssignmentAction{
+				Reviewers:      rule.Reviewers,
+				ExtraTeam:      rule.ExtraTeam,
+				ExtraReviewers: rule.ExtraReviewers,
+			},
+		}
+	}
+	return result
+}
+
+// RuleFiring - правило, сработавшее при назначении ревьюверов PR; assigned_reviewers -
+// ревьюверы из extra_team, назначенные по нему
+type RuleFiring struct {
+	TeamName          string           `json:"team_name"`
+	Rule              string           `json:"rule"`
+	Then              AssignmentAction `json:"then"`
+	AssignedReviewers []string         `json:"assigned_reviewers"`
+	Source            string           `json:"source"`
+	FiredAt           time.Time        `json:"fired_at"`
+}
+
+func RuleFiringsFromDomain(firings []domain.RuleFiring) []RuleFiring {
+	result := make([]RuleFiring, len(firings))
+	for i, f := range firings {
+		result[i] = RuleFiring{
+			TeamName: f.TeamName,
+			Rule:     f.RuleName,
+			Then: AssignmentAction{
+				Reviewers:      f.Reviewers,
+				ExtraTeam:      f.ExtraTeam,
+				ExtraReviewers: f.ExtraReviewers,
+			},
+			AssignedReviewers: f.AssignedReviewers,
+			Source:            f.Source,
+			FiredAt:           f.FiredAt,
+		}
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"strings"
+
+	"avito/internal/domain"
+)
+
+const maxCodeOwnersSize = 64 << 10
+
+// TeamCodeOwnersRequest - загрузка файла CODEOWNERS команды; файл заменяется целиком
+type TeamCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+	Content  string `json:"content"`
+}
+
+func (r *TeamCodeOwnersRequest) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	if strings.TrimSpace(r.Content) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "content cannot be empty")
+	}
+	if len(r.Content) > maxCodeOwnersSize {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "content too large (max 64KB)")
+	}
+	return nil
+}
+
+type DeleteCodeOwnersRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+func (r *DeleteCodeOwnersRequest) Validate() error {
+	return ValidateTeamName(r.TeamName)
+}
+
+// TeamCodeOwnersResponse - файл CODEOWNERS команды и разобранные из него правила
+type TeamCodeOwnersResponse struct {
+	TeamName string               `json:"team_name"`
+	Content  string               `json:"content"`
+	Rules    []CodeOwnersRuleInfo `json:"rules"`
+}
+
+// CodeOwnersRuleInfo - правило CODEOWNERS; line - номер строки в загруженном файле
+type CodeOwnersRuleInfo struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+func CodeOwnersFromDomain(teamName, content string, owners *domain.CodeOwners) TeamCodeOwnersResponse {
+	rules := make([]CodeOwnersRuleInfo, len(owners.Rules))
+	for i, rule := range owners.Rules {
+		rules[i] = CodeOwnersRuleInfo{
+			Line:    rule.Line,
+			Pattern: rule.Pattern,
+			Owners:  rule.Owners,
+		}
+	}
+	return TeamCodeOwnersResponse{TeamName: teamName, Content: content, Rules: rules}
+}
@@ -0,0 +1,220 @@
+package dto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"avito/internal/domain"
+)
+
+// GitHubPullRequestEvent - используемая часть payload события pull_request
+type GitHubPullRequestEvent struct {
+	Action      string            `json:"action"`
+	Number      int               `json:"number"`
+	PullRequest GitHubPullRequest `json:"pull_request"`
+	Repository  GitHubRepository  `json:"repository"`
+}
+
+type GitHubPullRequest struct {
+	Title        string        `json:"title"`
+	State        string        `json:"state"`
+	Draft        bool          `json:"draft"`
+	Merged       bool          `json:"merged"`
+	User         GitHubUser    `json:"user"`
+	HTMLURL      string        `json:"html_url"`
+	Head         GitHubRef     `json:"head"`
+	Base         GitHubRef     `json:"base"`
+	Labels       []GitHubLabel `json:"labels"`
+	Additions    int           `json:"additions"`
+	Deletions    int           `json:"deletions"`
+	ChangedFiles int           `json:"changed_files"`
+}
+
+type GitHubRef struct {
+	Ref string `json:"ref"`
+}
+
+type GitHubLabel struct {
+	Name string `json:"name"`
+}
+
+type GitHubUser struct {
+	Login string `json:"login"`
+}
+
+type GitHubRepository struct {
+	FullName string `json:"full_name"`
+}
+
+// IntegrationUserMappingRequest связывает логин во внешней системе с пользователем
+type IntegrationUserMappingRequest struct {
+	Login  string `json:"login"`
+	UserID string `json:"user_id"`
+}
+
+func (r *IntegrationUserMappingRequest) Validate() error {
+	if err := ValidateExternalLogin(r.Login); err != nil {
+		return err
+	}
+	return ValidateUserID(r.UserID)
+}
+
+func (r *IntegrationUserMappingRequest) ToDomain(provider string) *domain.IntegrationUserMapping {
+	return &domain.IntegrationUserMapping{
+		Provider:      provider,
+		ExternalLogin: NormalizeExternalLogin(r.Login),
+		UserID:        r.UserID,
+	}
+}
+
+type DeleteIntegrationUserMappingRequest struct {
+	Login string `json:"login"`
+}
+
+func (r *DeleteIntegrationUserMappingRequest) Validate() error {
+	return ValidateExternalLogin(r.Login)
+}
+
+func ValidateExternalLogin(login string) error {
+	if strings.TrimSpace(login) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "login cannot be empty")
+	}
+	if len(login) > 255 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "login too long (max 255 characters)")
+	}
+	return nil
+}
+
+// NormalizeExternalLogin приводит логин к виду, в котором он хранится: логины GitHub не зависят от регистра
+func NormalizeExternalLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+type IntegrationUserMappingResponse struct {
+	Login     string    `json:"login"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func IntegrationUserMappingsFromDomain(mappings []domain.IntegrationUserMapping) []IntegrationUserMappingResponse {
+	result := make([]IntegrationUserMappingResponse, len(mappings))
+	for i, m := range mappings {
+		result[i] = IntegrationUserMappingResponse{
+			Login:     m.ExternalLogin,
+			UserID:    m.UserID,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+	return result
+}
+
+// IntegrationEventResponse - ответ на входящее событие внешней системы
+type IntegrationEventResponse struct {
+	Status string               `json:"status"`
+	Action string               `json:"action,omitempty"`
+	Reason string               `json:"reason,omitempty"`
+	PR     *PullRequestResponse `json:"pr,omitempty"`
+}
+
+func IntegrationEventFromDomain(result *domain.IntegrationResult) IntegrationEventResponse {
+	if result.PR == nil {
+		return IntegrationEventResponse{Status: "ignored", Action: result.Action, Reason: result.Reason}
+	}
+	pr := PRFromDomain(result.PR)
+	return IntegrationEventResponse{Status: "processed", Action: result.Action, PR: &pr}
+}
+
+func (e *GitHubPullRequestEvent) Validate() error {
+	if e.Number <= 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "pull request number is required")
+	}
+	if strings.TrimSpace(e.Repository.FullName) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "repository full_name is required")
+	}
+	return nil
+}
+
+// ToCommand переводит событие pull_request в действие над PR
+func (e *GitHubPullRequestEvent) ToCommand() *domain.IntegrationCommand {
+	cmd := &domain.IntegrationCommand{
+		Provider:      domain.IntegrationGitHub,
+		PullRequestID: ExternalPullRequestID("gh", e.Repository.FullName, e.Number),
+		Name:          truncate(e.PullRequest.Title, maxPullRequestNameLength),
+		AuthorLogin:   NormalizeExternalLogin(e.PullRequest.User.Login),
+		Draft:         e.PullRequest.Draft,
+	}
+
+	labels := make([]string, len(e.PullRequest.Labels))
+	for i, label := range e.PullRequest.Labels {
+		labels[i] = label.Name
+	}
+	cmd.Metadata = externalMetadata(PullRequestMetadata{
+		Repository:        e.Repository.FullName,
+		SourceBranch:      e.PullRequest.Head.Ref,
+		TargetBranch:      e.PullRequest.Base.Ref,
+		URL:               e.PullRequest.HTMLURL,
+		Labels:            labels,
+		Additions:         e.PullRequest.Additions,
+		Deletions:         e.PullRequest.Deletions,
+		ChangedFilesCount: e.PullRequest.ChangedFiles,
+	})
+
+	switch e.Action {
+	case "opened":
+		cmd.Action = domain.IntegrationActionOpen
+	case "ready_for_review":
+		cmd.Action = domain.IntegrationActionReady
+	case "reopened":
+		cmd.Action = domain.IntegrationActionReopen
+	case "closed":
+		if e.PullRequest.Merged {
+			cmd.Action = domain.IntegrationActionMerge
+		} else {
+			cmd.Action = domain.IntegrationActionClose
+		}
+	default:
+		cmd.Reason = fmt.Sprintf("pull_request action %q is not handled", e.Action)
+	}
+	return cmd
+}
+
+// maxExternalRepositoryLength - сколько символов пути репозитория попадает в id PR
+const maxExternalRepositoryLength = 200
+
+// ExternalPullRequestID строит id PR из репозитория и номера во внешней системе: символы,
+// недопустимые в id, заменяются на '-', поэтому к id добавляется хэш исходного пути -
+// иначе acme/web-app и acme-web/app дали бы один и тот же id
+func ExternalPullRequestID(prefix, repository string, number int) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('-')
+	for _, r := range truncate(repository, maxExternalRepositoryLength) {
+		if r < utf8.RuneSelf && idRegex.MatchString(string(r)) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	sum := sha256.Sum256([]byte(repository))
+	b.WriteByte('-')
+	b.WriteString(hex.EncodeToString(sum[:4]))
+	b.WriteByte('-')
+	b.WriteString(strconv.Itoa(number))
+	return b.String()
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	// Не режем многобайтовый символ посередине
+	for max > 0 && !utf8.RuneStart(s[max]) {
+		max--
+	}
+	return s[:max]
+}
@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// GitLabMergeRequestEvent - используемая часть payload Merge Request Hook
+type GitLabMergeRequestEvent struct {
+	ObjectKind       string                  `json:"object_kind"`
+	User             GitLabUser              `json:"user"`
+	Project          GitLabProject           `json:"project"`
+	ObjectAttributes GitLabMergeRequest      `json:"object_attributes"`
+	Labels           []GitLabLabel           `json:"labels"`
+	Changes          GitLabMergeRequestDelta `json:"changes"`
+}
+
+type GitLabUser struct {
+	Username string `json:"username"`
+}
+
+type GitLabProject struct {
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+type GitLabMergeRequest struct {
+	IID          int    `json:"iid"`
+	Title        string `json:"title"`
+	State        string `json:"state"`
+	Action       string `json:"action"`
+	Draft        bool   `json:"draft"`
+	URL          string `json:"url"`
+	SourceBranch string `json:"source_branch"`
+	TargetBranch string `json:"target_branch"`
+	// WorkInProgress - прежнее название draft в старых версиях GitLab
+	WorkInProgress bool `json:"work_in_progress"`
+}
+
+// GitLabMergeRequestDelta - изменённые атрибуты в событии update
+type GitLabLabel struct {
+	Title string `json:"title"`
+}
+
+type GitLabMergeRequestDelta struct {
+	Draft *GitLabBoolChange `json:"draft,omitempty"`
+}
+
+type GitLabBoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+func (e *GitLabMergeRequestEvent) Validate() error {
+	if e.ObjectKind != "merge_request" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "object_kind must be merge_request")
+	}
+	if e.ObjectAttributes.IID <= 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "merge request iid is required")
+	}
+	if strings.TrimSpace(e.Project.PathWithNamespace) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "project path_with_namespace is required")
+	}
+	return nil
+}
+
+// ToCommand переводит Merge Request Hook в действие над PR. Автором считается пользователь,
+// открывший MR: для остальных действий автор не нужен.
+func (e *GitLabMergeRequestEvent) ToCommand() *domain.IntegrationCommand {
+	mr := e.ObjectAttributes
+	cmd := &domain.IntegrationCommand{
+		Provider:      domain.IntegrationGitLab,
+		PullRequestID: ExternalPullRequestID("gl", e.Project.PathWithNamespace, mr.IID),
+		Project:       NormalizeProjectPath(e.Project.PathWithNamespace),
+		Name:          truncate(mr.Title, maxPullRequestNameLength),
+		AuthorLogin:   NormalizeExternalLogin(e.User.Username),
+		Draft:         mr.Draft || mr.WorkInProgress,
+	}
+
+	// Размер MR в хуке не передаётся
+	labels := make([]string, len(e.Labels))
+	for i, label := range e.Labels {
+		labels[i] = label.Title
+	}
+	cmd.Metadata = externalMetadata(PullRequestMetadata{
+		Repository:   e.Project.PathWithNamespace,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		URL:          mr.URL,
+		Labels:       labels,
+	})
+
+	switch mr.Action {
+	case "open":
+		cmd.Action = domain.IntegrationActionOpen
+	case "merge":
+		cmd.Action = domain.IntegrationActionMerge
+	case "close":
+		cmd.Action = domain.IntegrationActionClose
+	case "reopen":
+		cmd.Action = domain.IntegrationActionReopen
+	case "update":
+		if e.Changes.Draft != nil && e.Changes.Draft.Previous && !e.Changes.Draft.Current {
+			cmd.Action = domain.IntegrationActionReady
+		} else {
+			cmd.Reason = "merge request update does not change review state"
+		}
+	default:
+		cmd.Reason = fmt.Sprintf("merge request action %q is not handled", mr.Action)
+	}
+	return cmd
+}
+
+// NormalizeProjectPath приводит путь проекта к виду, в котором он хранится: пути GitLab не зависят от регистра
+func NormalizeProjectPath(path string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(path), "/"))
+}
+
+// IntegrationProjectMappingRequest направляет PR проекта на ревью команде
+type IntegrationProjectMappingRequest struct {
+	Project  string `json:"project"`
+	TeamName string `json:"team_name"`
+}
+
+func (r *IntegrationProjectMappingRequest) Validate() error {
+	if err := ValidateProjectPath(r.Project); err != nil {
+		return err
+	}
+	return ValidateTeamName(r.TeamName)
+}
+
+func (r *IntegrationProjectMappingRequest) ToDomain(provider string) *domain.IntegrationProjectMapping {
+	return &domain.IntegrationProjectMapping{
+		Provider: provider,
+		Project:  NormalizeProjectPath(r.Project),
+		TeamName: r.TeamName,
+	}
+}
+
+type DeleteIntegrationProjectMappingRequest struct {
+	Project string `json:"project"`
+}
+
+func (r *DeleteIntegrationProjectMappingRequest) Validate() error {
+	return ValidateProjectPath(r.Project)
+}
+
+func ValidateProjectPath(path string) error {
+	if NormalizeProjectPath(path) == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "project cannot be empty")
+	}
+	if len(path) > 255 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "project too long (max 255 characters)")
+	}
+	return nil
+}
+
+type IntegrationProjectMappingResponse struct {
+	Project   string    `json:"project"`
+	TeamName  string    `json:"team_name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func IntegrationProjectMappingsFromDomain(mappings []domain.IntegrationProjectMapping) []IntegrationProjectMappingResponse {
+	result := make([]IntegrationProjectMappingResponse, len(mappings))
+	for i, m := range mappings {
+		result[i] = IntegrationProjectMappingResponse{
+			Project:   m.Project,
+			TeamName:  m.TeamName,
+			CreatedAt: m.CreatedAt,
+		}
+	}
+	return result
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
@@ -11,6 +12,13 @@ type PullRequestCreateRequest struct {
 	ID       string `json:"pull_request_id"`
 	Name     string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
+	// Strategy переопределяет стратегию выбора ревьюверов команды для этого запроса
+	Strategy string `json:"strategy,omitempty"`
+	// Draft создаёт черновик: ревьюверы назначаются при переводе в OPEN
+	Draft bool `json:"draft,omitempty"`
+	// ChangedFiles - затронутые пути; владельцы путей по CODEOWNERS команды назначаются в первую очередь
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	PullRequestMetadata
 }
 
 func (r *PullRequestCreateRequest) Validate() error {
@@ -23,22 +31,76 @@ func (r *PullRequestCreateRequest) Validate() error {
 	if err := ValidateUserID(r.AuthorID); err != nil {
 		return err
 	}
+	if err := ValidateReviewerStrategy(r.Strategy); err != nil {
+		return err
+	}
+	if err := ValidateChangedFiles(r.ChangedFiles); err != nil {
+		return err
+	}
+	return r.PullRequestMetadata.Validate()
+}
+
+const (
+	maxChangedFiles      = 1000
+	maxChangedFileLength = 1024
+)
+
+func ValidateChangedFiles(paths []string) error {
+	if len(paths) > maxChangedFiles {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "too many changed_files (max 1000)")
+	}
+	for i, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("changed_files[%d] cannot be empty", i))
+		}
+		if len(path) > maxChangedFileLength {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("changed_files[%d] too long (max 1024 characters)", i))
+		}
+	}
 	return nil
 }
 
 // MergePRRequest - запрос на мерж PR
 type MergePRRequest struct {
 	PullRequestID string `json:"pull_request_id"`
+	// Force мержит без нужных одобрений, доступно только администраторам
+	Force           bool  `json:"force,omitempty"`
+	ExpectedVersion int64 `json:"expected_version,omitempty"`
 }
 
 func (r *MergePRRequest) Validate() error {
-	return ValidatePullRequestID(r.PullRequestID)
+	if err := ValidatePullRequestID(r.PullRequestID); err != nil {
+		return err
+	}
+	return ValidateExpectedVersion(r.ExpectedVersion)
+}
+
+// PRTransitionRequest - запрос на смену статуса PR: ready, close, reopen.
+// Strategy учитывается только переходами, которые назначают ревьюверов.
+type PRTransitionRequest struct {
+	PullRequestID   string `json:"pull_request_id"`
+	Strategy        string `json:"strategy,omitempty"`
+	ExpectedVersion int64  `json:"expected_version,omitempty"`
+}
+
+func (r *PRTransitionRequest) Validate() error {
+	if err := ValidatePullRequestID(r.PullRequestID); err != nil {
+		return err
+	}
+	if err := ValidateReviewerStrategy(r.Strategy); err != nil {
+		return err
+	}
+	return ValidateExpectedVersion(r.ExpectedVersion)
 }
 
 // ReassignReviewerRequest - запрос на переназначение ревьюера
 type ReassignReviewerRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldReviewerID string `json:"old_user_id"`
+	Strategy      string `json:"strategy,omitempty"`
+	// Force разрешает заменить ревьювера, который уже одобрил PR
+	Force           bool  `json:"force,omitempty"`
+	ExpectedVersion int64 `json:"expected_version,omitempty"`
 }
 
 // Validate проверяет корректность данных в запросе
@@ -49,7 +111,48 @@ func (r *ReassignReviewerRequest) Validate() error {
 	if err := ValidateUserID(r.OldReviewerID); err != nil {
 		return err
 	}
-	return nil
+	if err := ValidateReviewerStrategy(r.Strategy); err != nil {
+		return err
+	}
+	return ValidateExpectedVersion(r.ExpectedVersion)
+}
+
+const maxReviewBodyLength = 10000
+
+// SubmitReviewRequest - вердикт ревьювера по PR
+type SubmitReviewRequest struct {
+	PullRequestID   string `json:"pull_request_id"`
+	ReviewerID      string `json:"user_id"`
+	State           string `json:"state"`
+	Body            string `json:"body,omitempty"`
+	ExpectedVersion int64  `json:"expected_version,omitempty"`
+}
+
+func (r *SubmitReviewRequest) Validate() error {
+	if err := ValidatePullRequestID(r.PullRequestID); err != nil {
+		return err
+	}
+	if err := ValidateUserID(r.ReviewerID); err != nil {
+		return err
+	}
+	switch r.State {
+	case domain.ReviewStateApproved, domain.ReviewStateChangesRequested, domain.ReviewStateCommented:
+	default:
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "state must be one of APPROVED, CHANGES_REQUESTED, COMMENTED")
+	}
+	if len(r.Body) > maxReviewBodyLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "body too long (max 10000 characters)")
+	}
+	return ValidateExpectedVersion(r.ExpectedVersion)
+}
+
+func (r *SubmitReviewRequest) ToDomain() *domain.ReviewVerdict {
+	return &domain.ReviewVerdict{
+		PullRequestID: r.PullRequestID,
+		ReviewerID:    r.ReviewerID,
+		State:         r.State,
+		Body:          r.Body,
+	}
 }
 
 // PullRequestResponse - ответ с данными PR
@@ -61,33 +164,155 @@ type PullRequestResponse struct {
 	AssignedReviewers []string   `json:"assigned_reviewers"`
 	CreatedAt         time.Time  `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time `json:"closedAt,omitempty"`
+	// PendingReviewers - места ревьюверов, ожидающие освобождения лимита у участников команды
+	PendingReviewers int `json:"pending_reviewers,omitempty"`
+	// AssignmentDecisions - на основании чего выбраны ревьюверы в этом запросе
+	AssignmentDecisions []ReviewerDecision `json:"assignment_decisions,omitempty"`
+	// FiredRules - правила назначения команды, сработавшие в этом запросе
+	FiredRules []RuleFiring `json:"fired_rules,omitempty"`
+	// Reviews - последний вердикт каждого назначенного ревьювера
+	Reviews []ReviewState `json:"reviews,omitempty"`
+	// MergeForced - PR смержен администратором без нужных одобрений
+	MergeForced   bool   `json:"merge_forced,omitempty"`
+	MergeForcedBy string `json:"merge_forced_by,omitempty"`
+	// ReviewTeam - команда ревьюверов, если она отличается от команды автора
+	ReviewTeam   string   `json:"review_team,omitempty"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// Version - версия PR, она же ETag ответа; передаётся в If-Match или expected_version изменяющих запросов
+	Version int64 `json:"version"`
+	PullRequestMetadata
+}
+
+// ReviewState - текущее состояние ревьювера; для PENDING body и submitted_at не заполняются
+type ReviewState struct {
+	UserID      string     `json:"user_id"`
+	State       string     `json:"state"`
+	Body        string     `json:"body,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+}
+
+// Правило, по которому выбран ревьювер
+const (
+	DecisionRuleCodeOwners = "codeowners"
+	DecisionRuleStrategy   = "strategy"
+	DecisionRuleFallback   = "fallback"
+	DecisionRuleAssignment = "rule"
+)
+
+// ReviewerDecision - выбранный ревьювер и его загрузка (открытые ревью) на момент выбора.
+// Rule - codeowners (владелец затронутого пути, совпавшая строка в codeowners_rule),
+// strategy (стратегия команды), fallback (запасная команда) или rule (правило назначения assignment_rule).
+type ReviewerDecision struct {
+	UserID         string              `json:"user_id"`
+	Rule           string              `json:"rule"`
+	Strategy       string              `json:"strategy"`
+	OpenReviews    int                 `json:"open_reviews"`
+	FallbackTeam   string              `json:"fallback_team,omitempty"`
+	CodeOwnersRule *CodeOwnersRuleInfo `json:"codeowners_rule,omitempty"`
+	AssignmentRule string              `json:"assignment_rule,omitempty"`
 }
 
 // ToDomain преобразует DTO в domain модель
 func (r *PullRequestCreateRequest) ToDomain() *domain.PullRequest {
+	status := domain.PRStatusOpen
+	if r.Draft {
+		status = domain.PRStatusDraft
+	}
+	metadata := r.PullRequestMetadata.ToDomain()
+	metadata.ChangedFilesCount = max(metadata.ChangedFilesCount, len(r.ChangedFiles))
 	return &domain.PullRequest{
 		ID:                r.ID,
 		Name:              r.Name,
 		AuthorID:          r.AuthorID,
-		Status:            domain.PRStatusOpen,
+		Status:            status,
 		AssignedReviewers: []string{},
+		ChangedFiles:      r.ChangedFiles,
 		CreatedAt:         time.Now(),
+		PRMetadata:        metadata,
 	}
 }
 
 // PRFromDomain преобразует domain модель в DTO
 func PRFromDomain(pr *domain.PullRequest) PullRequestResponse {
 	return PullRequestResponse{
-		ID:                pr.ID,
-		Name:              pr.Name,
-		AuthorID:          pr.AuthorID,
-		Status:            pr.Status,
-		AssignedReviewers: pr.AssignedReviewers,
-		CreatedAt:         pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
+		ID:                  pr.ID,
+		Name:                pr.Name,
+		AuthorID:            pr.AuthorID,
+		Status:              pr.Status,
+		AssignedReviewers:   pr.AssignedReviewers,
+		CreatedAt:           pr.CreatedAt,
+		MergedAt:            pr.MergedAt,
+		ClosedAt:            pr.ClosedAt,
+		AssignmentDecisions: decisionsFromDomain(pr.Decisions),
+		FiredRules:          firingsFromDomain(pr.FiredRules),
+		Reviews:             reviewsFromDomain(pr.Reviews),
+		MergeForced:         pr.MergeForced,
+		MergeForcedBy:       pr.MergeForcedBy,
+		ReviewTeam:          pr.ReviewTeam,
+		ChangedFiles:        pr.ChangedFiles,
+		Version:             pr.Version,
+		PullRequestMetadata: metadataFromDomain(pr.PRMetadata),
 	}
 }
 
+func reviewsFromDomain(reviews []domain.ReviewVerdict) []ReviewState {
+	if len(reviews) == 0 {
+		return nil
+	}
+	result := make([]ReviewState, len(reviews))
+	for i, v := range reviews {
+		result[i] = ReviewState{
+			UserID: v.ReviewerID,
+			State:  v.State,
+			Body:   v.Body,
+		}
+		if v.State != domain.ReviewStatePending {
+			submittedAt := v.CreatedAt
+			result[i].SubmittedAt = &submittedAt
+		}
+	}
+	return result
+}
+
+func firingsFromDomain(firings []domain.RuleFiring) []RuleFiring {
+	if len(firings) == 0 {
+		return nil
+	}
+	return RuleFiringsFromDomain(firings)
+}
+
+func decisionsFromDomain(decisions []domain.ReviewerDecision) []ReviewerDecision {
+	if len(decisions) == 0 {
+		return nil
+	}
+	result := make([]ReviewerDecision, len(decisions))
+	for i, d := range decisions {
+		result[i] = ReviewerDecision{
+			UserID:       d.ReviewerID,
+			Strategy:     d.Strategy,
+			OpenReviews:  d.OpenReviews,
+			Rule:         DecisionRuleStrategy,
+			FallbackTeam: d.FallbackTeam,
+		}
+		switch {
+		case d.CodeOwnersRule != nil:
+			result[i].Rule = DecisionRuleCodeOwners
+			result[i].CodeOwnersRule = &CodeOwnersRuleInfo{
+				Line:    d.CodeOwnersRule.Line,
+				Pattern: d.CodeOwnersRule.Pattern,
+				Owners:  d.CodeOwnersRule.Owners,
+			}
+		case d.FallbackTeam != "":
+			result[i].Rule = DecisionRuleFallback
+		case d.AssignmentRule != "":
+			result[i].Rule = DecisionRuleAssignment
+			result[i].AssignmentRule = d.AssignmentRule
+		}
+	}
+	return result
+}
+
 func ValidatePullRequestID(id string) error {
 	if strings.TrimSpace(id) == "" {
 		return domain.NewAppError(domain.ErrCodeInvalidInput, "pull_request_id cannot be empty")
@@ -101,11 +326,21 @@ func ValidatePullRequestID(id string) error {
 	return nil
 }
 
+// ValidateExpectedVersion - 0 означает, что версия не проверяется
+func ValidateExpectedVersion(version int64) error {
+	if version < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "expected_version cannot be negative")
+	}
+	return nil
+}
+
+const maxPullRequestNameLength = 500
+
 func ValidatePullRequestName(name string) error {
 	if strings.TrimSpace(name) == "" {
 		return domain.NewAppError(domain.ErrCodeInvalidInput, "pull_request_name cannot be empty")
 	}
-	if len(name) > 500 {
+	if len(name) > maxPullRequestNameLength {
 		return domain.NewAppError(domain.ErrCodeInvalidInput, "pull_request_name too long (max 500 characters)")
 	}
 	return nil
@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// PullRequestListResponse - страница списка PR; next_cursor передаётся в cursor следующего запроса
+type PullRequestListResponse struct {
+	PullRequests []PullRequestResponse `json:"pull_requests"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
+}
+
+func PRPageFromDomain(page *domain.PRPage) PullRequestListResponse {
+	prs := make([]PullRequestResponse, len(page.PullRequests))
+	for i := range page.PullRequests {
+		prs[i] = PRFromDomain(&page.PullRequests[i])
+	}
+	return PullRequestListResponse{PullRequests: prs, NextCursor: EncodePRCursor(page.Next)}
+}
+
+type prCursor struct {
+	SortBy string    `json:"s"`
+	Value  time.Time `json:"v"`
+	ID     string    `json:"id"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// EncodePRCursor упаковывает позицию в списке в непрозрачную строку; nil - пустая строка
+func EncodePRCursor(cursor *domain.PRCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	data, _ := json.Marshal(prCursor{SortBy: cursor.SortBy, Value: cursor.Value, ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func DecodePRCursor(raw string) (*domain.PRCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var cursor prCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, errInvalidCursor
+	}
+	if cursor.SortBy != domain.PRSortCreatedAt && cursor.SortBy != domain.PRSortMergedAt {
+		return nil, errInvalidCursor
+	}
+	return &domain.PRCursor{SortBy: cursor.SortBy, Value: cursor.Value, ID: cursor.ID}, nil
+}
@@ -0,0 +1,253 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"avito/internal/domain"
+)
+
+const (
+	maxRefLength     = 255
+	maxPRURLLength   = 2048
+	maxLabels        = 50
+	maxLabelLength   = 100
+	maxLinesPerField = 10_000_000
+)
+
+// PullRequestMetadata - сведения о PR из системы контроля версий, общие для запросов и ответов
+type PullRequestMetadata struct {
+	Repository   string   `json:"repository,omitempty"`
+	SourceBranch string   `json:"source_branch,omitempty"`
+	TargetBranch string   `json:"target_branch,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	Labels       []string `json:"labels,omitempty"`
+	Additions    int      `json:"additions"`
+	Deletions    int      `json:"deletions"`
+	// ChangedFilesCount - число изменённых файлов; не меньше длины changed_files
+	ChangedFilesCount int `json:"changed_files_count"`
+}
+
+func (m *PullRequestMetadata) Validate() error {
+	if err := validateRef("repository", m.Repository); err != nil {
+		return err
+	}
+	if err := validateRef("source_branch", m.SourceBranch); err != nil {
+		return err
+	}
+	if err := validateRef("target_branch", m.TargetBranch); err != nil {
+		return err
+	}
+	if err := ValidatePRURL(m.URL); err != nil {
+		return err
+	}
+	if err := ValidateLabels(m.Labels); err != nil {
+		return err
+	}
+	if err := validateCount("additions", m.Additions); err != nil {
+		return err
+	}
+	if err := validateCount("deletions", m.Deletions); err != nil {
+		return err
+	}
+	return validateCount("changed_files_count", m.ChangedFilesCount)
+}
+
+func (m *PullRequestMetadata) ToDomain() domain.PRMetadata {
+	return domain.PRMetadata{
+		Repository:        m.Repository,
+		SourceBranch:      m.SourceBranch,
+		TargetBranch:      m.TargetBranch,
+		URL:               m.URL,
+		Labels:            normalizeLabels(m.Labels),
+		Additions:         m.Additions,
+		Deletions:         m.Deletions,
+		ChangedFilesCount: m.ChangedFilesCount,
+	}
+}
+
+func metadataFromDomain(m domain.PRMetadata) PullRequestMetadata {
+	return PullRequestMetadata{
+		Repository:        m.Repository,
+		SourceBranch:      m.SourceBranch,
+		TargetBranch:      m.TargetBranch,
+		URL:               m.URL,
+		Labels:            m.Labels,
+		Additions:         m.Additions,
+		Deletions:         m.Deletions,
+		ChangedFilesCount: m.ChangedFilesCount,
+	}
+}
+
+// UpdatePullRequestRequest - частичное обновление PR: незаданные поля не меняются,
+// labels заменяет список целиком ([] снимает все метки)
+type UpdatePullRequestRequest struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	Name              *string  `json:"pull_request_name,omitempty"`
+	Repository        *string  `json:"repository,omitempty"`
+	SourceBranch      *string  `json:"source_branch,omitempty"`
+	TargetBranch      *string  `json:"target_branch,omitempty"`
+	URL               *string  `json:"url,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+	Additions         *int     `json:"additions,omitempty"`
+	Deletions         *int     `json:"deletions,omitempty"`
+	ChangedFilesCount *int     `json:"changed_files_count,omitempty"`
+	ExpectedVersion   int64    `json:"expected_version,omitempty"`
+}
+
+func (r *UpdatePullRequestRequest) Validate() error {
+	if err := ValidatePullRequestID(r.PullRequestID); err != nil {
+		return err
+	}
+	if err := ValidateExpectedVersion(r.ExpectedVersion); err != nil {
+		return err
+	}
+	if r.Name != nil {
+		if err := ValidatePullRequestName(*r.Name); err != nil {
+			return err
+		}
+	}
+
+	// Незаданные поля проверяются как пустые значения, которые всегда допустимы
+	metadata := PullRequestMetadata{
+		Repository:        deref(r.Repository),
+		SourceBranch:      deref(r.SourceBranch),
+		TargetBranch:      deref(r.TargetBranch),
+		URL:               deref(r.URL),
+		Labels:            r.Labels,
+		Additions:         deref(r.Additions),
+		Deletions:         deref(r.Deletions),
+		ChangedFilesCount: deref(r.ChangedFilesCount),
+	}
+	return metadata.Validate()
+}
+
+func deref[T any](value *T) T {
+	var zero T
+	if value == nil {
+		return zero
+	}
+	return *value
+}
+
+// Apply переносит заданные в запросе поля в PR
+func (r *UpdatePullRequestRequest) Apply(pr *domain.PullRequest) {
+	if r.Name != nil {
+		pr.Name = *r.Name
+	}
+	if r.Repository != nil {
+		pr.Repository = *r.Repository
+	}
+	if r.SourceBranch != nil {
+		pr.SourceBranch = *r.SourceBranch
+	}
+	if r.TargetBranch != nil {
+		pr.TargetBranch = *r.TargetBranch
+	}
+	if r.URL != nil {
+		pr.URL = *r.URL
+	}
+	if r.Labels != nil {
+		pr.Labels = normalizeLabels(r.Labels)
+	}
+	if r.Additions != nil {
+		pr.Additions = *r.Additions
+	}
+	if r.Deletions != nil {
+		pr.Deletions = *r.Deletions
+	}
+	if r.ChangedFilesCount != nil {
+		pr.ChangedFilesCount = max(*r.ChangedFilesCount, len(pr.ChangedFiles))
+	}
+}
+
+func validateRef(field, value string) error {
+	if len(value) > maxRefLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("%s too long (max %d characters)", field, maxRefLength))
+	}
+	return nil
+}
+
+func ValidatePRURL(rawURL string) error {
+	if rawURL == "" {
+		return nil
+	}
+	if len(rawURL) > maxPRURLLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "url too long (max 2048 characters)")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// ValidateLabels проверяет метки; метки сравниваются без учёта регистра
+func ValidateLabels(labels []string) error {
+	if len(labels) > maxLabels {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("too many labels (max %d)", maxLabels))
+	}
+	seen := make(map[string]struct{}, len(labels))
+	for _, label := range labels {
+		normalized := strings.ToLower(strings.TrimSpace(label))
+		if normalized == "" {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "label cannot be empty")
+		}
+		if len(normalized) > maxLabelLength {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("label too long (max %d characters)", maxLabelLength))
+		}
+		if _, ok := seen[normalized]; ok {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("duplicate label %s", normalized))
+		}
+		seen[normalized] = struct{}{}
+	}
+	return nil
+}
+
+// normalizeLabels приводит метки к нижнему регистру, чтобы фильтры и правила не зависели от написания
+func normalizeLabels(labels []string) []string {
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = strings.ToLower(strings.TrimSpace(label))
+	}
+	return result
+}
+
+func validateCount(field string, value int) error {
+	if value < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("%s cannot be negative", field))
+	}
+	if value > maxLinesPerField {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("%s too large", field))
+	}
+	return nil
+}
+
+// externalMetadata приводит метаданные из события внешней системы к ограничениям API:
+// длинные значения обрезаются, недопустимые URL и повторные метки отбрасываются
+func externalMetadata(m PullRequestMetadata) domain.PRMetadata {
+	metadata := domain.PRMetadata{
+		Repository:        truncate(m.Repository, maxRefLength),
+		SourceBranch:      truncate(m.SourceBranch, maxRefLength),
+		TargetBranch:      truncate(m.TargetBranch, maxRefLength),
+		Labels:            []string{},
+		Additions:         min(max(m.Additions, 0), maxLinesPerField),
+		Deletions:         min(max(m.Deletions, 0), maxLinesPerField),
+		ChangedFilesCount: min(max(m.ChangedFilesCount, 0), maxLinesPerField),
+	}
+	if ValidatePRURL(m.URL) == nil {
+		metadata.URL = m.URL
+	}
+
+	seen := make(map[string]struct{}, len(m.Labels))
+	for _, label := range normalizeLabels(m.Labels) {
+		label = truncate(label, maxLabelLength)
+		if _, ok := seen[label]; ok || label == "" || len(metadata.Labels) == maxLabels {
+			continue
+		}
+		seen[label] = struct{}{}
+		metadata.Labels = append(metadata.Labels, label)
+	}
+	return metadata
+}
@@ -8,12 +8,13 @@ type AssignmentStat struct {
 }
 
 type StatisticsResponse struct {
-	AssignmentsByUser []AssignmentStat `json:"assignments_by_user"`
-	AssignmentsByPR   []AssignmentStat `json:"assignments_by_pr"`
-	TotalPRs          int              `json:"total_prs"`
-	TotalAssignments  int              `json:"total_assignments"`
-	ActiveUsers       int              `json:"active_users"`
-	Teams             int              `json:"teams"`
+	AssignmentsByUser     []AssignmentStat `json:"assignments_by_user"`
+	AssignmentsByPR       []AssignmentStat `json:"assignments_by_pr"`
+	OpenAssignmentsByUser []AssignmentStat `json:"open_assignments_by_user"`
+	TotalPRs              int              `json:"total_prs"`
+	TotalAssignments      int              `json:"total_assignments"`
+	ActiveUsers           int              `json:"active_users"`
+	Teams                 int              `json:"teams"`
 }
 
 func StatisticsFromDomain(stats *domain.Statistics) *StatisticsResponse {
@@ -40,5 +41,13 @@ func StatisticsFromDomain(stats *domain.Statistics) *StatisticsResponse {
 		}
 	}
 
+	response.OpenAssignmentsByUser = make([]AssignmentStat, len(stats.OpenAssignmentsByUser))
+	for i, stat := range stats.OpenAssignmentsByUser {
+		response.OpenAssignmentsByUser[i] = AssignmentStat{
+			ID:    stat.ID,
+			Count: stat.Count,
+		}
+	}
+
 	return response
 }
@@ -16,8 +16,9 @@ var (
 
 // TeamRequest - входящий запрос для создания команды
 type TeamRequest struct {
-	Name    string       `json:"team_name"`
-	Members []TeamMember `json:"members"`
+	Name             string       `json:"team_name"`
+	ReviewerStrategy string       `json:"reviewer_strategy,omitempty"`
+	Members          []TeamMember `json:"members"`
 }
 
 // Validate проверяет корректность данных в запросе
@@ -27,8 +28,9 @@ func (r *TeamRequest) Validate() error {
 
 // TeamResponse - ответ с данными команды
 type TeamResponse struct {
-	TeamName string       `json:"team_name"`
-	Members  []TeamMember `json:"members"`
+	TeamName         string       `json:"team_name"`
+	ReviewerStrategy string       `json:"reviewer_strategy,omitempty"`
+	Members          []TeamMember `json:"members"`
 }
 
 type TeamMember struct {
@@ -45,13 +47,117 @@ type MassDeactivateRequest struct {
 
 // Validate проверяет корректность запроса
 func (r *MassDeactivateRequest) Validate() error {
+	return validateTeamUserIDs(r.TeamName, r.UserIDs)
+}
+
+// AddMembersRequest - запрос на добавление участников в существующую команду
+type AddMembersRequest struct {
+	TeamName string       `json:"team_name"`
+	Members  []TeamMember `json:"members"`
+}
+
+func (r *AddMembersRequest) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	if len(r.Members) == 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "members cannot be empty")
+	}
+	return validateMembers(r.Members)
+}
+
+func (r *AddMembersRequest) ToDomain() []domain.TeamMember {
+	return membersToDomain(r.Members)
+}
+
+// RemoveMembersRequest - запрос на исключение участников из команды
+type RemoveMembersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+func (r *RemoveMembersRequest) Validate() error {
+	return validateTeamUserIDs(r.TeamName, r.UserIDs)
+}
+
+// TransferUserRequest - запрос на перевод пользователя в другую команду
+type TransferUserRequest struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+func (r *TransferUserRequest) Validate() error {
+	if err := ValidateUserID(r.UserID); err != nil {
+		return err
+	}
+	return ValidateTeamName(r.TeamName)
+}
+
+// RenameTeamRequest - запрос на переименование команды
+type RenameTeamRequest struct {
+	TeamName    string `json:"team_name"`
+	NewTeamName string `json:"new_team_name"`
+}
+
+func (r *RenameTeamRequest) Validate() error {
 	if err := ValidateTeamName(r.TeamName); err != nil {
 		return err
 	}
-	if len(r.UserIDs) == 0 {
+	if err := ValidateTeamName(r.NewTeamName); err != nil {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "new_team_name: "+err.Error())
+	}
+	if r.TeamName == r.NewTeamName {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "new_team_name must differ from team_name")
+	}
+	return nil
+}
+
+// DeleteTeamRequest - запрос на удаление команды. open_prs задаёт судьбу её черновиков и открытых PR:
+// close - закрыть, reassign - передать команде reassign_to; без него удаляется только команда без таких PR
+type DeleteTeamRequest struct {
+	TeamName   string `json:"team_name"`
+	OpenPRs    string `json:"open_prs,omitempty"`
+	ReassignTo string `json:"reassign_to,omitempty"`
+}
+
+func (r *DeleteTeamRequest) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	switch r.OpenPRs {
+	case "", domain.OpenPRsClose:
+		if r.ReassignTo != "" {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "reassign_to requires open_prs to be reassign")
+		}
+	case domain.OpenPRsReassign:
+		if err := ValidateTeamName(r.ReassignTo); err != nil {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "reassign_to: "+err.Error())
+		}
+		if r.ReassignTo == r.TeamName {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "reassign_to must differ from team_name")
+		}
+	default:
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "open_prs must be close or reassign")
+	}
+	return nil
+}
+
+// DeleteTeamResponse - итог удаления: закрытые или переданные другой команде PR
+type DeleteTeamResponse struct {
+	TeamName     string   `json:"team_name"`
+	OpenPRs      string   `json:"open_prs,omitempty"`
+	ReassignTo   string   `json:"reassign_to,omitempty"`
+	PullRequests []string `json:"pull_requests"`
+}
+
+func validateTeamUserIDs(teamName string, userIDs []string) error {
+	if err := ValidateTeamName(teamName); err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
 		return domain.NewAppError(domain.ErrCodeInvalidInput, "user_ids cannot be empty")
 	}
-	for i, uid := range r.UserIDs {
+	for i, uid := range userIDs {
 		if err := ValidateUserID(uid); err != nil {
 			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("user_ids[%d]: %s", i, err.Error()))
 		}
@@ -61,18 +167,23 @@ func (r *MassDeactivateRequest) Validate() error {
 
 // ToDomain преобразует DTO в domain модель
 func (r *TeamRequest) ToDomain() *domain.Team {
-	members := make([]domain.TeamMember, len(r.Members))
-	for i, m := range r.Members {
-		members[i] = domain.TeamMember{
+	return &domain.Team{
+		Name:             r.Name,
+		ReviewerStrategy: r.ReviewerStrategy,
+		Members:          membersToDomain(r.Members),
+	}
+}
+
+func membersToDomain(members []TeamMember) []domain.TeamMember {
+	result := make([]domain.TeamMember, len(members))
+	for i, m := range members {
+		result[i] = domain.TeamMember{
 			UserID:   m.UserID,
 			Username: m.Username,
 			IsActive: m.IsActive,
 		}
 	}
-	return &domain.Team{
-		Name:    r.Name,
-		Members: members,
-	}
+	return result
 }
 
 // FromDomain преобразует domain модель в DTO
@@ -86,8 +197,9 @@ func TeamFromDomain(team *domain.Team) TeamResponse {
 		}
 	}
 	return TeamResponse{
-		TeamName: team.Name,
-		Members:  members,
+		TeamName:         team.Name,
+		ReviewerStrategy: team.ReviewerStrategy,
+		Members:          members,
 	}
 }
 
@@ -96,15 +208,23 @@ func ValidateTeamRequest(req *TeamRequest) error {
 		return err
 	}
 
+	if err := ValidateReviewerStrategy(req.ReviewerStrategy); err != nil {
+		return err
+	}
+
 	if len(req.Members) == 0 {
 		return domain.NewAppError(domain.ErrCodeInvalidInput, "team must have at least one member")
 	}
 
-	if len(req.Members) > 200 {
-		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("team has too many members (max 200, got %d)", len(req.Members)))
+	return validateMembers(req.Members)
+}
+
+func validateMembers(members []TeamMember) error {
+	if len(members) > 200 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("team has too many members (max 200, got %d)", len(members)))
 	}
 
-	for i, member := range req.Members {
+	for i, member := range members {
 		if err := ValidateUserID(member.UserID); err != nil {
 			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("member[%d]: %s", i, err.Error()))
 		}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+// TeamFallbacks - запасные команды ревьюверов в порядке приоритета
+type TeamFallbacks struct {
+	TeamName      string   `json:"team_name"`
+	FallbackTeams []string `json:"fallback_teams"`
+}
+
+// Validate проверяет запрос на замену запасных команд; пустой список снимает все запасные команды
+func (r *TeamFallbacks) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	if r.FallbackTeams == nil {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "fallback_teams is required")
+	}
+
+	seen := make(map[string]struct{}, len(r.FallbackTeams))
+	for _, team := range r.FallbackTeams {
+		if err := ValidateTeamName(team); err != nil {
+			return err
+		}
+		if team == r.TeamName {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "team cannot be its own fallback")
+		}
+		if _, ok := seen[team]; ok {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("duplicate fallback team %s", team))
+		}
+		seen[team] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package dto
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+// TeamSettingsResponse - политика назначения ревьюверов команды
+type TeamSettingsResponse struct {
+	TeamName         string `json:"team_name"`
+	MinReviewers     int    `json:"min_reviewers"`
+	MaxReviewers     int    `json:"max_reviewers"`
+	ReviewerStrategy string `json:"reviewer_strategy"`
+	AllowNoReviewers bool   `json:"allow_no_reviewers"`
+	// DefaultMaxOpenReviews - null означает отсутствие лимита
+	DefaultMaxOpenReviews *int   `json:"default_max_open_reviews"`
+	CapacityOverflow      string `json:"capacity_overflow"`
+	RequiredApprovals     int    `json:"required_approvals"`
+	// LargePRLines - порог размера PR (additions + deletions), 0 - правило выключено
+	LargePRLines     int `json:"large_pr_lines"`
+	LargePRReviewers int `json:"large_pr_reviewers"`
+}
+
+// UpdateTeamSettingsRequest - частичное обновление настроек: незаданные поля не меняются
+type UpdateTeamSettingsRequest struct {
+	TeamName         string  `json:"team_name"`
+	MinReviewers     *int    `json:"min_reviewers,omitempty"`
+	MaxReviewers     *int    `json:"max_reviewers,omitempty"`
+	ReviewerStrategy *string `json:"reviewer_strategy,omitempty"`
+	AllowNoReviewers *bool   `json:"allow_no_reviewers,omitempty"`
+	// DefaultMaxOpenReviews: отсутствует - не менять, null - снять лимит
+	DefaultMaxOpenReviews OptionalInt `json:"default_max_open_reviews"`
+	CapacityOverflow      *string     `json:"capacity_overflow,omitempty"`
+	RequiredApprovals     *int        `json:"required_approvals,omitempty"`
+	LargePRLines          *int        `json:"large_pr_lines,omitempty"`
+	LargePRReviewers      *int        `json:"large_pr_reviewers,omitempty"`
+}
+
+// Validate проверяет корректность запроса; согласованность min/max проверяется после применения
+func (r *UpdateTeamSettingsRequest) Validate() error {
+	if err := ValidateTeamName(r.TeamName); err != nil {
+		return err
+	}
+	if r.MinReviewers != nil && *r.MinReviewers < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "min_reviewers cannot be negative")
+	}
+	if r.MaxReviewers != nil && *r.MaxReviewers < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "max_reviewers cannot be negative")
+	}
+	if r.RequiredApprovals != nil && *r.RequiredApprovals < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "required_approvals cannot be negative")
+	}
+	if r.LargePRLines != nil && *r.LargePRLines < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "large_pr_lines cannot be negative")
+	}
+	if r.LargePRReviewers != nil && *r.LargePRReviewers < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "large_pr_reviewers cannot be negative")
+	}
+	if v := r.DefaultMaxOpenReviews.Value; v != nil && *v < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "default_max_open_reviews cannot be negative")
+	}
+	if r.CapacityOverflow != nil {
+		switch *r.CapacityOverflow {
+		case domain.CapacityOverflowReject, domain.CapacityOverflowQueue:
+		default:
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("unknown capacity_overflow %q", *r.CapacityOverflow))
+		}
+	}
+	if r.ReviewerStrategy != nil {
+		if *r.ReviewerStrategy == "" {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, "reviewer_strategy cannot be empty")
+		}
+		if err := ValidateReviewerStrategy(*r.ReviewerStrategy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply переносит заданные в запросе поля в настройки
+func (r *UpdateTeamSettingsRequest) Apply(settings *domain.TeamSettings) {
+	if r.MinReviewers != nil {
+		settings.MinReviewers = *r.MinReviewers
+	}
+	if r.MaxReviewers != nil {
+		settings.MaxReviewers = *r.MaxReviewers
+	}
+	if r.ReviewerStrategy != nil {
+		settings.ReviewerStrategy = *r.ReviewerStrategy
+	}
+	if r.AllowNoReviewers != nil {
+		settings.AllowNoReviewers = *r.AllowNoReviewers
+	}
+	if r.DefaultMaxOpenReviews.Set {
+		settings.DefaultMaxOpenReviews = r.DefaultMaxOpenReviews.Value
+	}
+	if r.CapacityOverflow != nil {
+		settings.CapacityOverflow = *r.CapacityOverflow
+	}
+	if r.RequiredApprovals != nil {
+		settings.RequiredApprovals = *r.RequiredApprovals
+	}
+	if r.LargePRLines != nil {
+		settings.LargePRLines = *r.LargePRLines
+	}
+	if r.LargePRReviewers != nil {
+		settings.LargePRReviewers = *r.LargePRReviewers
+	}
+}
+
+func TeamSettingsFromDomain(settings *domain.TeamSettings) TeamSettingsResponse {
+	return TeamSettingsResponse{
+		TeamName:              settings.TeamName,
+		MinReviewers:          settings.MinReviewers,
+		MaxReviewers:          settings.MaxReviewers,
+		ReviewerStrategy:      settings.ReviewerStrategy,
+		AllowNoReviewers:      settings.AllowNoReviewers,
+		DefaultMaxOpenReviews: settings.DefaultMaxOpenReviews,
+		CapacityOverflow:      settings.CapacityOverflow,
+		RequiredApprovals:     settings.RequiredApprovals,
+		LargePRLines:          settings.LargePRLines,
+		LargePRReviewers:      settings.LargePRReviewers,
+	}
+}
+
+// ValidateReviewerStrategy проверяет стратегию выбора ревьюверов, пустая строка - стратегия по умолчанию
+func ValidateReviewerStrategy(strategy string) error {
+	switch strategy {
+	case "", domain.ReviewerStrategyRandom, domain.ReviewerStrategyRoundRobin, domain.ReviewerStrategyLeastLoaded:
+		return nil
+	default:
+		return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("unknown reviewer_strategy %q", strategy))
+	}
+}
+
+// OptionalInt различает отсутствующее в JSON поле и явный null
+type OptionalInt struct {
+	Set   bool
+	Value *int
+}
+
+func (o *OptionalInt) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Value = nil
+		return nil
+	}
+	var v int
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	o.Value = &v
+	return nil
+}
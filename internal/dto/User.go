@@ -29,11 +29,40 @@ func (r *SetIsActiveRequest) Validate() error {
 	return ValidateUserID(r.UserID)
 }
 
-type UserResponse struct {
-	ID       string `json:"user_id"`
+type SetUsernameRequest struct {
+	UserID   string `json:"user_id"`
 	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+}
+
+func (r *SetUsernameRequest) Validate() error {
+	if err := ValidateUserID(r.UserID); err != nil {
+		return err
+	}
+	return ValidateUsername(r.Username)
+}
+
+// SetMaxOpenReviewsRequest - запрос на установку личного лимита открытых ревью, null - лимит команды
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+func (r *SetMaxOpenReviewsRequest) Validate() error {
+	if err := ValidateUserID(r.UserID); err != nil {
+		return err
+	}
+	if r.MaxOpenReviews != nil && *r.MaxOpenReviews < 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "max_open_reviews cannot be negative")
+	}
+	return nil
+}
+
+type UserResponse struct {
+	ID             string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	IsActive       bool   `json:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty"`
 }
 
 // ToDomain преобразует DTO в domain модель
@@ -47,10 +76,11 @@ func (r *UserRequest) ToDomain() *domain.User {
 
 func UserFromDomain(user *domain.User) UserResponse {
 	return UserResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		TeamName: user.TeamName,
-		IsActive: user.IsActive,
+		ID:             user.ID,
+		Username:       user.Username,
+		TeamName:       user.TeamName,
+		IsActive:       user.IsActive,
+		MaxOpenReviews: user.MaxOpenReviews,
 	}
 }
 
@@ -0,0 +1,176 @@
+package dto
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"avito/internal/domain"
+)
+
+const (
+	maxWebhookURLLength    = 2048
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 255
+)
+
+// CreateWebhookRequest - подписка на события; пустой event_types - все события,
+// без secret секрет генерируется и возвращается один раз в ответе
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+func (r *CreateWebhookRequest) Validate() error {
+	return validateWebhook(r.URL, r.Secret, r.EventTypes)
+}
+
+func (r *CreateWebhookRequest) ToDomain() *domain.WebhookSubscription {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return &domain.WebhookSubscription{
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: normalizeEventTypes(r.EventTypes),
+		IsActive:   isActive,
+	}
+}
+
+// UpdateWebhookRequest заменяет подписку целиком; пустой secret оставляет текущий
+type UpdateWebhookRequest struct {
+	ID         int64    `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types"`
+	IsActive   bool     `json:"is_active"`
+}
+
+func (r *UpdateWebhookRequest) Validate() error {
+	if r.ID <= 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "id must be positive")
+	}
+	return validateWebhook(r.URL, r.Secret, r.EventTypes)
+}
+
+func (r *UpdateWebhookRequest) ToDomain() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:         r.ID,
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: normalizeEventTypes(r.EventTypes),
+		IsActive:   r.IsActive,
+	}
+}
+
+type DeleteWebhookRequest struct {
+	ID int64 `json:"id"`
+}
+
+func (r *DeleteWebhookRequest) Validate() error {
+	if r.ID <= 0 {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "id must be positive")
+	}
+	return nil
+}
+
+func validateWebhook(rawURL, secret string, eventTypes []string) error {
+	if rawURL == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "url is required")
+	}
+	if len(rawURL) > maxWebhookURLLength {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "url too long (max 2048 characters)")
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "url must be an absolute http or https URL")
+	}
+
+	if secret != "" && (len(secret) < minWebhookSecretLength || len(secret) > maxWebhookSecretLength) {
+		return domain.NewAppError(domain.ErrCodeInvalidInput, "secret must be between 16 and 255 characters")
+	}
+
+	seen := make(map[string]struct{}, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !domain.IsEventType(eventType) {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("unknown event type %s", eventType))
+		}
+		if _, ok := seen[eventType]; ok {
+			return domain.NewAppError(domain.ErrCodeInvalidInput, fmt.Sprintf("duplicate event type %s", eventType))
+		}
+		seen[eventType] = struct{}{}
+	}
+	return nil
+}
+
+func normalizeEventTypes(eventTypes []string) []string {
+	if eventTypes == nil {
+		return []string{}
+	}
+	return eventTypes
+}
+
+// WebhookResponse - подписка без секрета
+type WebhookResponse struct {
+	ID         int64     `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func WebhookFromDomain(sub *domain.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: normalizeEventTypes(sub.EventTypes),
+		IsActive:   sub.IsActive,
+		CreatedAt:  sub.CreatedAt,
+	}
+}
+
+func WebhooksFromDomain(subs []domain.WebhookSubscription) []WebhookResponse {
+	result := make([]WebhookResponse, len(subs))
+	for i := range subs {
+		result[i] = WebhookFromDomain(&subs[i])
+	}
+	return result
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64      `json:"id"`
+	EventID        int64      `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+func WebhookDeliveriesFromDomain(deliveries []domain.WebhookDelivery) []WebhookDeliveryResponse {
+	result := make([]WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		result[i] = WebhookDeliveryResponse{
+			ID:             d.ID,
+			EventID:        d.EventID,
+			EventType:      d.EventType,
+			Status:         d.Status,
+			Attempts:       d.Attempts,
+			LastError:      d.LastError,
+			ResponseStatus: d.ResponseStatus,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+		if d.Status == domain.DeliveryStatusPending {
+			nextAttemptAt := d.NextAttemptAt
+			result[i].NextAttemptAt = &nextAttemptAt
+		}
+	}
+	return result
+}
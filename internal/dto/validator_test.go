@@ -2,7 +2,14 @@ package dto
 
 import (
 	"avito/internal/domain"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestValidateTeamName(t *testing.T) {
@@ -155,3 +162,539 @@ func TestNewAppError(t *testing.T) {
 		t.Errorf("Expected Error() to return 'test message', got '%s'", err.Error())
 	}
 }
+
+func TestUpdateTeamSettingsRequest_Validate(t *testing.T) {
+	negative := -1
+	two := 2
+	unknown := "fastest"
+	leastLoaded := domain.ReviewerStrategyLeastLoaded
+
+	tests := []struct {
+		name      string
+		req       UpdateTeamSettingsRequest
+		wantError bool
+	}{
+		{"only team name", UpdateTeamSettingsRequest{TeamName: "backend"}, false},
+		{"valid update", UpdateTeamSettingsRequest{TeamName: "backend", MaxReviewers: &two, ReviewerStrategy: &leastLoaded}, false},
+		{"empty team name", UpdateTeamSettingsRequest{TeamName: ""}, true},
+		{"negative min", UpdateTeamSettingsRequest{TeamName: "backend", MinReviewers: &negative}, true},
+		{"unknown strategy", UpdateTeamSettingsRequest{TeamName: "backend", ReviewerStrategy: &unknown}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestUpdateTeamSettingsRequest_DefaultMaxOpenReviews(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		wantSet   bool
+		wantValue *int
+	}{
+		{"absent", `{"team_name":"backend"}`, false, nil},
+		{"explicit null", `{"team_name":"backend","default_max_open_reviews":null}`, true, nil},
+		{"value", `{"team_name":"backend","default_max_open_reviews":3}`, true, intPtr(3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req UpdateTeamSettingsRequest
+			if err := json.Unmarshal([]byte(tt.body), &req); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if req.DefaultMaxOpenReviews.Set != tt.wantSet {
+				t.Errorf("Set = %v, want %v", req.DefaultMaxOpenReviews.Set, tt.wantSet)
+			}
+			got := req.DefaultMaxOpenReviews.Value
+			if (got == nil) != (tt.wantValue == nil) || (got != nil && *got != *tt.wantValue) {
+				t.Errorf("Value = %v, want %v", got, tt.wantValue)
+			}
+
+			limit := 5
+			settings := domain.DefaultTeamSettings("backend")
+			settings.DefaultMaxOpenReviews = &limit
+			req.Apply(&settings)
+			if !tt.wantSet && settings.DefaultMaxOpenReviews != &limit {
+				t.Error("Absent field must keep the current limit")
+			}
+			if tt.wantSet && tt.wantValue == nil && settings.DefaultMaxOpenReviews != nil {
+				t.Error("Explicit null must clear the limit")
+			}
+		})
+	}
+}
+
+func TestCreateAbsenceRequest_Validate(t *testing.T) {
+	start := time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		req     CreateAbsenceRequest
+		wantErr bool
+	}{
+		{"valid", CreateAbsenceRequest{UserID: "u1", StartsAt: start, EndsAt: start.Add(time.Hour)}, false},
+		{"missing end", CreateAbsenceRequest{UserID: "u1", StartsAt: start}, true},
+		{"empty period", CreateAbsenceRequest{UserID: "u1", StartsAt: start, EndsAt: start}, true},
+		{"ends before start", CreateAbsenceRequest{UserID: "u1", StartsAt: start, EndsAt: start.Add(-time.Hour)}, true},
+		{"reason too long", CreateAbsenceRequest{UserID: "u1", StartsAt: start, EndsAt: start.Add(time.Hour), Reason: strings.Repeat("a", 501)}, true},
+		{"invalid user", CreateAbsenceRequest{UserID: "", StartsAt: start, EndsAt: start.Add(time.Hour)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSubmitReviewRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     SubmitReviewRequest
+		wantErr bool
+	}{
+		{"approved", SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: domain.ReviewStateApproved}, false},
+		{"commented with body", SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: domain.ReviewStateCommented, Body: "nit"}, false},
+		{"pending is not a verdict", SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: domain.ReviewStatePending}, true},
+		{"lowercase state", SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: "approved"}, true},
+		{"body too long", SubmitReviewRequest{PullRequestID: "pr-1", ReviewerID: "u1", State: domain.ReviewStateCommented, Body: strings.Repeat("a", 10001)}, true},
+		{"missing reviewer", SubmitReviewRequest{PullRequestID: "pr-1", State: domain.ReviewStateApproved}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTeamFallbacks_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TeamFallbacks
+		wantErr bool
+	}{
+		{"ordered list", TeamFallbacks{TeamName: "backend", FallbackTeams: []string{"platform", "infra"}}, false},
+		{"clear", TeamFallbacks{TeamName: "backend", FallbackTeams: []string{}}, false},
+		{"missing list", TeamFallbacks{TeamName: "backend"}, true},
+		{"self", TeamFallbacks{TeamName: "backend", FallbackTeams: []string{"backend"}}, true},
+		{"duplicate", TeamFallbacks{TeamName: "backend", FallbackTeams: []string{"infra", "infra"}}, true},
+		{"invalid name", TeamFallbacks{TeamName: "backend", FallbackTeams: []string{""}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPullRequestCreateRequest_ValidateChangedFiles(t *testing.T) {
+	tooMany := make([]string, maxChangedFiles+1)
+	for i := range tooMany {
+		tooMany[i] = "file.go"
+	}
+
+	tests := []struct {
+		name    string
+		files   []string
+		wantErr bool
+	}{
+		{"none", nil, false},
+		{"paths", []string{"cmd/main.go", "docs/README.md"}, false},
+		{"empty path", []string{"main.go", " "}, true},
+		{"long path", []string{strings.Repeat("a", maxChangedFileLength+1)}, true},
+		{"too many", tooMany, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := PullRequestCreateRequest{ID: "pr-1", Name: "Feature", AuthorID: "u1", ChangedFiles: tt.files}
+			if err := req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTeamCodeOwnersRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TeamCodeOwnersRequest
+		wantErr bool
+	}{
+		{"valid", TeamCodeOwnersRequest{TeamName: "backend", Content: "*.go @u1\n"}, false},
+		{"empty content", TeamCodeOwnersRequest{TeamName: "backend", Content: "\n"}, true},
+		{"too large", TeamCodeOwnersRequest{TeamName: "backend", Content: strings.Repeat("#", maxCodeOwnersSize+1)}, true},
+		{"invalid team", TeamCodeOwnersRequest{Content: "*.go @u1"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCreateWebhookRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     CreateWebhookRequest
+		wantErr bool
+	}{
+		{"all events", CreateWebhookRequest{URL: "https://example.com/hook"}, false},
+		{"filtered", CreateWebhookRequest{URL: "http://hooks.local:8080/in", EventTypes: []string{domain.EventPRCreated, domain.EventPRMerged}}, false},
+		{"custom secret", CreateWebhookRequest{URL: "https://example.com/hook", Secret: strings.Repeat("s", 16)}, false},
+		{"missing url", CreateWebhookRequest{}, true},
+		{"relative url", CreateWebhookRequest{URL: "/hook"}, true},
+		{"unsupported scheme", CreateWebhookRequest{URL: "ftp://example.com/hook"}, true},
+		{"short secret", CreateWebhookRequest{URL: "https://example.com/hook", Secret: "short"}, true},
+		{"unknown event", CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"pr.deleted"}}, true},
+		{"duplicate event", CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{domain.EventPRReady, domain.EventPRReady}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGitHubPullRequestEvent_ToCommand(t *testing.T) {
+	tests := []struct {
+		fixture string
+		action  string
+		draft   bool
+	}{
+		{"pull_request_opened.json", domain.IntegrationActionOpen, false},
+		{"pull_request_opened_draft.json", domain.IntegrationActionOpen, true},
+		{"pull_request_ready_for_review.json", domain.IntegrationActionReady, false},
+		{"pull_request_closed_merged.json", domain.IntegrationActionMerge, false},
+		{"pull_request_closed_unmerged.json", domain.IntegrationActionClose, false},
+		{"pull_request_reopened.json", domain.IntegrationActionReopen, false},
+		{"pull_request_labeled.json", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "github", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var event GitHubPullRequestEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				t.Fatal(err)
+			}
+			if err := event.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			cmd := event.ToCommand()
+			if cmd.Action != tt.action || cmd.Draft != tt.draft {
+				t.Errorf("Expected action %q draft %v, got %q draft %v", tt.action, tt.draft, cmd.Action, cmd.Draft)
+			}
+			if cmd.Action == "" && cmd.Reason == "" {
+				t.Error("Ignored event must carry a reason")
+			}
+			if cmd.PullRequestID != "gh-octo-org-api-server-bb235ab7-42" {
+				t.Errorf("Unexpected pull request id %q", cmd.PullRequestID)
+			}
+			if err := ValidatePullRequestID(cmd.PullRequestID); err != nil {
+				t.Errorf("Generated id must be valid: %v", err)
+			}
+			if cmd.AuthorLogin != "octo-dev" {
+				t.Errorf("Expected normalized login octo-dev, got %q", cmd.AuthorLogin)
+			}
+			if cmd.Metadata.Repository != "octo-org/api.server" || cmd.Metadata.TargetBranch != "main" {
+				t.Errorf("Unexpected metadata %+v", cmd.Metadata)
+			}
+		})
+	}
+}
+
+func TestGitHubPullRequestEvent_Metadata(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "github", "pull_request_opened.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var event GitHubPullRequestEvent
+	if err := json.Unmarshal(data, &event); err != nil {
+		t.Fatal(err)
+	}
+
+	got := event.ToCommand().Metadata
+	want := domain.PRMetadata{
+		Repository:        "octo-org/api.server",
+		SourceBranch:      "rate-limit",
+		TargetBranch:      "main",
+		URL:               "https://github.com/octo-org/api.server/pull/42",
+		Labels:            []string{},
+		Additions:         212,
+		Deletions:         18,
+		ChangedFilesCount: 7,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Metadata = %+v, want %+v", got, want)
+	}
+}
+
+func TestExternalMetadata(t *testing.T) {
+	labels := []string{"Bug", "bug", " ", strings.Repeat("x", maxLabelLength+10)}
+	for i := 0; i < maxLabels+5; i++ {
+		labels = append(labels, fmt.Sprintf("l%d", i))
+	}
+
+	got := externalMetadata(PullRequestMetadata{
+		Repository: strings.Repeat("r", maxRefLength+1),
+		URL:        "not a url",
+		Labels:     labels,
+		Additions:  -5,
+	})
+
+	if len(got.Repository) != maxRefLength {
+		t.Errorf("Expected repository truncated to %d, got %d", maxRefLength, len(got.Repository))
+	}
+	if got.URL != "" {
+		t.Errorf("Expected invalid url dropped, got %q", got.URL)
+	}
+	if len(got.Labels) != maxLabels || got.Labels[0] != "bug" || len(got.Labels[1]) != maxLabelLength {
+		t.Errorf("Unexpected labels %v", got.Labels)
+	}
+	if got.Additions != 0 {
+		t.Errorf("Expected negative additions clamped, got %d", got.Additions)
+	}
+	meta := PullRequestMetadata{Labels: got.Labels}
+	if err := meta.Validate(); err != nil {
+		t.Errorf("Sanitized metadata must be valid: %v", err)
+	}
+}
+
+func TestPullRequestMetadata_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		meta    PullRequestMetadata
+		wantErr bool
+	}{
+		{"empty", PullRequestMetadata{}, false},
+		{"full", PullRequestMetadata{
+			Repository: "org/repo", SourceBranch: "feature", TargetBranch: "main",
+			URL: "https://git.example.com/org/repo/pull/1", Labels: []string{"security", "backend"},
+			Additions: 10, Deletions: 2, ChangedFilesCount: 3,
+		}, false},
+		{"relative url", PullRequestMetadata{URL: "/org/repo/pull/1"}, true},
+		{"long branch", PullRequestMetadata{TargetBranch: strings.Repeat("b", maxRefLength+1)}, true},
+		{"duplicate label", PullRequestMetadata{Labels: []string{"Bug", "bug"}}, true},
+		{"empty label", PullRequestMetadata{Labels: []string{""}}, true},
+		{"negative additions", PullRequestMetadata{Additions: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.meta.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdatePullRequestRequest_Apply(t *testing.T) {
+	name := "Renamed"
+	additions := 700
+	req := UpdatePullRequestRequest{PullRequestID: "pr-1", Name: &name, Labels: []string{"Security"}, Additions: &additions}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	pr := &domain.PullRequest{ID: "pr-1", Name: "Feature", PRMetadata: domain.PRMetadata{Repository: "org/repo", Deletions: 5}}
+	req.Apply(pr)
+
+	if pr.Name != "Renamed" || pr.Repository != "org/repo" || pr.Additions != 700 || pr.Deletions != 5 {
+		t.Errorf("Unexpected PR after update: %+v", pr)
+	}
+	if len(pr.Labels) != 1 || pr.Labels[0] != "security" {
+		t.Errorf("Expected normalized labels, got %v", pr.Labels)
+	}
+
+	invalid := UpdatePullRequestRequest{PullRequestID: "pr-1", URL: &name}
+	if err := invalid.Validate(); err == nil {
+		t.Error("Expected invalid url to be rejected")
+	}
+}
+
+func TestGitLabMergeRequestEvent_ToCommand(t *testing.T) {
+	tests := []struct {
+		fixture string
+		action  string
+		draft   bool
+	}{
+		{"merge_request_open.json", domain.IntegrationActionOpen, false},
+		{"merge_request_open_draft.json", domain.IntegrationActionOpen, true},
+		{"merge_request_update_ready.json", domain.IntegrationActionReady, false},
+		{"merge_request_update_title.json", "", false},
+		{"merge_request_close.json", domain.IntegrationActionClose, false},
+		{"merge_request_reopen.json", domain.IntegrationActionReopen, false},
+		{"merge_request_merge.json", domain.IntegrationActionMerge, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "gitlab", tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var event GitLabMergeRequestEvent
+			if err := json.Unmarshal(data, &event); err != nil {
+				t.Fatal(err)
+			}
+			if err := event.Validate(); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			cmd := event.ToCommand()
+			if cmd.Action != tt.action || cmd.Draft != tt.draft {
+				t.Errorf("Expected action %q draft %v, got %q draft %v", tt.action, tt.draft, cmd.Action, cmd.Draft)
+			}
+			if cmd.PullRequestID != "gl-Payments-billing-api-a6e84c15-17" {
+				t.Errorf("Unexpected pull request id %q", cmd.PullRequestID)
+			}
+			if cmd.Project != "payments/billing-api" || cmd.AuthorLogin != "d.ivanova" {
+				t.Errorf("Unexpected project %q or author %q", cmd.Project, cmd.AuthorLogin)
+			}
+		})
+	}
+}
+
+func TestExternalPullRequestID(t *testing.T) {
+	first := ExternalPullRequestID("gh", "acme/web-app", 12)
+	second := ExternalPullRequestID("gh", "acme-web/app", 12)
+	if first == second {
+		t.Errorf("Repositories acme/web-app and acme-web/app must get different ids, both got %q", first)
+	}
+	if first != ExternalPullRequestID("gh", "acme/web-app", 12) {
+		t.Error("The same repository and number must always give the same id")
+	}
+
+	long := ExternalPullRequestID("gl", strings.Repeat("group/", 100)+"project", 7)
+	for _, id := range []string{first, second, long} {
+		if err := ValidatePullRequestID(id); err != nil {
+			t.Errorf("Generated id %q must be valid: %v", id, err)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("привет", 3); got != "п" {
+		t.Errorf("Expected truncation on rune boundary, got %q", got)
+	}
+	if got := truncate("short", 10); got != "short" {
+		t.Errorf("Expected unchanged string, got %q", got)
+	}
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestTeamRules_Validate(t *testing.T) {
+	minLines := 1000
+	security := AssignmentRule{Name: "security", When: AssignmentCondition{Labels: []string{"Security"}}, Then: AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}}
+	large := AssignmentRule{Name: "large", When: AssignmentCondition{MinLines: &minLines}, Then: AssignmentAction{Reviewers: 3}}
+
+	tests := []struct {
+		name    string
+		req     TeamRules
+		wantErr bool
+	}{
+		{"valid", TeamRules{TeamName: "backend", Rules: []AssignmentRule{security, large}}, false},
+		{"clear", TeamRules{TeamName: "backend", Rules: []AssignmentRule{}}, false},
+		{"missing rules", TeamRules{TeamName: "backend"}, true},
+		{"duplicate name", TeamRules{TeamName: "backend", Rules: []AssignmentRule{large, large}}, true},
+		{"invalid name", TeamRules{TeamName: "backend", Rules: []AssignmentRule{{Name: "a/b", When: large.When, Then: large.Then}}}, true},
+		{"empty condition", TeamRules{TeamName: "backend", Rules: []AssignmentRule{{Name: "all", Then: large.Then}}}, true},
+		{"duplicate label", TeamRules{TeamName: "backend", Rules: []AssignmentRule{{Name: "dup", When: AssignmentCondition{Labels: []string{"a", "A"}}, Then: large.Then}}}, true},
+		{"invalid extra team", TeamRules{TeamName: "backend", Rules: []AssignmentRule{{Name: "sec", When: security.When, Then: AssignmentAction{ExtraTeam: "a/b", ExtraReviewers: 1}}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	rules := (&TeamRules{TeamName: "backend", Rules: []AssignmentRule{security}}).ToDomain()
+	if rules[0].TeamName != "backend" || rules[0].When.Labels[0] != "security" {
+		t.Errorf("ToDomain() = %+v, want team backend and normalized label", rules[0])
+	}
+}
+
+func TestPRCursor_RoundTrip(t *testing.T) {
+	cursor := &domain.PRCursor{SortBy: domain.PRSortMergedAt, Value: time.Date(2025, 3, 1, 10, 0, 0, 123000, time.UTC), ID: "pr-1"}
+
+	decoded, err := DecodePRCursor(EncodePRCursor(cursor))
+	if err != nil {
+		t.Fatalf("DecodePRCursor() error = %v", err)
+	}
+	if decoded.SortBy != cursor.SortBy || !decoded.Value.Equal(cursor.Value) || decoded.ID != cursor.ID {
+		t.Errorf("DecodePRCursor() = %+v, want %+v", decoded, cursor)
+	}
+
+	if EncodePRCursor(nil) != "" {
+		t.Error("EncodePRCursor(nil) must be empty")
+	}
+
+	for _, raw := range []string{"not base64!", "e30", EncodePRCursor(&domain.PRCursor{SortBy: "name", ID: "pr-1"})} {
+		if _, err := DecodePRCursor(raw); err == nil {
+			t.Errorf("DecodePRCursor(%q) expected error", raw)
+		}
+	}
+}
+
+func TestDeleteTeamRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     DeleteTeamRequest
+		wantErr bool
+	}{
+		{"no open PRs choice", DeleteTeamRequest{TeamName: "backend"}, false},
+		{"close", DeleteTeamRequest{TeamName: "backend", OpenPRs: "close"}, false},
+		{"reassign", DeleteTeamRequest{TeamName: "backend", OpenPRs: "reassign", ReassignTo: "platform"}, false},
+		{"reassign without target", DeleteTeamRequest{TeamName: "backend", OpenPRs: "reassign"}, true},
+		{"reassign to itself", DeleteTeamRequest{TeamName: "backend", OpenPRs: "reassign", ReassignTo: "backend"}, true},
+		{"target without reassign", DeleteTeamRequest{TeamName: "backend", OpenPRs: "close", ReassignTo: "platform"}, true},
+		{"unknown choice", DeleteTeamRequest{TeamName: "backend", OpenPRs: "keep"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.req.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (&RenameTeamRequest{TeamName: "backend", NewTeamName: "backend"}).Validate(); err == nil {
+		t.Error("RenameTeamRequest.Validate() expected error for unchanged name")
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/service"
+)
+
+// AbsenceHandler handles user absence endpoints
+type AbsenceHandler struct {
+	absenceService *service.AbsenceService
+}
+
+// NewAbsenceHandler creates a new absence handler
+func NewAbsenceHandler(absenceService *service.AbsenceService) *AbsenceHandler {
+	return &AbsenceHandler{
+		absenceService: absenceService,
+	}
+}
+
+// Create handles POST /users/absences/create
+func (h *AbsenceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	absence := req.ToDomain()
+	if err := h.absenceService.Create(r.Context(), absence); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, AbsenceResponse{Absence: dto.AbsenceFromDomain(absence)})
+}
+
+// List handles GET /users/absences/list
+func (h *AbsenceHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "user_id is required")
+		return
+	}
+
+	if err := dto.ValidateUserID(userID); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	absences, err := h.absenceService.List(r.Context(), userID)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, UserAbsencesResponse{
+		UserID:   userID,
+		Absences: dto.AbsencesFromDomain(absences),
+	})
+}
+
+// Delete handles POST /users/absences/delete
+func (h *AbsenceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteAbsenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	if err := h.absenceService.Delete(r.Context(), req.AbsenceID); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
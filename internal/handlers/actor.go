@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"avito/internal/domain"
+)
+
+// ActorHeader names who performs the request (a user id or a service name); it is recorded
+// in the assignment history and is not authenticated
+const ActorHeader = "X-Actor"
+
+const maxActorLength = 255
+
+// ActorMiddleware puts the caller named in ActorHeader into the request context
+func ActorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := strings.TrimSpace(r.Header.Get(ActorHeader))
+		if actor != "" && len(actor) <= maxActorLength {
+			r = r.WithContext(domain.WithActor(r.Context(), actor))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"avito/internal/domain"
+)
+
+// AdminTokenHeader carries the token that grants admin-only actions
+const AdminTokenHeader = "X-Admin-Token"
+
+// isAdminRequest reports whether the request carries the configured admin token.
+// An empty configured token disables admin actions entirely.
+func isAdminRequest(r *http.Request, adminToken string) bool {
+	if adminToken == "" {
+		return false
+	}
+	got := r.Header.Get(AdminTokenHeader)
+	return subtle.ConstantTimeCompare([]byte(got), []byte(adminToken)) == 1
+}
+
+// requireAdmin serves next only for requests carrying the admin token
+func requireAdmin(adminToken string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isAdminRequest(r, adminToken) {
+			WriteError(w, http.StatusForbidden, domain.ErrCodeForbidden, "admin token required")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/service"
+)
+
+const (
+	// GitHubSignatureHeader carries the HMAC-SHA256 of the raw body signed with the webhook secret
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+	// GitHubEventHeader carries the event name, e.g. pull_request
+	GitHubEventHeader = "X-GitHub-Event"
+
+	// maxIntegrationPayloadSize matches the GitHub payload cap
+	maxIntegrationPayloadSize = 25 << 20
+)
+
+// GitHubHandler handles GitHub integration endpoints
+type GitHubHandler struct {
+	integrationMappings
+	webhookSecret string
+}
+
+// NewGitHubHandler creates a new GitHub integration handler.
+// An empty webhook secret rejects every delivery.
+func NewGitHubHandler(integrationService *service.IntegrationService, webhookSecret string) *GitHubHandler {
+	return &GitHubHandler{
+		integrationMappings: integrationMappings{
+			integrationService: integrationService,
+			provider:           domain.IntegrationGitHub,
+		},
+		webhookSecret: webhookSecret,
+	}
+}
+
+// Webhook handles POST /integrations/github/webhook
+func (h *GitHubHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationPayloadSize))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if !h.validSignature(body, r.Header.Get(GitHubSignatureHeader)) {
+		WriteError(w, http.StatusUnauthorized, domain.ErrCodeUnauthorized, "invalid webhook signature")
+		return
+	}
+
+	event := r.Header.Get(GitHubEventHeader)
+	if event != "pull_request" {
+		WriteJSON(w, http.StatusOK, dto.IntegrationEventResponse{Status: "ignored", Reason: "event " + event + " is not handled"})
+		return
+	}
+
+	var req dto.GitHubPullRequestEvent
+	if err := json.Unmarshal(body, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	result, err := h.integrationService.Apply(r.Context(), req.ToCommand())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.IntegrationEventFromDomain(result))
+}
+
+func (h *GitHubHandler) validSignature(body []byte, signature string) bool {
+	if h.webhookSecret == "" || signature == "" {
+		return false
+	}
+	expected := service.SignWebhookPayload(h.webhookSecret, body)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/service"
+)
+
+const (
+	// GitLabTokenHeader carries the secret token configured on the GitLab webhook
+	GitLabTokenHeader = "X-Gitlab-Token"
+	// GitLabEventHeader carries the hook name, e.g. Merge Request Hook
+	GitLabEventHeader = "X-Gitlab-Event"
+
+	gitLabMergeRequestHook = "Merge Request Hook"
+)
+
+// GitLabHandler handles GitLab integration endpoints
+type GitLabHandler struct {
+	integrationMappings
+	webhookToken string
+}
+
+// NewGitLabHandler creates a new GitLab integration handler.
+// An empty webhook token rejects every delivery.
+func NewGitLabHandler(integrationService *service.IntegrationService, webhookToken string) *GitLabHandler {
+	return &GitLabHandler{
+		integrationMappings: integrationMappings{
+			integrationService: integrationService,
+			provider:           domain.IntegrationGitLab,
+		},
+		webhookToken: webhookToken,
+	}
+}
+
+// Webhook handles POST /integrations/gitlab/webhook
+func (h *GitLabHandler) Webhook(w http.ResponseWriter, r *http.Request) {
+	if !h.validToken(r.Header.Get(GitLabTokenHeader)) {
+		WriteError(w, http.StatusUnauthorized, domain.ErrCodeUnauthorized, "invalid webhook token")
+		return
+	}
+
+	event := r.Header.Get(GitLabEventHeader)
+	if event != gitLabMergeRequestHook {
+		WriteJSON(w, http.StatusOK, dto.IntegrationEventResponse{Status: "ignored", Reason: "event " + event + " is not handled"})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIntegrationPayloadSize))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	var req dto.GitLabMergeRequestEvent
+	if err := json.Unmarshal(body, &req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	result, err := h.integrationService.Apply(r.Context(), req.ToCommand())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.IntegrationEventFromDomain(result))
+}
+
+func (h *GitLabHandler) validToken(token string) bool {
+	if h.webhookToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.webhookToken)) == 1
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/service"
+)
+
+// integrationMappings serves user and project mapping endpoints of a single provider
+type integrationMappings struct {
+	integrationService *service.IntegrationService
+	provider           string
+}
+
+// MapUser handles POST /integrations/{provider}/users/map
+func (h *integrationMappings) MapUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.IntegrationUserMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	mapping := req.ToDomain(h.provider)
+	if err := h.integrationService.MapUser(r.Context(), mapping); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, IntegrationUsersResponse{
+		Provider: h.provider,
+		Users:    dto.IntegrationUserMappingsFromDomain([]domain.IntegrationUserMapping{*mapping}),
+	})
+}
+
+// ListUsers handles GET /integrations/{provider}/users
+func (h *integrationMappings) ListUsers(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.integrationService.ListUsers(r.Context(), h.provider)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, IntegrationUsersResponse{
+		Provider: h.provider,
+		Users:    dto.IntegrationUserMappingsFromDomain(mappings),
+	})
+}
+
+// UnmapUser handles POST /integrations/{provider}/users/unmap
+func (h *integrationMappings) UnmapUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteIntegrationUserMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	if err := h.integrationService.UnmapUser(r.Context(), h.provider, dto.NormalizeExternalLogin(req.Login)); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MapProject handles POST /integrations/{provider}/projects/map
+func (h *integrationMappings) MapProject(w http.ResponseWriter, r *http.Request) {
+	var req dto.IntegrationProjectMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	mapping := req.ToDomain(h.provider)
+	if err := h.integrationService.MapProject(r.Context(), mapping); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, IntegrationProjectsResponse{
+		Provider: h.provider,
+		Projects: dto.IntegrationProjectMappingsFromDomain([]domain.IntegrationProjectMapping{*mapping}),
+	})
+}
+
+// ListProjects handles GET /integrations/{provider}/projects
+func (h *integrationMappings) ListProjects(w http.ResponseWriter, r *http.Request) {
+	mappings, err := h.integrationService.ListProjects(r.Context(), h.provider)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, IntegrationProjectsResponse{
+		Provider: h.provider,
+		Projects: dto.IntegrationProjectMappingsFromDomain(mappings),
+	})
+}
+
+// UnmapProject handles POST /integrations/{provider}/projects/unmap
+func (h *integrationMappings) UnmapProject(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteIntegrationProjectMappingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	if err := h.integrationService.UnmapProject(r.Context(), h.provider, dto.NormalizeProjectPath(req.Project)); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
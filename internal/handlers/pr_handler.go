@@ -3,6 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"avito/internal/domain"
 	"avito/internal/dto"
@@ -11,13 +14,15 @@ import (
 
 // PullRequestHandler handles PR endpoints
 type PullRequestHandler struct {
-	prService *service.PullRequestService
+	prService  *service.PullRequestService
+	adminToken string
 }
 
 // NewPullRequestHandler creates a new PR handler
-func NewPullRequestHandler(prService *service.PullRequestService) *PullRequestHandler {
+func NewPullRequestHandler(prService *service.PullRequestService, adminToken string) *PullRequestHandler {
 	return &PullRequestHandler{
-		prService: prService,
+		prService:  prService,
+		adminToken: adminToken,
 	}
 }
 
@@ -35,7 +40,7 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.CreatePR(r.Context(), req.ID, req.Name, req.AuthorID)
+	pr, err := h.prService.CreatePR(r.Context(), req.ToDomain(), req.Strategy)
 	if err != nil {
 		WriteAppError(w, err)
 		return
@@ -43,9 +48,38 @@ func (h *PullRequestHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 
 	// Convert domain to DTO and return
 	response := dto.PRFromDomain(pr)
+	setPRETag(w, pr)
 	WriteJSON(w, http.StatusCreated, PRResponse{PR: response})
 }
 
+// UpdatePR handles POST /pullRequest/update
+func (h *PullRequestHandler) UpdatePR(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdatePullRequestRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.UpdatePR(r.Context(), req.PullRequestID, req.Apply, version)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRResponse{PR: dto.PRFromDomain(pr)})
+}
+
 // MergePR handles POST /pullRequest/merge
 func (h *PullRequestHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	var req dto.MergePRRequest
@@ -60,7 +94,22 @@ func (h *PullRequestHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID)
+	if req.Force && !isAdminRequest(r, h.adminToken) {
+		WriteError(w, http.StatusForbidden, domain.ErrCodeForbidden, "force merge requires admin token")
+		return
+	}
+	// X-Actor никто не проверяет, поэтому force-мерж записываем на предъявленный токен администратора
+	forcedBy := ""
+	if req.Force {
+		forcedBy = domain.ActorAdmin
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.MergePR(r.Context(), req.PullRequestID, forcedBy, version)
 	if err != nil {
 		WriteAppError(w, err)
 		return
@@ -68,6 +117,7 @@ func (h *PullRequestHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 
 	// Convert domain to DTO and return
 	response := dto.PRFromDomain(pr)
+	setPRETag(w, pr)
 	WriteJSON(w, http.StatusOK, PRResponse{PR: response})
 }
 
@@ -84,7 +134,14 @@ func (h *PullRequestHandler) ReassignReviewer(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	pr, replacedBy, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldReviewerID)
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, replacedBy, err := h.prService.ReassignReviewer(
+		r.Context(), req.PullRequestID, req.OldReviewerID, req.Strategy, req.Force, version,
+	)
 
 	if err != nil {
 		WriteAppError(w, err)
@@ -93,5 +150,310 @@ func (h *PullRequestHandler) ReassignReviewer(w http.ResponseWriter, r *http.Req
 
 	// Convert domain to DTO and return
 	response := dto.PRFromDomain(pr)
+	setPRETag(w, pr)
 	WriteJSON(w, http.StatusOK, PRReassignResponse{PR: response, ReplacedBy: replacedBy})
 }
+
+// SubmitReview handles POST /pullRequest/review
+func (h *PullRequestHandler) SubmitReview(w http.ResponseWriter, r *http.Request) {
+	var req dto.SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(r.Context(), req.ToDomain(), version)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRResponse{PR: dto.PRFromDomain(pr)})
+}
+
+// MarkReady handles POST /pullRequest/ready
+func (h *PullRequestHandler) MarkReady(w http.ResponseWriter, r *http.Request) {
+	var req dto.PRTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.MarkReady(r.Context(), req.PullRequestID, req.Strategy, version)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRResponse{PR: dto.PRFromDomain(pr)})
+}
+
+// ClosePR handles POST /pullRequest/close
+func (h *PullRequestHandler) ClosePR(w http.ResponseWriter, r *http.Request) {
+	var req dto.PRTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.ClosePR(r.Context(), req.PullRequestID, version)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRResponse{PR: dto.PRFromDomain(pr)})
+}
+
+// ReopenPR handles POST /pullRequest/reopen
+func (h *PullRequestHandler) ReopenPR(w http.ResponseWriter, r *http.Request) {
+	var req dto.PRTransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	version, ok := expectedVersion(w, r, req.ExpectedVersion)
+	if !ok {
+		return
+	}
+
+	pr, err := h.prService.ReopenPR(r.Context(), req.PullRequestID, req.Strategy, version)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRResponse{PR: dto.PRFromDomain(pr)})
+}
+
+// ListRuleFirings handles GET /pullRequest/rules
+func (h *PullRequestHandler) ListRuleFirings(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "pull_request_id is required")
+		return
+	}
+
+	if err := dto.ValidatePullRequestID(prID); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	firings, err := h.prService.ListRuleFirings(r.Context(), prID)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, PRRuleFiringsResponse{
+		PullRequestID: prID,
+		FiredRules:    dto.RuleFiringsFromDomain(firings),
+	})
+}
+
+// GetPR handles GET /pullRequest/get
+func (h *PullRequestHandler) GetPR(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "pull_request_id is required")
+		return
+	}
+
+	if err := dto.ValidatePullRequestID(prID); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	pr, history, err := h.prService.GetPR(r.Context(), prID)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	setPRETag(w, pr)
+	WriteJSON(w, http.StatusOK, PRDetailsResponse{
+		PR:      dto.PRFromDomain(pr),
+		History: dto.AssignmentEventsFromDomain(history),
+	})
+}
+
+// setPRETag отдаёт версию PR в заголовке ETag
+func setPRETag(w http.ResponseWriter, pr *domain.PullRequest) {
+	w.Header().Set("ETag", strconv.Quote(strconv.FormatInt(pr.Version, 10)))
+}
+
+// expectedVersion - версия PR, с которой клиент начинал изменение: из If-Match (ETag PR) или из
+// expected_version тела; 0 - версия не проверяется. При ошибке пишет 400 и возвращает false.
+func expectedVersion(w http.ResponseWriter, r *http.Request, bodyVersion int64) (int64, bool) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return bodyVersion, true
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(header, `"`), 10, 64)
+	if err != nil || version < 1 {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "If-Match must be a single PR ETag")
+		return 0, false
+	}
+	if bodyVersion != 0 && bodyVersion != version {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "If-Match does not match expected_version")
+		return 0, false
+	}
+	return version, true
+}
+
+// ListPRs handles GET /pullRequest/list. Фильтры: status, author_id, reviewer_id, team_name,
+// name (подстрока без учёта регистра), created_from/created_to, merged_from/merged_to (RFC 3339, правая граница
+// не включается) и фильтры метаданных как в /statistics. sort - created_at (по умолчанию) или merged_at,
+// limit - размер страницы, cursor - next_cursor предыдущей страницы.
+func (h *PullRequestHandler) ListPRs(w http.ResponseWriter, r *http.Request) {
+	query, ok := parsePRListQuery(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.prService.ListPRs(r.Context(), query)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.PRPageFromDomain(page))
+}
+
+// parsePRListQuery разбирает параметры списка PR; при ошибке пишет 400 и возвращает false
+func parsePRListQuery(w http.ResponseWriter, r *http.Request) (domain.PRListQuery, bool) {
+	params := r.URL.Query()
+
+	filter, ok := parsePRFilter(w, r)
+	if !ok {
+		return domain.PRListQuery{}, false
+	}
+	query := domain.PRListQuery{
+		PRFilter:     filter,
+		Status:       params.Get("status"),
+		AuthorID:     params.Get("author_id"),
+		ReviewerID:   params.Get("reviewer_id"),
+		TeamName:     params.Get("team_name"),
+		NameContains: params.Get("name"),
+		SortBy:       params.Get("sort"),
+	}
+
+	if !validStatusParam(w, query.Status) {
+		return query, false
+	}
+
+	switch query.SortBy {
+	case "", domain.PRSortCreatedAt, domain.PRSortMergedAt:
+	default:
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "sort must be created_at or merged_at")
+		return query, false
+	}
+
+	bounds := []struct {
+		name string
+		dest **time.Time
+	}{
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+		{"merged_from", &query.MergedFrom},
+		{"merged_to", &query.MergedTo},
+	}
+	for _, bound := range bounds {
+		raw := params.Get(bound.name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, bound.name+" must be an RFC 3339 timestamp")
+			return query, false
+		}
+		*bound.dest = &value
+	}
+
+	if query.Limit, ok = parseLimitParam(w, params.Get("limit")); !ok {
+		return query, false
+	}
+
+	if raw := params.Get("cursor"); raw != "" {
+		cursor, err := dto.DecodePRCursor(raw)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, err.Error())
+			return query, false
+		}
+		if query.SortBy == "" {
+			query.SortBy = cursor.SortBy
+		}
+		if cursor.SortBy != query.SortBy {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "cursor does not match sort")
+			return query, false
+		}
+		query.After = cursor
+	}
+
+	return query, true
+}
+
+// validStatusParam проверяет фильтр по статусу PR; при ошибке пишет 400 и возвращает false
+func validStatusParam(w http.ResponseWriter, status string) bool {
+	switch status {
+	case "", domain.PRStatusDraft, domain.PRStatusOpen, domain.PRStatusMerged, domain.PRStatusClosed:
+		return true
+	}
+	WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "status must be one of DRAFT, OPEN, MERGED, CLOSED")
+	return false
+}
+
+// parseLimitParam разбирает размер страницы; пустое значение - 0; при ошибке пишет 400 и возвращает false
+func parseLimitParam(w http.ResponseWriter, raw string) (int, bool) {
+	if raw == "" {
+		return 0, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > domain.MaxPRPageSize {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "limit must be between 1 and 100")
+		return 0, false
+	}
+	return limit, true
+}
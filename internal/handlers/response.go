@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/rs/zerolog/log"
+
 	"avito/internal/domain"
 )
 
@@ -26,22 +29,34 @@ func WriteError(w http.ResponseWriter, status int, code, message string) {
 }
 
 func WriteAppError(w http.ResponseWriter, err error) {
-	if appErr, ok := err.(*domain.AppError); ok {
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) {
 		status := getStatusCode(appErr.Code)
 		WriteError(w, status, appErr.Code, appErr.Message)
 		return
 	}
-	WriteError(w, http.StatusInternalServerError, domain.ErrCodeInternalError, err.Error())
+	// Текст прочих ошибок (драйвер, сеть) клиенту не отдаётся
+	log.Error().Err(err).Msg("internal error")
+	WriteError(w, http.StatusInternalServerError, domain.ErrCodeInternalError, "internal server error")
 }
 
 func getStatusCode(errCode string) int {
 	switch errCode {
-	case domain.ErrCodeTeamExists:
+	case domain.ErrCodeTeamExists, domain.ErrCodeInvalidRequest, domain.ErrCodeInvalidInput:
 		return http.StatusBadRequest
-	case domain.ErrCodePRExists, domain.ErrCodePRMerged, domain.ErrCodeNotAssigned, domain.ErrCodeNoCandidate:
+	case domain.ErrCodePRExists, domain.ErrCodePRMerged, domain.ErrCodeNotAssigned, domain.ErrCodeNoCandidate,
+		domain.ErrCodeCapacityExhausted, domain.ErrCodeReviewerApproved, domain.ErrCodeNotApproved,
+		domain.ErrCodeInvalidState, domain.ErrCodeUserInTeam,
+		domain.ErrCodeAlreadyExists, domain.ErrCodeConstraintViolation, domain.ErrCodeConcurrentUpdate:
 		return http.StatusConflict
+	case domain.ErrCodeUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrCodeForbidden:
+		return http.StatusForbidden
 	case domain.ErrCodeNotFound:
 		return http.StatusNotFound
+	case domain.ErrCodeVersionMismatch:
+		return http.StatusPreconditionFailed
 	default:
 		return http.StatusInternalServerError
 	}
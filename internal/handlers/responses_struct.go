@@ -8,14 +8,28 @@ type PRResponse struct {
 	PR dto.PullRequestResponse `json:"pr"`
 }
 
+// PRDetailsResponse - PR и история назначений его ревьюверов в хронологическом порядке
+type PRDetailsResponse struct {
+	PR      dto.PullRequestResponse `json:"pr"`
+	History []dto.AssignmentEvent   `json:"history"`
+}
+
 type PRReassignResponse struct {
 	PR         dto.PullRequestResponse `json:"pr"`
 	ReplacedBy string                  `json:"replaced_by"`
 }
 
+type PRRuleFiringsResponse struct {
+	PullRequestID string           `json:"pull_request_id"`
+	FiredRules    []dto.RuleFiring `json:"fired_rules"`
+}
+
 type UserReviewResponse struct {
 	UserID       string                 `json:"user_id"`
 	PullRequests []dto.PullRequestShort `json:"pull_requests"`
+	// Total - число PR ревьювера с учётом status, без учёта страницы
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 type HealthResponse struct {
@@ -30,3 +44,41 @@ type ErrorDetail struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
+
+type AbsenceResponse struct {
+	Absence dto.AbsenceResponse `json:"absence"`
+}
+
+type UserAbsencesResponse struct {
+	UserID   string                `json:"user_id"`
+	Absences []dto.AbsenceResponse `json:"absences"`
+}
+
+type WebhookResponse struct {
+	Webhook dto.WebhookResponse `json:"webhook"`
+}
+
+// CreatedWebhookResponse - единственный ответ, в котором возвращается секрет подписки
+type CreatedWebhookResponse struct {
+	Webhook dto.WebhookResponse `json:"webhook"`
+	Secret  string              `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Webhooks []dto.WebhookResponse `json:"webhooks"`
+}
+
+type WebhookDeliveriesResponse struct {
+	SubscriptionID int64                         `json:"subscription_id"`
+	Deliveries     []dto.WebhookDeliveryResponse `json:"deliveries"`
+}
+
+type IntegrationUsersResponse struct {
+	Provider string                               `json:"provider"`
+	Users    []dto.IntegrationUserMappingResponse `json:"users"`
+}
+
+type IntegrationProjectsResponse struct {
+	Provider string                                  `json:"provider"`
+	Projects []dto.IntegrationProjectMappingResponse `json:"projects"`
+}
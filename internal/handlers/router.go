@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"expvar"
 	"net/http"
 	"time"
 
@@ -14,32 +15,88 @@ func Router(
 	userHandler *UserHandler,
 	prHandler *PullRequestHandler,
 	statsHandler *StatisticsHandler,
+	absenceHandler *AbsenceHandler,
+	webhookHandler *WebhookHandler,
+	githubHandler *GitHubHandler,
+	gitlabHandler *GitLabHandler,
+	adminToken string,
 ) http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(LoggerMiddleware)
+	r.Use(ActorMiddleware)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Timeout(60 * time.Second))
 
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		WriteJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
 	})
+	// Метрики процесса и счётчики повторов транзакций (db_tx); в них есть командная строка процесса,
+	// поэтому отдаются только с токеном администратора
+	r.Handle("/debug/vars", requireAdmin(adminToken, expvar.Handler()))
 
 	r.Post("/team/add", teamHandler.AddTeam)
 	r.Get("/team/get", teamHandler.GetTeam)
+	r.Post("/team/rename", teamHandler.RenameTeam)
+	r.Post("/team/delete", teamHandler.DeleteTeam)
 	r.Post("/team/users/deactivate", teamHandler.MassDeactivateUsers)
+	r.Post("/team/members/add", teamHandler.AddMembers)
+	r.Post("/team/members/remove", teamHandler.RemoveMembers)
+	r.Post("/team/members/transfer", teamHandler.TransferUser)
+	r.Get("/team/settings", teamHandler.GetSettings)
+	r.Post("/team/settings/update", teamHandler.UpdateSettings)
+	r.Get("/team/fallbacks", teamHandler.GetFallbacks)
+	r.Post("/team/fallbacks/set", teamHandler.SetFallbacks)
+	r.Get("/team/codeowners", teamHandler.GetCodeOwners)
+	r.Post("/team/codeowners/upload", teamHandler.UploadCodeOwners)
+	r.Post("/team/codeowners/delete", teamHandler.DeleteCodeOwners)
+	r.Get("/team/rules", teamHandler.GetRules)
+	r.Post("/team/rules/set", teamHandler.SetRules)
 
 	r.Post("/users/setIsActive", userHandler.SetIsActive)
 	r.Get("/users/getReview", userHandler.GetReview)
+	r.Post("/users/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
+	r.Post("/users/setUsername", userHandler.SetUsername)
+	r.Post("/users/absences/create", absenceHandler.Create)
+	r.Get("/users/absences/list", absenceHandler.List)
+	r.Post("/users/absences/delete", absenceHandler.Delete)
 
 	r.Post("/pullRequest/create", prHandler.CreatePR)
+	r.Get("/pullRequest/list", prHandler.ListPRs)
+	r.Get("/pullRequest/get", prHandler.GetPR)
+	r.Post("/pullRequest/update", prHandler.UpdatePR)
 	r.Post("/pullRequest/merge", prHandler.MergePR)
 	r.Post("/pullRequest/reassign", prHandler.ReassignReviewer)
+	r.Post("/pullRequest/review", prHandler.SubmitReview)
+	r.Post("/pullRequest/ready", prHandler.MarkReady)
+	r.Post("/pullRequest/close", prHandler.ClosePR)
+	r.Post("/pullRequest/reopen", prHandler.ReopenPR)
+	r.Get("/pullRequest/rules", prHandler.ListRuleFirings)
 
 	r.Get("/statistics", statsHandler.GetStatistics)
 
+	r.Post("/webhooks/create", webhookHandler.Create)
+	r.Get("/webhooks/list", webhookHandler.List)
+	r.Get("/webhooks/get", webhookHandler.Get)
+	r.Post("/webhooks/update", webhookHandler.Update)
+	r.Post("/webhooks/delete", webhookHandler.Delete)
+	r.Get("/webhooks/deliveries", webhookHandler.Deliveries)
+
+	r.Post("/integrations/github/webhook", githubHandler.Webhook)
+	r.Get("/integrations/github/users", githubHandler.ListUsers)
+	r.Post("/integrations/github/users/map", githubHandler.MapUser)
+	r.Post("/integrations/github/users/unmap", githubHandler.UnmapUser)
+
+	r.Post("/integrations/gitlab/webhook", gitlabHandler.Webhook)
+	r.Get("/integrations/gitlab/users", gitlabHandler.ListUsers)
+	r.Post("/integrations/gitlab/users/map", gitlabHandler.MapUser)
+	r.Post("/integrations/gitlab/users/unmap", gitlabHandler.UnmapUser)
+	r.Get("/integrations/gitlab/projects", gitlabHandler.ListProjects)
+	r.Post("/integrations/gitlab/projects/map", gitlabHandler.MapProject)
+	r.Post("/integrations/gitlab/projects/unmap", gitlabHandler.UnmapProject)
+
 	return r
 }
 
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
 	"avito/internal/domain"
 	"avito/internal/dto"
@@ -18,15 +20,57 @@ func NewStatisticsHandler(statsService *service.StatisticsService) *StatisticsHa
 	}
 }
 
+// GetStatistics handles GET /statistics. Назначения и PR можно отфильтровать по метаданным:
+// repository, target_branch, label (можно повторять), min_lines, max_lines.
 func (h *StatisticsHandler) GetStatistics(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	stats, err := h.statsService.GetStatistics(ctx)
+	filter, ok := parsePRFilter(w, r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.statsService.GetStatistics(ctx, filter)
 	if err != nil {
-		WriteError(w, http.StatusInternalServerError, domain.ErrCodeInternalError, err.Error())
+		WriteAppError(w, err)
 		return
 	}
 
 	response := dto.StatisticsFromDomain(stats)
 	WriteJSON(w, http.StatusOK, response)
 }
+
+// parsePRFilter разбирает фильтр PR по метаданным из query; при ошибке пишет 400 и возвращает false
+func parsePRFilter(w http.ResponseWriter, r *http.Request) (domain.PRFilter, bool) {
+	query := r.URL.Query()
+	filter := domain.PRFilter{
+		Repository:   query.Get("repository"),
+		TargetBranch: query.Get("target_branch"),
+	}
+	for _, label := range query["label"] {
+		filter.Labels = append(filter.Labels, strings.ToLower(strings.TrimSpace(label)))
+	}
+
+	bounds := []struct {
+		name string
+		dest **int
+	}{
+		{"min_lines", &filter.MinLines},
+		{"max_lines", &filter.MaxLines},
+	}
+	for _, bound := range bounds {
+		name, dest := bound.name, bound.dest
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, name+" must be a non-negative integer")
+			return filter, false
+		}
+		*dest = &value
+	}
+
+	return filter, true
+}
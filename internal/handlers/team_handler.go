@@ -79,6 +79,55 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, response)
 }
 
+// RenameTeam handles POST /team/rename
+func (h *TeamHandler) RenameTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.RenameTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	team, err := h.teamService.RenameTeam(r.Context(), req.TeamName, req.NewTeamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamFromDomain(team))
+}
+
+// DeleteTeam handles POST /team/delete
+func (h *TeamHandler) DeleteTeam(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteTeamRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	prIDs, err := h.teamService.DeleteTeam(r.Context(), req.TeamName, req.OpenPRs, req.ReassignTo)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.DeleteTeamResponse{
+		TeamName:     req.TeamName,
+		OpenPRs:      req.OpenPRs,
+		ReassignTo:   req.ReassignTo,
+		PullRequests: prIDs,
+	})
+}
+
 // MassDeactivateUsers handles POST /team/users/deactivate
 func (h *TeamHandler) MassDeactivateUsers(w http.ResponseWriter, r *http.Request) {
 	var req dto.MassDeactivateRequest
@@ -99,3 +148,266 @@ func (h *TeamHandler) MassDeactivateUsers(w http.ResponseWriter, r *http.Request
 
 	WriteJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
+
+// AddMembers handles POST /team/members/add
+func (h *TeamHandler) AddMembers(w http.ResponseWriter, r *http.Request) {
+	var req dto.AddMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	team, err := h.teamService.AddMembers(r.Context(), req.TeamName, req.ToDomain())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamFromDomain(team))
+}
+
+// RemoveMembers handles POST /team/members/remove
+func (h *TeamHandler) RemoveMembers(w http.ResponseWriter, r *http.Request) {
+	var req dto.RemoveMembersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	team, err := h.teamService.RemoveMembers(r.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamFromDomain(team))
+}
+
+// TransferUser handles POST /team/members/transfer
+func (h *TeamHandler) TransferUser(w http.ResponseWriter, r *http.Request) {
+	var req dto.TransferUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	user, err := h.teamService.TransferUser(r.Context(), req.UserID, req.TeamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.UserFromDomain(user))
+}
+
+// GetSettings handles GET /team/settings
+func (h *TeamHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "team_name is required")
+		return
+	}
+
+	if err := dto.ValidateTeamName(teamName); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(r.Context(), teamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamSettingsFromDomain(settings))
+}
+
+// UpdateSettings handles POST /team/settings/update
+func (h *TeamHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateTeamSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	settings, err := h.teamService.UpdateSettings(r.Context(), req.TeamName, req.Apply)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamSettingsFromDomain(settings))
+}
+
+// GetFallbacks handles GET /team/fallbacks
+func (h *TeamHandler) GetFallbacks(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "team_name is required")
+		return
+	}
+
+	if err := dto.ValidateTeamName(teamName); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	fallbackTeams, err := h.teamService.GetFallbacks(r.Context(), teamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamFallbacks{TeamName: teamName, FallbackTeams: fallbackTeams})
+}
+
+// SetFallbacks handles POST /team/fallbacks/set
+func (h *TeamHandler) SetFallbacks(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamFallbacks
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	fallbackTeams, err := h.teamService.SetFallbacks(r.Context(), req.TeamName, req.FallbackTeams)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamFallbacks{TeamName: req.TeamName, FallbackTeams: fallbackTeams})
+}
+
+// GetRules handles GET /team/rules
+func (h *TeamHandler) GetRules(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "team_name is required")
+		return
+	}
+
+	if err := dto.ValidateTeamName(teamName); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	rules, err := h.teamService.GetRules(r.Context(), teamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamRulesFromDomain(teamName, rules))
+}
+
+// SetRules handles POST /team/rules/set
+func (h *TeamHandler) SetRules(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamRules
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	rules, err := h.teamService.SetRules(r.Context(), req.TeamName, req.ToDomain())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.TeamRulesFromDomain(req.TeamName, rules))
+}
+
+// GetCodeOwners handles GET /team/codeowners
+func (h *TeamHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "team_name is required")
+		return
+	}
+
+	if err := dto.ValidateTeamName(teamName); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	content, owners, err := h.teamService.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.CodeOwnersFromDomain(teamName, content, owners))
+}
+
+// UploadCodeOwners handles POST /team/codeowners/upload
+func (h *TeamHandler) UploadCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req dto.TeamCodeOwnersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	owners, err := h.teamService.SetCodeOwners(r.Context(), req.TeamName, req.Content)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.CodeOwnersFromDomain(req.TeamName, req.Content, owners))
+}
+
+// DeleteCodeOwners handles POST /team/codeowners/delete
+func (h *TeamHandler) DeleteCodeOwners(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteCodeOwnersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	if err := h.teamService.DeleteCodeOwners(r.Context(), req.TeamName); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
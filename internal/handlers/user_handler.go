@@ -48,7 +48,52 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	WriteJSON(w, http.StatusOK, response)
 }
 
-// GetReview handles GET /users/getReview
+// SetMaxOpenReviews handles POST /users/setMaxOpenReviews
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetMaxOpenReviewsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	user, err := h.userService.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.UserFromDomain(user))
+}
+
+// SetUsername handles POST /users/setUsername
+func (h *UserHandler) SetUsername(w http.ResponseWriter, r *http.Request) {
+	var req dto.SetUsernameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	user, err := h.userService.SetUsername(r.Context(), req.UserID, req.Username)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, dto.UserFromDomain(user))
+}
+
+// GetReview handles GET /users/getReview. Необязательные параметры: status, limit (без него - все PR),
+// cursor - next_cursor предыдущей страницы.
 func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
@@ -62,17 +107,39 @@ func (h *UserHandler) GetReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.userService.GetReviewPRs(r.Context(), userID, h.prRepo)
+	params := r.URL.Query()
+	query := domain.ReviewQuery{ReviewerID: userID, Status: params.Get("status")}
+	if !validStatusParam(w, query.Status) {
+		return
+	}
+
+	var ok bool
+	if query.Limit, ok = parseLimitParam(w, params.Get("limit")); !ok {
+		return
+	}
+
+	if raw := params.Get("cursor"); raw != "" {
+		cursor, err := dto.DecodePRCursor(raw)
+		if err != nil || cursor.SortBy != domain.PRSortCreatedAt {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid cursor")
+			return
+		}
+		query.After = cursor
+	}
+
+	page, err := h.userService.GetReviewPRs(r.Context(), query, h.prRepo)
 	if err != nil {
 		WriteAppError(w, err)
 		return
 	}
 
 	// Convert domain to DTO
-	dtoPRs := dto.PullRequestsShortFromDomain(prs)
+	dtoPRs := dto.PullRequestsShortFromDomain(page.PullRequests)
 
 	WriteJSON(w, http.StatusOK, UserReviewResponse{
 		UserID:       userID,
 		PullRequests: dtoPRs,
+		Total:        page.Total,
+		NextCursor:   dto.EncodePRCursor(page.Next),
 	})
 }
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/service"
+)
+
+// WebhookHandler handles webhook subscription endpoints
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// Create handles POST /webhooks/create
+func (h *WebhookHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	sub := req.ToDomain()
+	if err := h.webhookService.Create(r.Context(), sub); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusCreated, CreatedWebhookResponse{
+		Webhook: dto.WebhookFromDomain(sub),
+		Secret:  sub.Secret,
+	})
+}
+
+// List handles GET /webhooks/list
+func (h *WebhookHandler) List(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.webhookService.List(r.Context())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, WebhooksResponse{Webhooks: dto.WebhooksFromDomain(subs)})
+}
+
+// Get handles GET /webhooks/get
+func (h *WebhookHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseIDParam(w, r, "id")
+	if !ok {
+		return
+	}
+
+	sub, err := h.webhookService.Get(r.Context(), id)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, WebhookResponse{Webhook: dto.WebhookFromDomain(sub)})
+}
+
+// Update handles POST /webhooks/update
+func (h *WebhookHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	sub, err := h.webhookService.Update(r.Context(), req.ToDomain())
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, WebhookResponse{Webhook: dto.WebhookFromDomain(sub)})
+}
+
+// Delete handles POST /webhooks/delete
+func (h *WebhookHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req dto.DeleteWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "invalid request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), req.ID); err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Deliveries handles GET /webhooks/deliveries
+func (h *WebhookHandler) Deliveries(w http.ResponseWriter, r *http.Request) {
+	subscriptionID, ok := parseIDParam(w, r, "subscription_id")
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, "limit must be a positive integer")
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), subscriptionID, limit)
+	if err != nil {
+		WriteAppError(w, err)
+		return
+	}
+
+	WriteJSON(w, http.StatusOK, WebhookDeliveriesResponse{
+		SubscriptionID: subscriptionID,
+		Deliveries:     dto.WebhookDeliveriesFromDomain(deliveries),
+	})
+}
+
+// parseIDParam читает обязательный положительный числовой параметр запроса, при ошибке пишет ответ
+func parseIDParam(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, name+" is required")
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		WriteError(w, http.StatusBadRequest, domain.ErrCodeInvalidRequest, name+" must be a positive integer")
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type absenceRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewAbsenceRepository(db *sql.DB) AbsenceRepository {
+	return &absenceRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var absenceColumns = []string{"id", "user_id", "starts_at", "ends_at", "reason", "created_at", "reviews_reassigned_at"}
+
+func (r *absenceRepo) Create(ctx context.Context, absence *domain.Absence) error {
+	query, args, err := r.builder.
+		Insert("absences").
+		Columns("user_id", "starts_at", "ends_at", "reason").
+		Values(absence.UserID, absence.StartsAt, absence.EndsAt, absence.Reason).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return translateError(conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&absence.ID, &absence.CreatedAt))
+}
+
+func (r *absenceRepo) ListByUser(ctx context.Context, userID string) ([]domain.Absence, error) {
+	query, args, err := r.builder.
+		Select(absenceColumns...).
+		From("absences").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("starts_at").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	return scanAbsences(rows)
+}
+
+func (r *absenceRepo) Delete(ctx context.Context, absenceID int64) error {
+	query, args, err := r.builder.
+		Delete("absences").
+		Where(sq.Eq{"id": absenceID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "absence not found")
+	}
+
+	return nil
+}
+
+func (r *absenceRepo) GetStartedUnprocessed(ctx context.Context, now time.Time) ([]domain.Absence, error) {
+	query, args, err := r.builder.
+		Select(absenceColumns...).
+		From("absences").
+		Where(sq.LtOrEq{"starts_at": now}).
+		Where(sq.Gt{"ends_at": now}).
+		Where(sq.Eq{"reviews_reassigned_at": nil}).
+		OrderBy("starts_at").
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	return scanAbsences(rows)
+}
+
+func (r *absenceRepo) MarkReassigned(ctx context.Context, absenceID int64, at time.Time) error {
+	query, args, err := r.builder.
+		Update("absences").
+		Set("reviews_reassigned_at", at).
+		Where(sq.Eq{"id": absenceID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func scanAbsences(rows *sql.Rows) ([]domain.Absence, error) {
+	absences := []domain.Absence{}
+	for rows.Next() {
+		var a domain.Absence
+		if err := rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason, &a.CreatedAt, &a.ReviewsReassignedAt); err != nil {
+			return nil, err
+		}
+		absences = append(absences, a)
+	}
+	return absences, translateError(rows.Err())
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type assignmentEventRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewAssignmentEventRepository(db *sql.DB) AssignmentEventRepository {
+	return &assignmentEventRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *assignmentEventRepo) Add(ctx context.Context, events []domain.AssignmentEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("assignment_events").
+		Columns("pull_request_id", "event_type", "reviewer_id", "previous_reviewer_id", "reason", "actor")
+	for _, e := range events {
+		insert = insert.Values(e.PullRequestID, e.EventType, e.ReviewerID, e.PreviousReviewerID, e.Reason, e.Actor)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *assignmentEventRepo) ListByPR(ctx context.Context, prID string) ([]domain.AssignmentEvent, error) {
+	query, args, err := r.builder.
+		Select("id", "pull_request_id", "event_type", "reviewer_id", "previous_reviewer_id", "reason", "actor", "created_at").
+		From("assignment_events").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	events := []domain.AssignmentEvent{}
+	for rows.Next() {
+		var e domain.AssignmentEvent
+		if err := rows.Scan(&e.ID, &e.PullRequestID, &e.EventType, &e.ReviewerID, &e.PreviousReviewerID, &e.Reason, &e.Actor, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, translateError(rows.Err())
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type assignmentRuleRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewAssignmentRuleRepository(db *sql.DB) AssignmentRuleRepository {
+	return &assignmentRuleRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *assignmentRuleRepo) List(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
+	return r.list(ctx, sq.Eq{"team_name": teamName})
+}
+
+func (r *assignmentRuleRepo) ListByExtraTeam(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
+	return r.list(ctx, sq.Eq{"extra_team": teamName})
+}
+
+func (r *assignmentRuleRepo) list(ctx context.Context, cond sq.Sqlizer) ([]domain.AssignmentRule, error) {
+	query, args, err := r.builder.
+		Select(
+			"team_name", "name", "repository", "target_branch", "labels", "min_lines", "max_lines",
+			"reviewers", "COALESCE(extra_team, '')", "extra_reviewers",
+		).
+		From("assignment_rules").
+		Where(cond).
+		OrderBy("team_name", "priority").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	rules := []domain.AssignmentRule{}
+	for rows.Next() {
+		var rule domain.AssignmentRule
+		var minLines, maxLines sql.NullInt64
+		if err := rows.Scan(
+			&rule.TeamName, &rule.Name, &rule.When.Repository, &rule.When.TargetBranch, pq.Array(&rule.When.Labels),
+			&minLines, &maxLines, &rule.Reviewers, &rule.ExtraTeam, &rule.ExtraReviewers,
+		); err != nil {
+			return nil, err
+		}
+		rule.When.MinLines = nullIntPtr(minLines)
+		rule.When.MaxLines = nullIntPtr(maxLines)
+		rules = append(rules, rule)
+	}
+	return rules, translateError(rows.Err())
+}
+
+func nullIntPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
+
+// Replace заменяет правила команды целиком; приоритет - позиция в rules.
+// Удаление и вставка должны быть атомарны, поэтому вызывается внутри WithinTx.
+func (r *assignmentRuleRepo) Replace(ctx context.Context, teamName string, rules []domain.AssignmentRule) error {
+	deleteQuery, deleteArgs, err := r.builder.
+		Delete("assignment_rules").
+		Where(sq.Eq{"team_name": teamName}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return translateError(err)
+	}
+
+	if len(rules) == 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("assignment_rules").
+		Columns(
+			"team_name", "name", "priority", "repository", "target_branch", "labels", "min_lines", "max_lines",
+			"reviewers", "extra_team", "extra_reviewers",
+		)
+	for i, rule := range rules {
+		insert = insert.Values(
+			teamName, rule.Name, i, rule.When.Repository, rule.When.TargetBranch, pq.Array(nonNil(rule.When.Labels)),
+			rule.When.MinLines, rule.When.MaxLines,
+			rule.Reviewers, sq.Expr("NULLIF(?, '')", rule.ExtraTeam), rule.ExtraReviewers,
+		)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+func (r *assignmentRuleRepo) RecordFirings(ctx context.Context, firings []domain.RuleFiring) error {
+	if len(firings) == 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("assignment_rule_firings").
+		Columns(
+			"pull_request_id", "team_name", "rule_name", "reviewers", "extra_team", "extra_reviewers",
+			"assigned_reviewers", "source", "fired_at",
+		)
+	for _, f := range firings {
+		insert = insert.Values(
+			f.PullRequestID, f.TeamName, f.RuleName, f.Reviewers, f.ExtraTeam, f.ExtraReviewers,
+			pq.Array(nonNil(f.AssignedReviewers)), f.Source, f.FiredAt,
+		)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+func (r *assignmentRuleRepo) ListFirings(ctx context.Context, prID string) ([]domain.RuleFiring, error) {
+	query, args, err := r.builder.
+		Select(
+			"pull_request_id", "team_name", "rule_name", "reviewers", "extra_team", "extra_reviewers",
+			"assigned_reviewers", "source", "fired_at",
+		).
+		From("assignment_rule_firings").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("id").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	firings := []domain.RuleFiring{}
+	for rows.Next() {
+		var f domain.RuleFiring
+		if err := rows.Scan(
+			&f.PullRequestID, &f.TeamName, &f.RuleName, &f.Reviewers, &f.ExtraTeam, &f.ExtraReviewers,
+			pq.Array(&f.AssignedReviewers), &f.Source, &f.FiredAt,
+		); err != nil {
+			return nil, err
+		}
+		firings = append(firings, f)
+	}
+	return firings, translateError(rows.Err())
+}
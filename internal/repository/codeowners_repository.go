@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type codeOwnersRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewCodeOwnersRepository(db *sql.DB) CodeOwnersRepository {
+	return &codeOwnersRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *codeOwnersRepo) Get(ctx context.Context, teamName string) (string, error) {
+	query, args, err := r.builder.
+		Select("content").
+		From("team_codeowners").
+		Where(sq.Eq{"team_name": teamName}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var content string
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&content)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", translateError(err)
+	}
+
+	return content, nil
+}
+
+func (r *codeOwnersRepo) Upsert(ctx context.Context, teamName, content string) error {
+	query, args, err := r.builder.
+		Insert("team_codeowners").
+		Columns("team_name", "content").
+		Values(teamName, content).
+		Suffix(`ON CONFLICT (team_name) DO UPDATE SET
+			content = EXCLUDED.content,
+			updated_at = CURRENT_TIMESTAMP`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+func (r *codeOwnersRepo) Delete(ctx context.Context, teamName string) error {
+	query, args, err := r.builder.
+		Delete("team_codeowners").
+		Where(sq.Eq{"team_name": teamName}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "CODEOWNERS not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+// SQLSTATE кодов ошибок Postgres, которые переводятся в domain.AppError
+const (
+	pqUniqueViolation      = "23505"
+	pqForeignKeyViolation  = "23503"
+	pqNotNullViolation     = "23502"
+	pqCheckViolation       = "23514"
+	pqSerializationFailure = "40001"
+	pqDeadlockDetected     = "40P01"
+	pqStringTooLong        = "22001"
+	pqInvalidTextRepr      = "22P02"
+	pqNumericOutOfRange    = "22003"
+)
+
+// translateError переводит ошибку драйвера в domain.AppError со стабильным кодом.
+// Текст ошибки Postgres в сообщение не попадает: он раскрывает схему и уходит клиенту.
+// Нарушение внешнего ключа считается ссылкой на несуществующую запись; для удаления
+// записи, на которую ещё ссылаются, используется translateDeleteError.
+// Прочие ошибки (и nil) возвращаются как есть.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return domain.NewAppError(domain.ErrCodeAlreadyExists, "record already exists")
+	case pqForeignKeyViolation:
+		return domain.NewAppError(domain.ErrCodeNotFound, "referenced record not found")
+	case pqNotNullViolation, pqCheckViolation:
+		return domain.NewAppError(domain.ErrCodeConstraintViolation, "constraint violation")
+	case pqSerializationFailure, pqDeadlockDetected:
+		return domain.NewAppError(domain.ErrCodeConcurrentUpdate, "concurrent update, retry the request")
+	case pqStringTooLong, pqInvalidTextRepr, pqNumericOutOfRange:
+		return domain.NewAppError(domain.ErrCodeInvalidRequest, "invalid value")
+	default:
+		return err
+	}
+}
+
+// translateDeleteError переводит ошибку удаления записи. Нарушение внешнего ключа здесь
+// означает, что на запись ещё ссылаются: текст ошибки Postgres локализуется, поэтому
+// намерение передаётся вызывающим кодом, а не определяется по сообщению.
+func translateDeleteError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pqForeignKeyViolation {
+		return domain.NewAppError(domain.ErrCodeConstraintViolation, "record is still referenced")
+	}
+	return translateError(err)
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *pq.Error
+		wantCode string
+	}{
+		{"unique", &pq.Error{Code: "23505", Message: `duplicate key value violates unique constraint "teams_pkey"`}, domain.ErrCodeAlreadyExists},
+		{"missing reference", &pq.Error{Code: "23503", Message: `insert or update on table "pull_requests" violates foreign key constraint`}, domain.ErrCodeNotFound},
+		{"check", &pq.Error{Code: "23514", Message: `new row violates check constraint`}, domain.ErrCodeConstraintViolation},
+		{"serialization", &pq.Error{Code: "40001", Message: "could not serialize access"}, domain.ErrCodeConcurrentUpdate},
+		{"deadlock", &pq.Error{Code: "40P01", Message: "deadlock detected"}, domain.ErrCodeConcurrentUpdate},
+		{"too long", &pq.Error{Code: "22001", Message: "value too long for type character varying(255)"}, domain.ErrCodeInvalidRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appErr, ok := translateError(tt.err).(*domain.AppError)
+			if !ok {
+				t.Fatalf("translateError() = %T, want *domain.AppError", translateError(tt.err))
+			}
+			if appErr.Code != tt.wantCode {
+				t.Errorf("Code = %s, want %s", appErr.Code, tt.wantCode)
+			}
+			if strings.Contains(appErr.Message, tt.err.Message) {
+				t.Errorf("Message %q leaks driver message", appErr.Message)
+			}
+		})
+	}
+}
+
+func TestTranslateError_PassThrough(t *testing.T) {
+	if translateError(nil) != nil {
+		t.Error("translateError(nil) != nil")
+	}
+
+	plain := errors.New("connection refused")
+	if translateError(plain) != plain {
+		t.Error("non-driver error must be returned as is")
+	}
+
+	unknown := &pq.Error{Code: "XX000"}
+	if translateError(unknown) != unknown {
+		t.Error("unmapped SQLSTATE must be returned as is")
+	}
+}
+
+func TestTranslateError_Wrapped(t *testing.T) {
+	err := fmt.Errorf("insert team: %w", &pq.Error{Code: "23505"})
+
+	appErr, ok := translateError(err).(*domain.AppError)
+	if !ok || appErr.Code != domain.ErrCodeAlreadyExists {
+		t.Errorf("translateError() = %v, want ALREADY_EXISTS", translateError(err))
+	}
+}
+
+func TestTranslateDeleteError(t *testing.T) {
+	// Локализованный текст ошибки не влияет на классификацию
+	stillReferenced := &pq.Error{Code: "23503", Message: `UPDATE o DELETE en la tabla «teams» viola la llave foránea`}
+
+	appErr, ok := translateDeleteError(stillReferenced).(*domain.AppError)
+	if !ok || appErr.Code != domain.ErrCodeConstraintViolation {
+		t.Errorf("translateDeleteError() = %v, want CONSTRAINT_VIOLATION", translateDeleteError(stillReferenced))
+	}
+
+	appErr, ok = translateError(stillReferenced).(*domain.AppError)
+	if !ok || appErr.Code != domain.ErrCodeNotFound {
+		t.Errorf("translateError() = %v, want NOT_FOUND", translateError(stillReferenced))
+	}
+
+	conflict, ok := translateDeleteError(&pq.Error{Code: "40001"}).(*domain.AppError)
+	if !ok || conflict.Code != domain.ErrCodeConcurrentUpdate {
+		t.Errorf("translateDeleteError() must fall back to translateError, got %v", conflict)
+	}
+}
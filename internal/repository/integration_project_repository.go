@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type integrationProjectRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewIntegrationProjectRepository(db *sql.DB) IntegrationProjectRepository {
+	return &integrationProjectRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *integrationProjectRepo) Upsert(ctx context.Context, mapping *domain.IntegrationProjectMapping) error {
+	query, args, err := r.builder.
+		Insert("integration_project_teams").
+		Columns("provider", "project_path", "team_name").
+		Values(mapping.Provider, mapping.Project, mapping.TeamName).
+		Suffix(`ON CONFLICT (provider, project_path) DO UPDATE SET
+			team_name = EXCLUDED.team_name
+			RETURNING created_at`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return translateError(conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&mapping.CreatedAt))
+}
+
+func (r *integrationProjectRepo) GetTeam(ctx context.Context, provider, project string) (string, error) {
+	query, args, err := r.builder.
+		Select("team_name").
+		From("integration_project_teams").
+		Where(sq.Eq{"provider": provider, "project_path": project}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var teamName string
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&teamName)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", translateError(err)
+	}
+
+	return teamName, nil
+}
+
+func (r *integrationProjectRepo) List(ctx context.Context, provider string) ([]domain.IntegrationProjectMapping, error) {
+	query, args, err := r.builder.
+		Select("provider", "project_path", "team_name", "created_at").
+		From("integration_project_teams").
+		Where(sq.Eq{"provider": provider}).
+		OrderBy("project_path").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	mappings := []domain.IntegrationProjectMapping{}
+	for rows.Next() {
+		var m domain.IntegrationProjectMapping
+		if err := rows.Scan(&m.Provider, &m.Project, &m.TeamName, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, translateError(rows.Err())
+}
+
+func (r *integrationProjectRepo) Delete(ctx context.Context, provider, project string) error {
+	query, args, err := r.builder.
+		Delete("integration_project_teams").
+		Where(sq.Eq{"provider": provider, "project_path": project}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "mapping not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type integrationUserRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewIntegrationUserRepository(db *sql.DB) IntegrationUserRepository {
+	return &integrationUserRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *integrationUserRepo) Upsert(ctx context.Context, mapping *domain.IntegrationUserMapping) error {
+	query, args, err := r.builder.
+		Insert("integration_user_mappings").
+		Columns("provider", "external_login", "user_id").
+		Values(mapping.Provider, mapping.ExternalLogin, mapping.UserID).
+		Suffix(`ON CONFLICT (provider, external_login) DO UPDATE SET
+			user_id = EXCLUDED.user_id
+			RETURNING created_at`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return translateError(conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&mapping.CreatedAt))
+}
+
+func (r *integrationUserRepo) GetUserID(ctx context.Context, provider, externalLogin string) (string, error) {
+	query, args, err := r.builder.
+		Select("user_id").
+		From("integration_user_mappings").
+		Where(sq.Eq{"provider": provider, "external_login": externalLogin}).
+		ToSql()
+	if err != nil {
+		return "", err
+	}
+
+	var userID string
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return "", domain.NewAppError(domain.ErrCodeNotFound, fmt.Sprintf("%s login %s is not mapped to a user", provider, externalLogin))
+	}
+	if err != nil {
+		return "", translateError(err)
+	}
+
+	return userID, nil
+}
+
+func (r *integrationUserRepo) List(ctx context.Context, provider string) ([]domain.IntegrationUserMapping, error) {
+	query, args, err := r.builder.
+		Select("provider", "external_login", "user_id", "created_at").
+		From("integration_user_mappings").
+		Where(sq.Eq{"provider": provider}).
+		OrderBy("external_login").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	mappings := []domain.IntegrationUserMapping{}
+	for rows.Next() {
+		var m domain.IntegrationUserMapping
+		if err := rows.Scan(&m.Provider, &m.ExternalLogin, &m.UserID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return mappings, translateError(rows.Err())
+}
+
+func (r *integrationUserRepo) Delete(ctx context.Context, provider, externalLogin string) error {
+	query, args, err := r.builder.
+		Delete("integration_user_mappings").
+		Where(sq.Eq{"provider": provider, "external_login": externalLogin}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "mapping not found")
+	}
+
+	return nil
+}
@@ -2,45 +2,164 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"avito/internal/domain"
 )
 
 type TeamRepository interface {
-	Create(ctx context.Context, tx *sql.Tx, teamName string) error
+	Create(ctx context.Context, team *domain.Team) error
 	Get(ctx context.Context, teamName string) (*domain.Team, error)
 	Exists(ctx context.Context, teamName string) (bool, error)
+	// Rename меняет имя команды; ссылки на команду обновляются каскадом
+	Rename(ctx context.Context, oldName, newName string) error
+	// Delete удаляет команду вместе с её настройками; участники остаются без команды
+	Delete(ctx context.Context, teamName string) error
+}
+
+type TeamSettingsRepository interface {
+	Get(ctx context.Context, teamName string) (*domain.TeamSettings, error)
+	Upsert(ctx context.Context, settings *domain.TeamSettings) error
 }
 
 type UserRepository interface {
-	Create(ctx context.Context, tx *sql.Tx, user *domain.User) error
+	// AddToTeam не трогает пользователя, уже состоящего в команде, и тогда возвращает false
+	AddToTeam(ctx context.Context, user *domain.User) (bool, error)
 	Update(ctx context.Context, user *domain.User) error
 	Get(ctx context.Context, userID string) (*domain.User, error)
+	// GetForUpdate блокирует строку пользователя до конца транзакции, вызывается внутри WithinTx
+	GetForUpdate(ctx context.Context, userID string) (*domain.User, error)
 	GetByTeam(ctx context.Context, teamName string) ([]domain.User, error)
-	GetActiveTeammates(ctx context.Context, authorID string, limit int) ([]domain.User, error)
-	FindReplacementReviewer(ctx context.Context, tx *sql.Tx, teamName string, excludeIDs []string) (*domain.User, error)
+	// GetReviewCandidates блокирует строки кандидатов на чтение: деактивация кандидата дождётся конца транзакции
+	GetReviewCandidates(ctx context.Context, teamName string, excludeIDs []string) ([]domain.ReviewCandidate, error)
 	SetActive(ctx context.Context, userID string, isActive bool) error
-	DeactivateMany(ctx context.Context, tx *sql.Tx, userIDs []string) error
-	GetActiveUsersByTeam(ctx context.Context, tx *sql.Tx, teamName string) ([]domain.User, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, limit *int) error
+	DeactivateMany(ctx context.Context, userIDs []string) error
+	SetUsername(ctx context.Context, userID, username string) error
+	SetTeam(ctx context.Context, userID, teamName string) error
+	RemoveFromTeam(ctx context.Context, userIDs []string) error
 }
 
 type PullRequestRepository interface {
-	Create(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error
+	Create(ctx context.Context, pr *domain.PullRequest) error
 	Get(ctx context.Context, prID string) (*domain.PullRequest, error)
-	GetForUpdate(ctx context.Context, tx *sql.Tx, prID string) (*domain.PullRequest, error)
-	Update(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error
+	GetForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error)
+	// Update сохраняет PR и записывает увеличенную версию в pr.Version
+	Update(ctx context.Context, pr *domain.PullRequest) error
+	// GetVersion возвращает текущую версию PR; изменения ревьюверов тоже увеличивают версию
+	GetVersion(ctx context.Context, prID string) (int64, error)
 	Exists(ctx context.Context, prID string) (bool, error)
-	GetByReviewer(ctx context.Context, userID string) ([]domain.PullRequestShort, error)
-	AddReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error
-	RemoveReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error
+	// GetByReviewer возвращает PR ревьювера от новых к старым
+	GetByReviewer(ctx context.Context, query domain.ReviewQuery) ([]domain.PullRequestShort, error)
+	CountByReviewer(ctx context.Context, userID, status string) (int, error)
+	List(ctx context.Context, query domain.PRListQuery) ([]domain.PullRequest, error)
+	AddReviewer(ctx context.Context, prID, userID string) error
+	RemoveReviewer(ctx context.Context, prID, userID string) error
 	GetReviewers(ctx context.Context, prID string) ([]string, error)
-	GetOpenAssignmentsByReviewers(ctx context.Context, tx *sql.Tx, reviewerIDs []string) ([]domain.ReviewAssignment, error)
-	ReplaceReviewersBulk(ctx context.Context, tx *sql.Tx, replacements []domain.ReviewReplacement) error
-	GetReviewersByPRs(ctx context.Context, tx *sql.Tx, prIDs []string) (map[string][]string, error)
-	RemoveReviewersBulk(ctx context.Context, tx *sql.Tx, assignments []domain.ReviewAssignment) error
+	GetOpenAssignmentsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.ReviewAssignment, error)
+	ReplaceReviewersBulk(ctx context.Context, replacements []domain.ReviewReplacement) error
+	GetReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error)
+	RemoveReviewersBulk(ctx context.Context, assignments []domain.ReviewAssignment) error
+	// ListUnfinishedByTeam блокирует черновики и открытые PR команды до конца транзакции, вызывается внутри WithinTx
+	ListUnfinishedByTeam(ctx context.Context, teamName string) ([]domain.PullRequest, error)
+	SetReviewTeam(ctx context.Context, prIDs []string, teamName string) error
+}
+
+type TeamFallbackRepository interface {
+	// Get возвращает запасные команды в порядке приоритета
+	Get(ctx context.Context, teamName string) ([]string, error)
+	Replace(ctx context.Context, teamName string, fallbackTeams []string) error
+}
+
+type CodeOwnersRepository interface {
+	// Get возвращает файл CODEOWNERS команды; пустая строка - файл не загружен
+	Get(ctx context.Context, teamName string) (string, error)
+	Upsert(ctx context.Context, teamName, content string) error
+	Delete(ctx context.Context, teamName string) error
+}
+
+type AssignmentRuleRepository interface {
+	// List возвращает правила команды в порядке приоритета
+	List(ctx context.Context, teamName string) ([]domain.AssignmentRule, error)
+	// ListByExtraTeam возвращает правила, добирающие ревьюверов из команды teamName
+	ListByExtraTeam(ctx context.Context, teamName string) ([]domain.AssignmentRule, error)
+	Replace(ctx context.Context, teamName string, rules []domain.AssignmentRule) error
+	RecordFirings(ctx context.Context, firings []domain.RuleFiring) error
+	// ListFirings возвращает сработавшие на PR правила в порядке срабатывания
+	ListFirings(ctx context.Context, prID string) ([]domain.RuleFiring, error)
+}
+
+type AssignmentEventRepository interface {
+	Add(ctx context.Context, events []domain.AssignmentEvent) error
+	// ListByPR возвращает историю назначений PR в хронологическом порядке
+	ListByPR(ctx context.Context, prID string) ([]domain.AssignmentEvent, error)
+}
+
+type ReviewQueueRepository interface {
+	Enqueue(ctx context.Context, prID, teamName string, slots int) error
+	// ListByTeam блокирует и возвращает отложенные места команды по открытым PR в порядке очереди
+	ListByTeam(ctx context.Context, teamName string) ([]domain.QueuedReview, error)
+	Delete(ctx context.Context, id int64) error
+	DeleteByPR(ctx context.Context, prID string) error
+	CountByPR(ctx context.Context, prID string) (int, error)
+	GetQueuedTeams(ctx context.Context) ([]string, error)
+}
+
+type ReviewVerdictRepository interface {
+	// Create сохраняет вердикт и увеличивает версию PR
+	Create(ctx context.Context, verdict *domain.ReviewVerdict) error
+	// GetLatestByPR возвращает последний вердикт каждого ревьювера, когда-либо отвечавшего по PR
+	GetLatestByPR(ctx context.Context, prID string) ([]domain.ReviewVerdict, error)
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, eventType string, payload []byte) error
+	// ClaimUndispatched блокирует ещё не разосланные события в порядке появления
+	ClaimUndispatched(ctx context.Context, limit int) ([]domain.OutboxEvent, error)
+	MarkDispatched(ctx context.Context, eventIDs []int64) error
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, sub *domain.WebhookSubscription) error
+	Get(ctx context.Context, id int64) (*domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]domain.WebhookSubscription, error)
+	ListActive(ctx context.Context) ([]domain.WebhookSubscription, error)
+	Update(ctx context.Context, sub *domain.WebhookSubscription) error
+	Delete(ctx context.Context, id int64) error
+	CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// ClaimDue берёт в работу доставки, время которых пришло, откладывая их повтор на lease
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDispatch, error)
+	SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error)
+}
+
+type IntegrationUserRepository interface {
+	// Upsert сохраняет соответствие, перезаписывая пользователя для уже известного логина
+	Upsert(ctx context.Context, mapping *domain.IntegrationUserMapping) error
+	GetUserID(ctx context.Context, provider, externalLogin string) (string, error)
+	List(ctx context.Context, provider string) ([]domain.IntegrationUserMapping, error)
+	Delete(ctx context.Context, provider, externalLogin string) error
+}
+
+type IntegrationProjectRepository interface {
+	Upsert(ctx context.Context, mapping *domain.IntegrationProjectMapping) error
+	// GetTeam возвращает команду проекта; пустая строка - проект не привязан
+	GetTeam(ctx context.Context, provider, project string) (string, error)
+	List(ctx context.Context, provider string) ([]domain.IntegrationProjectMapping, error)
+	Delete(ctx context.Context, provider, project string) error
+}
+
+type AbsenceRepository interface {
+	Create(ctx context.Context, absence *domain.Absence) error
+	ListByUser(ctx context.Context, userID string) ([]domain.Absence, error)
+	Delete(ctx context.Context, absenceID int64) error
+	// GetStartedUnprocessed блокирует начавшиеся, но ещё не обработанные отсутствия
+	GetStartedUnprocessed(ctx context.Context, now time.Time) ([]domain.Absence, error)
+	MarkReassigned(ctx context.Context, absenceID int64, at time.Time) error
 }
 
 type TransactionManager interface {
-	BeginTx(ctx context.Context) (*sql.Tx, error)
+	// WithinTx выполняет fn в транзакции с режимом opts, переданной через ctx;
+	// конфликты сериализации повторяются автоматически
+	WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error
 }
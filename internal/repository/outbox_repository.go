@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type outboxRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *outboxRepo) Add(ctx context.Context, eventType string, payload []byte) error {
+	query, args, err := r.builder.
+		Insert("outbox_events").
+		Columns("event_type", "payload").
+		Values(eventType, string(payload)).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *outboxRepo) ClaimUndispatched(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
+	query, args, err := r.builder.
+		Select("id", "event_type", "payload", "created_at").
+		From("outbox_events").
+		Where(sq.Eq{"dispatched_at": nil}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		Suffix("FOR UPDATE SKIP LOCKED").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	events := []domain.OutboxEvent{}
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := rows.Scan(&e.ID, &e.EventType, &e.Payload, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, translateError(rows.Err())
+}
+
+func (r *outboxRepo) MarkDispatched(ctx context.Context, eventIDs []int64) error {
+	if len(eventIDs) == 0 {
+		return nil
+	}
+
+	query, args, err := r.builder.
+		Update("outbox_events").
+		Set("dispatched_at", sq.Expr("CURRENT_TIMESTAMP")).
+		Where(sq.Eq{"id": eventIDs}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -24,28 +25,83 @@ func NewPullRequestRepository(db *sql.DB) PullRequestRepository {
 	}
 }
 
-func (r *prRepo) Create(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error {
+func (r *prRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
 	query, args, err := r.builder.
 		Insert("pull_requests").
-		Columns("id", "name", "author_id", "status", "created_at").
-		Values(pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt).
+		Columns(
+			"id", "name", "author_id", "status", "created_at", "review_team", "changed_files",
+			"repository", "source_branch", "target_branch", "url", "labels",
+			"additions", "deletions", "changed_files_count",
+		).
+		Values(
+			pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.CreatedAt,
+			sq.Expr("NULLIF(?, '')", pr.ReviewTeam), pq.Array(nonNil(pr.ChangedFiles)),
+			pr.Repository, pr.SourceBranch, pr.TargetBranch, pr.URL, pq.Array(nonNil(pr.Labels)),
+			pr.Additions, pr.Deletions, pr.ChangedFilesCount,
+		).
+		Suffix("ON CONFLICT (id) DO NOTHING").
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		err = translateError(err)
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) && appErr.Code == domain.ErrCodeNotFound {
+			// Автор или команда ревьюверов удалены параллельно с созданием PR
+			return domain.NewAppError(domain.ErrCodeNotFound, "author or review team not found")
+		}
+		return err
+	}
+
+	// Параллельное создание с тем же id дожидается первой транзакции и ничего не вставляет
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodePRExists, "PR id already exists")
 	}
 
-	return err
+	return nil
+}
+
+// prColumns - колонки PR в порядке полей scanPR
+var prColumns = []string{
+	"id", "name", "author_id", "status", "created_at", "merged_at", "closed_at", "merge_forced",
+	"COALESCE(merge_forced_by, '')",
+	"COALESCE(review_team, '')", "changed_files",
+	"repository", "source_branch", "target_branch", "url", "labels",
+	"additions", "deletions", "changed_files_count", "version",
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanPR(row rowScanner, pr *domain.PullRequest) error {
+	return row.Scan(
+		&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.ClosedAt, &pr.MergeForced,
+		&pr.MergeForcedBy,
+		&pr.ReviewTeam, pq.Array(&pr.ChangedFiles),
+		&pr.Repository, &pr.SourceBranch, &pr.TargetBranch, &pr.URL, pq.Array(&pr.Labels),
+		&pr.Additions, &pr.Deletions, &pr.ChangedFilesCount, &pr.Version,
+	)
+}
+
+// nonNil - массивы пишутся как '{}', а не NULL
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
 }
 
 func (r *prRepo) Get(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	query, args, err := r.builder.
-		Select("id", "name", "author_id", "status", "created_at", "merged_at").
+		Select(prColumns...).
 		From("pull_requests").
 		Where(sq.Eq{"id": prID}).
 		ToSql()
@@ -54,15 +110,13 @@ func (r *prRepo) Get(ctx context.Context, prID string) (*domain.PullRequest, err
 	}
 
 	var pr domain.PullRequest
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
-	)
+	err = scanPR(conn(ctx, r.db).QueryRowContext(ctx, query, args...), &pr)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.NewAppError(domain.ErrCodeNotFound, "PR not found")
 	}
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	// reviewers
@@ -75,9 +129,9 @@ func (r *prRepo) Get(ctx context.Context, prID string) (*domain.PullRequest, err
 	return &pr, nil
 }
 
-func (r *prRepo) GetForUpdate(ctx context.Context, tx *sql.Tx, prID string) (*domain.PullRequest, error) {
+func (r *prRepo) GetForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	query, args, err := r.builder.
-		Select("id", "name", "author_id", "status", "created_at", "merged_at").
+		Select(prColumns...).
 		From("pull_requests").
 		Where(sq.Eq{"id": prID}).
 		Suffix("FOR UPDATE").
@@ -88,24 +142,16 @@ func (r *prRepo) GetForUpdate(ctx context.Context, tx *sql.Tx, prID string) (*do
 
 	var pr domain.PullRequest
 
-	if tx != nil {
-		err = tx.QueryRowContext(ctx, query, args...).Scan(
-			&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
-		)
-	} else {
-		err = r.db.QueryRowContext(ctx, query, args...).Scan(
-			&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
-		)
-	}
+	err = scanPR(conn(ctx, r.db).QueryRowContext(ctx, query, args...), &pr)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.NewAppError(domain.ErrCodeNotFound, "PR not found")
 	}
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
-	// Get reviewers
+	// Ревьюверы читаются в той же транзакции, что и заблокированный PR
 	reviewers, err := r.GetReviewers(ctx, prID)
 	if err != nil {
 		return nil, err
@@ -115,26 +161,74 @@ func (r *prRepo) GetForUpdate(ctx context.Context, tx *sql.Tx, prID string) (*do
 	return &pr, nil
 }
 
-func (r *prRepo) Update(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error {
+func (r *prRepo) Update(ctx context.Context, pr *domain.PullRequest) error {
 	query, args, err := r.builder.
 		Update("pull_requests").
 		Set("name", pr.Name).
 		Set("author_id", pr.AuthorID).
 		Set("status", pr.Status).
 		Set("merged_at", pr.MergedAt).
+		Set("closed_at", pr.ClosedAt).
+		Set("merge_forced", pr.MergeForced).
+		Set("merge_forced_by", sq.Expr("NULLIF(?, '')", pr.MergeForcedBy)).
+		Set("repository", pr.Repository).
+		Set("source_branch", pr.SourceBranch).
+		Set("target_branch", pr.TargetBranch).
+		Set("url", pr.URL).
+		Set("labels", pq.Array(nonNil(pr.Labels))).
+		Set("additions", pr.Additions).
+		Set("deletions", pr.Deletions).
+		Set("changed_files_count", pr.ChangedFilesCount).
+		Set("version", sq.Expr("version + 1")).
 		Where(sq.Eq{"id": pr.ID}).
+		Suffix("RETURNING version").
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&pr.Version)
+	if err == sql.ErrNoRows {
+		return domain.NewAppError(domain.ErrCodeNotFound, "PR not found")
+	}
+
+	return translateError(err)
+}
+
+func (r *prRepo) GetVersion(ctx context.Context, prID string) (int64, error) {
+	query, args, err := r.builder.
+		Select("version").
+		From("pull_requests").
+		Where(sq.Eq{"id": prID}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var version int64
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, domain.NewAppError(domain.ErrCodeNotFound, "PR not found")
+	}
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return version, nil
+}
+
+// bumpVersions увеличивает версию PR после изменения состава ревьюверов
+func (r *prRepo) bumpVersions(ctx context.Context, prIDs []string) error {
+	query, args, err := r.builder.
+		Update("pull_requests").
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": prIDs}).
+		ToSql()
+	if err != nil {
+		return err
 	}
 
-	return err
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
 }
 
 func (r *prRepo) Exists(ctx context.Context, prID string) (bool, error) {
@@ -149,41 +243,240 @@ func (r *prRepo) Exists(ctx context.Context, prID string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exists)
-	return exists, err
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&exists)
+	return exists, translateError(err)
 }
 
-func (r *prRepo) GetByReviewer(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
+// teamPRCond - PR, которые ревьюит команда: явно заданная review_team, иначе команда автора
+func teamPRCond(teamName string) sq.Sqlizer {
+	return sq.Or{
+		sq.Eq{"pr.review_team": teamName},
+		sq.And{
+			sq.Eq{"pr.review_team": nil},
+			sq.Expr("pr.author_id IN (SELECT id FROM users WHERE team_name = ?)", teamName),
+		},
+	}
+}
+
+// ListUnfinishedByTeam блокирует и возвращает черновики и открытые PR, которые ревьюит команда
+func (r *prRepo) ListUnfinishedByTeam(ctx context.Context, teamName string) ([]domain.PullRequest, error) {
 	query, args, err := r.builder.
-		Select("pr.id", "pr.name", "pr.author_id", "pr.status").
+		Select(prColumns...).
 		From("pull_requests pr").
-		Join("pr_reviewers prr ON pr.id = prr.pull_request_id").
-		Where(sq.Eq{"prr.user_id": userID}).
-		OrderBy("pr.created_at DESC").
+		Where(sq.And{
+			sq.Eq{"pr.status": []string{domain.PRStatusDraft, domain.PRStatusOpen}},
+			teamPRCond(teamName),
+		}).
+		OrderBy("pr.id").
+		Suffix("FOR UPDATE").
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	prs := []domain.PullRequest{}
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := scanPR(rows, &pr); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	ids := make([]string, len(prs))
+	for i := range prs {
+		ids[i] = prs[i].ID
+	}
+	reviewers, err := r.GetReviewersByPRs(ctx, ids)
 	if err != nil {
 		return nil, err
 	}
+	for i := range prs {
+		prs[i].AssignedReviewers = nonNil(reviewers[prs[i].ID])
+	}
+	return prs, nil
+}
+
+func (r *prRepo) SetReviewTeam(ctx context.Context, prIDs []string, teamName string) error {
+	query, args, err := r.builder.
+		Update("pull_requests").
+		Set("review_team", teamName).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": prIDs}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+// List возвращает до query.Limit PR по убыванию поля сортировки, начиная после query.After
+func (r *prRepo) List(ctx context.Context, query domain.PRListQuery) ([]domain.PullRequest, error) {
+	sortColumn := "pr.created_at"
+	if query.SortBy == domain.PRSortMergedAt {
+		sortColumn = "pr.merged_at"
+	}
+
+	cond := prFilterCond(query.PRFilter)
+	if query.SortBy == domain.PRSortMergedAt {
+		cond = append(cond, sq.NotEq{"pr.merged_at": nil})
+	}
+	if query.Status != "" {
+		cond = append(cond, sq.Eq{"pr.status": query.Status})
+	}
+	if query.AuthorID != "" {
+		cond = append(cond, sq.Eq{"pr.author_id": query.AuthorID})
+	}
+	if query.ReviewerID != "" {
+		cond = append(cond, sq.Expr(
+			"EXISTS (SELECT 1 FROM pr_reviewers prr WHERE prr.pull_request_id = pr.id AND prr.user_id = ?)", query.ReviewerID,
+		))
+	}
+	if query.TeamName != "" {
+		cond = append(cond, teamPRCond(query.TeamName))
+	}
+	if query.NameContains != "" {
+		cond = append(cond, sq.ILike{"pr.name": "%" + escapeLike(query.NameContains) + "%"})
+	}
+	if query.CreatedFrom != nil {
+		cond = append(cond, sq.GtOrEq{"pr.created_at": *query.CreatedFrom})
+	}
+	if query.CreatedTo != nil {
+		cond = append(cond, sq.Lt{"pr.created_at": *query.CreatedTo})
+	}
+	if query.MergedFrom != nil {
+		cond = append(cond, sq.GtOrEq{"pr.merged_at": *query.MergedFrom})
+	}
+	if query.MergedTo != nil {
+		cond = append(cond, sq.Lt{"pr.merged_at": *query.MergedTo})
+	}
+	if query.After != nil {
+		cond = append(cond, sq.Expr(fmt.Sprintf("(%s, pr.id) < (?, ?)", sortColumn), query.After.Value, query.After.ID))
+	}
+
+	sqlQuery, args, err := r.builder.
+		Select(prColumns...).
+		From("pull_requests pr").
+		Where(cond).
+		OrderBy(sortColumn+" DESC", "pr.id DESC").
+		Limit(uint64(query.Limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	prs := make([]domain.PullRequest, 0)
+	prIDs := make([]string, 0)
+	for rows.Next() {
+		var pr domain.PullRequest
+		if err := scanPR(rows, &pr); err != nil {
+			return nil, err
+		}
+		prs = append(prs, pr)
+		prIDs = append(prIDs, pr.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, translateError(err)
+	}
+
+	reviewers, err := r.GetReviewersByPRs(ctx, prIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range prs {
+		prs[i].AssignedReviewers = nonNil(reviewers[prs[i].ID])
+	}
+
+	return prs, nil
+}
+
+// escapeLike экранирует спецсимволы LIKE, чтобы подстрока искалась буквально
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (r *prRepo) GetByReviewer(ctx context.Context, query domain.ReviewQuery) ([]domain.PullRequestShort, error) {
+	cond := reviewerCond(query.ReviewerID, query.Status)
+	if query.After != nil {
+		cond = append(cond, sq.Expr("(pr.created_at, pr.id) < (?, ?)", query.After.Value, query.After.ID))
+	}
+
+	builder := r.builder.
+		Select("pr.id", "pr.name", "pr.author_id", "pr.status", "pr.created_at").
+		From("pull_requests pr").
+		Join("pr_reviewers prr ON pr.id = prr.pull_request_id").
+		Where(cond).
+		OrderBy("pr.created_at DESC", "pr.id DESC")
+	if query.Limit > 0 {
+		builder = builder.Limit(uint64(query.Limit))
+	}
+
+	sqlQuery, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	defer rows.Close()
 
 	prs := make([]domain.PullRequestShort, 0)
 	for rows.Next() {
 		var pr domain.PullRequestShort
-		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status); err != nil {
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt); err != nil {
 			return nil, err
 		}
 		prs = append(prs, pr)
 	}
 
-	return prs, rows.Err()
+	return prs, translateError(rows.Err())
+}
+
+func (r *prRepo) CountByReviewer(ctx context.Context, userID, status string) (int, error) {
+	query, args, err := r.builder.
+		Select("COUNT(*)").
+		From("pull_requests pr").
+		Join("pr_reviewers prr ON pr.id = prr.pull_request_id").
+		Where(reviewerCond(userID, status)).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, translateError(err)
+}
+
+// reviewerCond - PR, назначенные ревьюверу; пустой status - в любом статусе
+func reviewerCond(userID, status string) sq.And {
+	cond := sq.And{sq.Eq{"prr.user_id": userID}}
+	if status != "" {
+		cond = append(cond, sq.Eq{"pr.status": status})
+	}
+	return cond
 }
 
-func (r *prRepo) AddReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error {
+func (r *prRepo) AddReviewer(ctx context.Context, prID, userID string) error {
 	query, args, err := r.builder.
 		Insert("pr_reviewers").
 		Columns("pull_request_id", "user_id").
@@ -193,16 +486,14 @@ func (r *prRepo) AddReviewer(ctx context.Context, tx *sql.Tx, prID, userID strin
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return translateError(err)
 	}
 
-	return err
+	return r.bumpVersions(ctx, []string{prID})
 }
 
-func (r *prRepo) RemoveReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error {
+func (r *prRepo) RemoveReviewer(ctx context.Context, prID, userID string) error {
 	query, args, err := r.builder.
 		Delete("pr_reviewers").
 		Where(sq.Eq{"pull_request_id": prID, "user_id": userID}).
@@ -211,13 +502,11 @@ func (r *prRepo) RemoveReviewer(ctx context.Context, tx *sql.Tx, prID, userID st
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+		return translateError(err)
 	}
 
-	return err
+	return r.bumpVersions(ctx, []string{prID})
 }
 
 func (r *prRepo) GetReviewers(ctx context.Context, prID string) ([]string, error) {
@@ -231,9 +520,9 @@ func (r *prRepo) GetReviewers(ctx context.Context, prID string) ([]string, error
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -246,10 +535,10 @@ func (r *prRepo) GetReviewers(ctx context.Context, prID string) ([]string, error
 		reviewers = append(reviewers, userID)
 	}
 
-	return reviewers, rows.Err()
+	return reviewers, translateError(rows.Err())
 }
 
-func (r *prRepo) GetOpenAssignmentsByReviewers(ctx context.Context, tx *sql.Tx, reviewerIDs []string) ([]domain.ReviewAssignment, error) {
+func (r *prRepo) GetOpenAssignmentsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.ReviewAssignment, error) {
 	query := `
 		SELECT prr.pull_request_id, prr.user_id, pr.author_id
 		FROM pr_reviewers prr
@@ -257,17 +546,10 @@ func (r *prRepo) GetOpenAssignmentsByReviewers(ctx context.Context, tx *sql.Tx,
 		WHERE prr.user_id = ANY($1) AND pr.status = 'OPEN'
 	`
 
-	var rows *sql.Rows
-	var err error
-
-	if tx != nil {
-		rows, err = tx.QueryContext(ctx, query, pq.Array(reviewerIDs))
-	} else {
-		rows, err = r.db.QueryContext(ctx, query, pq.Array(reviewerIDs))
-	}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(reviewerIDs))
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -279,55 +561,50 @@ func (r *prRepo) GetOpenAssignmentsByReviewers(ctx context.Context, tx *sql.Tx,
 		}
 		assignments = append(assignments, a)
 	}
-	return assignments, rows.Err()
+	return assignments, translateError(rows.Err())
 }
 
-func (r *prRepo) ReplaceReviewersBulk(ctx context.Context, tx *sql.Tx, replacements []domain.ReviewReplacement) error {
+func (r *prRepo) ReplaceReviewersBulk(ctx context.Context, replacements []domain.ReviewReplacement) error {
 	if len(replacements) == 0 {
 		return nil
 	}
 
 	valueStrings := make([]string, 0, len(replacements))
 	valueArgs := make([]interface{}, 0, len(replacements)*3)
+	prIDs := make([]string, 0, len(replacements))
 
 	for i, rep := range replacements {
 		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d)", i*3+1, i*3+2, i*3+3))
 		valueArgs = append(valueArgs, rep.PullRequestID, rep.OldUserID, rep.NewUserID)
+		prIDs = append(prIDs, rep.PullRequestID)
 	}
 
 	query := fmt.Sprintf(`
 		UPDATE pr_reviewers AS t 
-		SET user_id = v.new_user_id 
+		SET user_id = v.new_user_id, assigned_at = CURRENT_TIMESTAMP
 		FROM (VALUES %s) AS v(pr_id, old_user_id, new_user_id) 
 		WHERE t.pull_request_id = v.pr_id AND t.user_id = v.old_user_id
 	`, strings.Join(valueStrings, ","))
 
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, valueArgs...)
-		return err
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, valueArgs...); err != nil {
+		return translateError(err)
 	}
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
-	return err
+	return r.bumpVersions(ctx, prIDs)
 }
 
-func (r *prRepo) GetReviewersByPRs(ctx context.Context, tx *sql.Tx, prIDs []string) (map[string][]string, error) {
+func (r *prRepo) GetReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
 	if len(prIDs) == 0 {
 		return map[string][]string{}, nil
 	}
 
-	query := `SELECT pull_request_id, user_id FROM pr_reviewers WHERE pull_request_id = ANY($1)`
+	// Порядок ревьюверов совпадает с GetReviewers, чтобы списки и карточка PR не расходились
+	query := `SELECT pull_request_id, user_id FROM pr_reviewers WHERE pull_request_id = ANY($1)
+		ORDER BY pull_request_id, assigned_at`
 
-	var rows *sql.Rows
-	var err error
-
-	if tx != nil {
-		rows, err = tx.QueryContext(ctx, query, pq.Array(prIDs))
-	} else {
-		rows, err = r.db.QueryContext(ctx, query, pq.Array(prIDs))
-	}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, pq.Array(prIDs))
 
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -339,20 +616,22 @@ func (r *prRepo) GetReviewersByPRs(ctx context.Context, tx *sql.Tx, prIDs []stri
 		}
 		result[prID] = append(result[prID], userID)
 	}
-	return result, rows.Err()
+	return result, translateError(rows.Err())
 }
 
-func (r *prRepo) RemoveReviewersBulk(ctx context.Context, tx *sql.Tx, assignments []domain.ReviewAssignment) error {
+func (r *prRepo) RemoveReviewersBulk(ctx context.Context, assignments []domain.ReviewAssignment) error {
 	if len(assignments) == 0 {
 		return nil
 	}
 
 	valueStrings := make([]string, 0, len(assignments))
 	valueArgs := make([]interface{}, 0, len(assignments)*2)
+	prIDs := make([]string, 0, len(assignments))
 
 	for i, a := range assignments {
 		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d)", i*2+1, i*2+2))
 		valueArgs = append(valueArgs, a.PullRequestID, a.ReviewerID)
+		prIDs = append(prIDs, a.PullRequestID)
 	}
 
 	query := fmt.Sprintf(`
@@ -361,10 +640,8 @@ func (r *prRepo) RemoveReviewersBulk(ctx context.Context, tx *sql.Tx, assignment
 		WHERE t.pull_request_id = v.pr_id AND t.user_id = v.user_id
 	`, strings.Join(valueStrings, ","))
 
-	if tx != nil {
-		_, err := tx.ExecContext(ctx, query, valueArgs...)
-		return err
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, valueArgs...); err != nil {
+		return translateError(err)
 	}
-	_, err := r.db.ExecContext(ctx, query, valueArgs...)
-	return err
+	return r.bumpVersions(ctx, prIDs)
 }
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type reviewQueueRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewReviewQueueRepository(db *sql.DB) ReviewQueueRepository {
+	return &reviewQueueRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *reviewQueueRepo) Enqueue(ctx context.Context, prID, teamName string, slots int) error {
+	if slots <= 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("review_queue").
+		Columns("pull_request_id", "team_name")
+	for i := 0; i < slots; i++ {
+		insert = insert.Values(prID, teamName)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *reviewQueueRepo) ListByTeam(ctx context.Context, teamName string) ([]domain.QueuedReview, error) {
+	query := `
+		SELECT q.id, q.pull_request_id, pr.author_id, q.team_name,
+		       ARRAY(SELECT prr.user_id FROM pr_reviewers prr WHERE prr.pull_request_id = q.pull_request_id)
+		FROM review_queue q
+		JOIN pull_requests pr ON pr.id = q.pull_request_id
+		WHERE q.team_name = $1 AND pr.status = 'OPEN'
+		ORDER BY q.id
+		FOR UPDATE OF q SKIP LOCKED
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	queued := []domain.QueuedReview{}
+	for rows.Next() {
+		var q domain.QueuedReview
+		if err := rows.Scan(&q.ID, &q.PullRequestID, &q.AuthorID, &q.TeamName, pq.Array(&q.AssignedReviewers)); err != nil {
+			return nil, err
+		}
+		queued = append(queued, q)
+	}
+	return queued, translateError(rows.Err())
+}
+
+func (r *reviewQueueRepo) Delete(ctx context.Context, id int64) error {
+	query, args, err := r.builder.
+		Delete("review_queue").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *reviewQueueRepo) DeleteByPR(ctx context.Context, prID string) error {
+	query, args, err := r.builder.
+		Delete("review_queue").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *reviewQueueRepo) CountByPR(ctx context.Context, prID string) (int, error) {
+	query, args, err := r.builder.
+		Select("COUNT(*)").
+		From("review_queue").
+		Where(sq.Eq{"pull_request_id": prID}).
+		ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+
+	return count, translateError(err)
+}
+
+func (r *reviewQueueRepo) GetQueuedTeams(ctx context.Context) ([]string, error) {
+	query, args, err := r.builder.
+		Select("DISTINCT team_name").
+		From("review_queue").
+		OrderBy("team_name").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	teams := []string{}
+	for rows.Next() {
+		var team string
+		if err := rows.Scan(&team); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, translateError(rows.Err())
+}
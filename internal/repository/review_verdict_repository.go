@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type reviewVerdictRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewReviewVerdictRepository(db *sql.DB) ReviewVerdictRepository {
+	return &reviewVerdictRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Create сохраняет вердикт и увеличивает версию PR тем же запросом
+func (r *reviewVerdictRepo) Create(ctx context.Context, verdict *domain.ReviewVerdict) error {
+	query, args, err := r.builder.
+		Insert("review_verdicts").
+		Prefix("WITH bumped AS (UPDATE pull_requests SET version = version + 1 WHERE id = ?)", verdict.PullRequestID).
+		Columns("pull_request_id", "reviewer_id", "state", "body").
+		Values(verdict.PullRequestID, verdict.ReviewerID, verdict.State, verdict.Body).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return translateError(conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&verdict.ID, &verdict.CreatedAt))
+}
+
+func (r *reviewVerdictRepo) GetLatestByPR(ctx context.Context, prID string) ([]domain.ReviewVerdict, error) {
+	query, args, err := r.builder.
+		Select("DISTINCT ON (reviewer_id) id", "pull_request_id", "reviewer_id", "state", "body", "created_at").
+		From("review_verdicts").
+		Where(sq.Eq{"pull_request_id": prID}).
+		OrderBy("reviewer_id", "id DESC").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	verdicts := []domain.ReviewVerdict{}
+	for rows.Next() {
+		var v domain.ReviewVerdict
+		if err := rows.Scan(&v.ID, &v.PullRequestID, &v.ReviewerID, &v.State, &v.Body, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		verdicts = append(verdicts, v)
+	}
+	return verdicts, translateError(rows.Err())
+}
@@ -7,12 +7,14 @@ import (
 	"avito/internal/domain"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 )
 
 type StatisticsRepository interface {
-	GetAssignmentsByUser(ctx context.Context) ([]domain.AssignmentStat, error)
-	GetAssignmentsByPR(ctx context.Context) ([]domain.AssignmentStat, error)
-	GetTotalPRs(ctx context.Context) (int, error)
+	GetAssignmentsByUser(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error)
+	GetAssignmentsByPR(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error)
+	GetOpenAssignmentsByUser(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error)
+	GetTotalPRs(ctx context.Context, filter domain.PRFilter) (int, error)
 	GetActiveUsersCount(ctx context.Context) (int, error)
 	GetTeamsCount(ctx context.Context) (int, error)
 }
@@ -29,20 +31,43 @@ func NewStatisticsRepository(db *sql.DB) StatisticsRepository {
 	}
 }
 
-func (r *statisticsRepository) GetAssignmentsByUser(ctx context.Context) ([]domain.AssignmentStat, error) {
+// prFilterCond - условие отбора PR по метаданным для таблицы pull_requests с псевдонимом pr
+func prFilterCond(filter domain.PRFilter) sq.And {
+	cond := sq.And{}
+	if filter.Repository != "" {
+		cond = append(cond, sq.Eq{"pr.repository": filter.Repository})
+	}
+	if filter.TargetBranch != "" {
+		cond = append(cond, sq.Eq{"pr.target_branch": filter.TargetBranch})
+	}
+	if len(filter.Labels) > 0 {
+		cond = append(cond, sq.Expr("pr.labels @> ?", pq.Array(filter.Labels)))
+	}
+	if filter.MinLines != nil {
+		cond = append(cond, sq.Expr("pr.additions + pr.deletions >= ?", *filter.MinLines))
+	}
+	if filter.MaxLines != nil {
+		cond = append(cond, sq.Expr("pr.additions + pr.deletions <= ?", *filter.MaxLines))
+	}
+	return cond
+}
+
+func (r *statisticsRepository) GetAssignmentsByUser(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error) {
 	query, args, err := r.builder.
-		Select("user_id", "COUNT(*) as count").
-		From("pr_reviewers").
-		GroupBy("user_id").
+		Select("prr.user_id", "COUNT(*) as count").
+		From("pr_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Where(prFilterCond(filter)).
+		GroupBy("prr.user_id").
 		OrderBy("count DESC").
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -55,24 +80,59 @@ func (r *statisticsRepository) GetAssignmentsByUser(ctx context.Context) ([]doma
 		result = append(result, stat)
 	}
 
-	return result, rows.Err()
+	return result, translateError(rows.Err())
 }
 
-func (r *statisticsRepository) GetAssignmentsByPR(ctx context.Context) ([]domain.AssignmentStat, error) {
+func (r *statisticsRepository) GetAssignmentsByPR(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error) {
 	query, args, err := r.builder.
-		Select("pull_request_id", "COUNT(*) as count").
-		From("pr_reviewers").
-		GroupBy("pull_request_id").
+		Select("prr.pull_request_id", "COUNT(*) as count").
+		From("pr_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Where(prFilterCond(filter)).
+		GroupBy("prr.pull_request_id").
 		OrderBy("count DESC").
 		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	var result []domain.AssignmentStat
+	for rows.Next() {
+		var stat domain.AssignmentStat
+		if err := rows.Scan(&stat.ID, &stat.Count); err != nil {
+			return nil, err
+		}
+		result = append(result, stat)
+	}
+
+	return result, translateError(rows.Err())
+}
+
+// GetOpenAssignmentsByUser - текущая загрузка ревьюверов: только OPEN PR
+func (r *statisticsRepository) GetOpenAssignmentsByUser(ctx context.Context, filter domain.PRFilter) ([]domain.AssignmentStat, error) {
+	query, args, err := r.builder.
+		Select("prr.user_id", "COUNT(*) as count").
+		From("pr_reviewers prr").
+		Join("pull_requests pr ON pr.id = prr.pull_request_id").
+		Where(sq.Eq{"pr.status": domain.PRStatusOpen}).
+		Where(prFilterCond(filter)).
+		GroupBy("prr.user_id").
+		OrderBy("count DESC").
+		ToSql()
 	if err != nil {
 		return nil, err
 	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
 	defer rows.Close()
 
 	var result []domain.AssignmentStat
@@ -84,20 +144,21 @@ func (r *statisticsRepository) GetAssignmentsByPR(ctx context.Context) ([]domain
 		result = append(result, stat)
 	}
 
-	return result, rows.Err()
+	return result, translateError(rows.Err())
 }
 
-func (r *statisticsRepository) GetTotalPRs(ctx context.Context) (int, error) {
+func (r *statisticsRepository) GetTotalPRs(ctx context.Context, filter domain.PRFilter) (int, error) {
 	var count int
 	query, args, err := r.builder.
 		Select("COUNT(*)").
-		From("pull_requests").
+		From("pull_requests pr").
+		Where(prFilterCond(filter)).
 		ToSql()
 	if err != nil {
 		return 0, err
 	}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&count)
-	return count, err
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, translateError(err)
 }
 
 func (r *statisticsRepository) GetActiveUsersCount(ctx context.Context) (int, error) {
@@ -110,8 +171,8 @@ func (r *statisticsRepository) GetActiveUsersCount(ctx context.Context) (int, er
 	if err != nil {
 		return 0, err
 	}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&count)
-	return count, err
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, translateError(err)
 }
 
 // GetTeamsCount retrieves total count of teams
@@ -124,6 +185,6 @@ func (r *statisticsRepository) GetTeamsCount(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&count)
-	return count, err
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, translateError(err)
 }
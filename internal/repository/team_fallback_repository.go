@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type teamFallbackRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewTeamFallbackRepository(db *sql.DB) TeamFallbackRepository {
+	return &teamFallbackRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+func (r *teamFallbackRepo) Get(ctx context.Context, teamName string) ([]string, error) {
+	query, args, err := r.builder.
+		Select("fallback_team").
+		From("team_fallbacks").
+		Where(sq.Eq{"team_name": teamName}).
+		OrderBy("priority").
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	teams := []string{}
+	for rows.Next() {
+		var team string
+		if err := rows.Scan(&team); err != nil {
+			return nil, err
+		}
+		teams = append(teams, team)
+	}
+	return teams, translateError(rows.Err())
+}
+
+// Replace заменяет список запасных команд целиком; приоритет - позиция в fallbackTeams.
+// Удаление и вставка должны быть атомарны, поэтому вызывается внутри WithinTx.
+func (r *teamFallbackRepo) Replace(ctx context.Context, teamName string, fallbackTeams []string) error {
+	deleteQuery, deleteArgs, err := r.builder.
+		Delete("team_fallbacks").
+		Where(sq.Eq{"team_name": teamName}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	if _, err := conn(ctx, r.db).ExecContext(ctx, deleteQuery, deleteArgs...); err != nil {
+		return translateError(err)
+	}
+
+	if len(fallbackTeams) == 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("team_fallbacks").
+		Columns("team_name", "fallback_team", "priority")
+	for i, fallback := range fallbackTeams {
+		insert = insert.Values(teamName, fallback, i+1)
+	}
+
+	query, args, err := insert.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
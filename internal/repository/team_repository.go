@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"avito/internal/domain"
 
@@ -21,22 +22,28 @@ func NewTeamRepository(db *sql.DB) TeamRepository {
 	}
 }
 
-func (r *teamRepo) Create(ctx context.Context, tx *sql.Tx, teamName string) error {
+func (r *teamRepo) Create(ctx context.Context, team *domain.Team) error {
 	query, args, err := r.builder.
 		Insert("teams").
 		Columns("name").
-		Values(teamName).
+		Values(team.Name).
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
-	}
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateTeamError(err)
+}
 
+// translateTeamError - команда с таким именем создана параллельно, после проверки в сервисе
+func translateTeamError(err error) error {
+	err = translateError(err)
+	var appErr *domain.AppError
+	if errors.As(err, &appErr) && appErr.Code == domain.ErrCodeAlreadyExists {
+		return domain.NewAppError(domain.ErrCodeTeamExists, "team_name already exists")
+	}
 	return err
 }
 
@@ -52,9 +59,9 @@ func (r *teamRepo) Get(ctx context.Context, teamName string) (*domain.Team, erro
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
@@ -68,7 +75,7 @@ func (r *teamRepo) Get(ctx context.Context, teamName string) (*domain.Team, erro
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 
 	if len(members) == 0 {
@@ -83,9 +90,9 @@ func (r *teamRepo) Get(ctx context.Context, teamName string) (*domain.Team, erro
 		if err != nil {
 			return nil, err
 		}
-		err = r.db.QueryRowContext(ctx, q, a...).Scan(&exists)
+		err = conn(ctx, r.db).QueryRowContext(ctx, q, a...).Scan(&exists)
 		if err != nil {
-			return nil, err
+			return nil, translateError(err)
 		}
 		if !exists {
 			return nil, domain.NewAppError(domain.ErrCodeNotFound, "team not found")
@@ -110,6 +117,74 @@ func (r *teamRepo) Exists(ctx context.Context, teamName string) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(&exists)
-	return exists, err
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&exists)
+	return exists, translateError(err)
+}
+
+func (r *teamRepo) Rename(ctx context.Context, oldName, newName string) error {
+	query, args, err := r.builder.
+		Update("teams").
+		Set("name", newName).
+		Where(sq.Eq{"name": oldName}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateTeamError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+	}
+
+	// История срабатываний правил ссылается на команды без внешнего ключа
+	for _, column := range []string{"team_name", "extra_team"} {
+		query, args, err := r.builder.
+			Update("assignment_rule_firings").
+			Set(column, newName).
+			Where(sq.Eq{column: oldName}).
+			ToSql()
+		if err != nil {
+			return err
+		}
+		if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+			return translateError(err)
+		}
+	}
+
+	return nil
+}
+
+func (r *teamRepo) Delete(ctx context.Context, teamName string) error {
+	query, args, err := r.builder.
+		Delete("teams").
+		Where(sq.Eq{"name": teamName}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateDeleteError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+	}
+
+	return nil
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+type teamSettingsRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewTeamSettingsRepository(db *sql.DB) TeamSettingsRepository {
+	return &teamSettingsRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+// Get возвращает настройки команды; если они не сохранялись - значения по умолчанию
+func (r *teamSettingsRepo) Get(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	defaults := domain.DefaultTeamSettings(teamName)
+
+	query, args, err := r.builder.
+		Select("t.name").
+		Column(sq.Expr("COALESCE(s.min_reviewers, ?)", defaults.MinReviewers)).
+		Column(sq.Expr("COALESCE(s.max_reviewers, ?)", defaults.MaxReviewers)).
+		Column(sq.Expr("COALESCE(s.reviewer_strategy, ?)", defaults.ReviewerStrategy)).
+		Column(sq.Expr("COALESCE(s.allow_no_reviewers, ?)", defaults.AllowNoReviewers)).
+		Column("s.default_max_open_reviews").
+		Column(sq.Expr("COALESCE(s.capacity_overflow, ?)", defaults.CapacityOverflow)).
+		Column(sq.Expr("COALESCE(s.required_approvals, ?)", defaults.RequiredApprovals)).
+		Column(sq.Expr("COALESCE(s.large_pr_lines, ?)", defaults.LargePRLines)).
+		Column(sq.Expr("COALESCE(s.large_pr_reviewers, ?)", defaults.LargePRReviewers)).
+		From("teams t").
+		LeftJoin("team_settings s ON s.team_name = t.name").
+		Where(sq.Eq{"t.name": teamName}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var settings domain.TeamSettings
+	dest := []interface{}{
+		&settings.TeamName, &settings.MinReviewers, &settings.MaxReviewers,
+		&settings.ReviewerStrategy, &settings.AllowNoReviewers,
+		&settings.DefaultMaxOpenReviews, &settings.CapacityOverflow, &settings.RequiredApprovals,
+		&settings.LargePRLines, &settings.LargePRReviewers,
+	}
+
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(dest...)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &settings, nil
+}
+
+func (r *teamSettingsRepo) Upsert(ctx context.Context, settings *domain.TeamSettings) error {
+	query, args, err := r.builder.
+		Insert("team_settings").
+		Columns(
+			"team_name", "min_reviewers", "max_reviewers", "reviewer_strategy", "allow_no_reviewers",
+			"default_max_open_reviews", "capacity_overflow", "required_approvals",
+			"large_pr_lines", "large_pr_reviewers",
+		).
+		Values(
+			settings.TeamName, settings.MinReviewers, settings.MaxReviewers, settings.ReviewerStrategy, settings.AllowNoReviewers,
+			settings.DefaultMaxOpenReviews, settings.CapacityOverflow, settings.RequiredApprovals,
+			settings.LargePRLines, settings.LargePRReviewers,
+		).
+		Suffix(`ON CONFLICT (team_name) DO UPDATE SET
+			min_reviewers = EXCLUDED.min_reviewers,
+			max_reviewers = EXCLUDED.max_reviewers,
+			reviewer_strategy = EXCLUDED.reviewer_strategy,
+			allow_no_reviewers = EXCLUDED.allow_no_reviewers,
+			default_max_open_reviews = EXCLUDED.default_max_open_reviews,
+			capacity_overflow = EXCLUDED.capacity_overflow,
+			required_approvals = EXCLUDED.required_approvals,
+			large_pr_lines = EXCLUDED.large_pr_lines,
+			large_pr_reviewers = EXCLUDED.large_pr_reviewers,
+			updated_at = CURRENT_TIMESTAMP`).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
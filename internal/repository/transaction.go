@@ -3,8 +3,79 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// TxOptions - режим транзакции WithinTx; нулевое значение - READ COMMITTED на запись.
+// ReadOnly без Serializable выполняется в REPEATABLE READ: все запросы чтения видят один снимок
+type TxOptions struct {
+	Serializable bool
+	ReadOnly     bool
+}
+
+// isolation - строгость уровня изоляции для сравнения вложенных транзакций с внешней
+func (o TxOptions) isolation() int {
+	switch {
+	case o.Serializable:
+		return 2
+	case o.ReadOnly:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// covers сообщает, выполняются ли гарантии nested внутри транзакции с режимом o
+func (o TxOptions) covers(nested TxOptions) bool {
+	if o.ReadOnly && !nested.ReadOnly {
+		return false
+	}
+	return o.isolation() >= nested.isolation()
+}
+
+var (
+	TxDefault      = TxOptions{}
+	TxReadOnly     = TxOptions{ReadOnly: true}
+	TxSerializable = TxOptions{Serializable: true}
+)
+
+const (
+	txMaxAttempts = 5
+	txBaseBackoff = 10 * time.Millisecond
+	txMaxBackoff  = 200 * time.Millisecond
 )
 
+// txMetrics - счётчики повторов транзакций, публикуются в /debug/vars:
+// retries - повторы после конфликта сериализации или взаимоблокировки,
+// retries_exhausted - транзакции, не прошедшие за txMaxAttempts попыток
+var txMetrics = expvar.NewMap("db_tx")
+
+// querier - общее подмножество *sql.DB и *sql.Tx, через которое репозитории выполняют запросы
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// txOptionsKey - режим транзакции из txKey, по нему проверяются вложенные WithinTx
+type txOptionsKey struct{}
+
+// conn возвращает транзакцию WithinTx из ctx, а вне транзакции - пул db
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
 type txManager struct {
 	db *sql.DB
 }
@@ -13,6 +84,71 @@ func NewTransactionManager(db *sql.DB) TransactionManager {
 	return &txManager{db: db}
 }
 
-func (tm *txManager) BeginTx(ctx context.Context) (*sql.Tx, error) {
-	return tm.db.BeginTx(ctx, nil)
+// WithinTx выполняет fn в транзакции и фиксирует её, если fn вернула nil. Транзакция передаётся
+// в fn через ctx: все вызовы репозиториев с этим ctx выполняются в ней. Вложенный WithinTx
+// присоединяется к внешней транзакции и возвращает ошибку, если его opts строже внешних:
+// уровень изоляции уже не поднять, а запись в транзакции только для чтения невозможна.
+// При конфликте сериализации или взаимоблокировке транзакция повторяется целиком
+// с новым вызовом fn, поэтому fn должна менять только базу.
+func (tm *txManager) WithinTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		outer, _ := ctx.Value(txOptionsKey{}).(TxOptions)
+		if !outer.covers(opts) {
+			return fmt.Errorf("nested transaction %+v is stricter than the outer %+v", opts, outer)
+		}
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := tm.runTx(ctx, opts, fn)
+		if !isRetryable(err) {
+			return err
+		}
+		if attempt == txMaxAttempts {
+			txMetrics.Add("retries_exhausted", 1)
+			return err
+		}
+		txMetrics.Add("retries", 1)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(txBackoff(attempt)):
+		}
+	}
+}
+
+func (tm *txManager) runTx(ctx context.Context, opts TxOptions, fn func(ctx context.Context) error) error {
+	sqlOpts := &sql.TxOptions{ReadOnly: opts.ReadOnly}
+	switch {
+	case opts.Serializable:
+		sqlOpts.Isolation = sql.LevelSerializable
+	case opts.ReadOnly:
+		sqlOpts.Isolation = sql.LevelRepeatableRead
+	}
+
+	tx, err := tm.db.BeginTx(ctx, sqlOpts)
+	if err != nil {
+		return translateError(err)
+	}
+	defer tx.Rollback()
+
+	txCtx := context.WithValue(context.WithValue(ctx, txKey{}, tx), txOptionsKey{}, opts)
+	if err := fn(txCtx); err != nil {
+		return translateError(err)
+	}
+	// Конфликт сериализации может обнаружиться и при фиксации
+	return translateError(tx.Commit())
+}
+
+func isRetryable(err error) bool {
+	var appErr *domain.AppError
+	return errors.As(err, &appErr) && appErr.Code == domain.ErrCodeConcurrentUpdate
+}
+
+// txBackoff - экспоненциальная задержка перед попыткой attempt+1 с полным джиттером,
+// чтобы конфликтующие транзакции не повторялись одновременно
+func txBackoff(attempt int) time.Duration {
+	backoff := min(txBaseBackoff<<(attempt-1), txMaxBackoff)
+	return time.Duration(rand.Int63n(int64(backoff)) + 1)
 }
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"avito/internal/domain"
+)
+
+func TestTxBackoff(t *testing.T) {
+	for attempt := 1; attempt < txMaxAttempts; attempt++ {
+		limit := min(txBaseBackoff<<(attempt-1), txMaxBackoff)
+		for i := 0; i < 100; i++ {
+			if d := txBackoff(attempt); d <= 0 || d > limit {
+				t.Fatalf("txBackoff(%d) = %v, want (0, %v]", attempt, d, limit)
+			}
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	if !isRetryable(translateError(&pq.Error{Code: "40001"})) {
+		t.Error("serialization failure must be retried")
+	}
+	if !isRetryable(translateError(&pq.Error{Code: "40P01"})) {
+		t.Error("deadlock must be retried")
+	}
+	if isRetryable(domain.NewAppError(domain.ErrCodeNotFound, "PR not found")) {
+		t.Error("NOT_FOUND must not be retried")
+	}
+	if isRetryable(nil) {
+		t.Error("nil must not be retried")
+	}
+}
+
+func TestConn(t *testing.T) {
+	db := &sql.DB{}
+	tx := &sql.Tx{}
+
+	if conn(context.Background(), db) != db {
+		t.Error("outside a transaction queries must go to the pool")
+	}
+	if conn(context.WithValue(context.Background(), txKey{}, tx), db) != tx {
+		t.Error("inside WithinTx queries must join the ambient transaction")
+	}
+}
+
+func TestWithinTx_NestedOptions(t *testing.T) {
+	tm := &txManager{}
+	tests := []struct {
+		outer, nested TxOptions
+		ok            bool
+	}{
+		{TxDefault, TxDefault, true},
+		{TxSerializable, TxDefault, true},
+		{TxSerializable, TxReadOnly, true},
+		{TxDefault, TxReadOnly, false},
+		{TxDefault, TxSerializable, false},
+		{TxReadOnly, TxReadOnly, true},
+		{TxReadOnly, TxDefault, false},
+		{TxReadOnly, TxSerializable, false},
+	}
+	for _, tt := range tests {
+		ctx := context.WithValue(context.WithValue(context.Background(), txKey{}, &sql.Tx{}), txOptionsKey{}, tt.outer)
+		called := false
+		err := tm.WithinTx(ctx, tt.nested, func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+		if tt.ok && (err != nil || !called) {
+			t.Errorf("nested %+v in %+v: want fn to join the outer transaction, got %v", tt.nested, tt.outer, err)
+		}
+		if !tt.ok && (err == nil || called) {
+			t.Errorf("nested %+v in %+v: want an error without calling fn", tt.nested, tt.outer)
+		}
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/lib/pq"
 )
 
+// userColumns - поля пользователя; у исключённого из команды team_name пустой
+var userColumns = []string{"id", "username", "COALESCE(team_name, '')", "is_active", "max_open_reviews"}
+
 type userRepo struct {
 	db      *sql.DB
 	builder sq.StatementBuilderType
@@ -22,24 +25,29 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	}
 }
 
-func (r *userRepo) Create(ctx context.Context, tx *sql.Tx, user *domain.User) error {
+// AddToTeam создаёт пользователя в команде или принимает в неё пользователя без команды.
+// Проверка и запись - один INSERT, поэтому параллельные добавления не перетрут команду друг друга
+func (r *userRepo) AddToTeam(ctx context.Context, user *domain.User) (bool, error) {
 	query, args, err := r.builder.
 		Insert("users").
 		Columns("id", "username", "team_name", "is_active").
 		Values(user.ID, user.Username, user.TeamName, user.IsActive).
-		Suffix("ON CONFLICT (id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active").
+		Suffix("ON CONFLICT (id) DO UPDATE SET username = EXCLUDED.username, team_name = EXCLUDED.team_name, is_active = EXCLUDED.is_active" +
+			" WHERE users.team_name IS NULL").
 		ToSql()
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, translateError(err)
 	}
-
-	return err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
 }
 
 func (r *userRepo) Update(ctx context.Context, user *domain.User) error {
@@ -54,13 +62,13 @@ func (r *userRepo) Update(ctx context.Context, user *domain.User) error {
 		return err
 	}
 
-	_, err = r.db.ExecContext(ctx, query, args...)
-	return err
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
 }
 
 func (r *userRepo) Get(ctx context.Context, userID string) (*domain.User, error) {
 	query, args, err := r.builder.
-		Select("id", "username", "team_name", "is_active").
+		Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"id": userID}).
 		ToSql()
@@ -69,23 +77,49 @@ func (r *userRepo) Get(ctx context.Context, userID string) (*domain.User, error)
 	}
 
 	var user domain.User
-	err = r.db.QueryRowContext(ctx, query, args...).Scan(
-		&user.ID, &user.Username, &user.TeamName, &user.IsActive,
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&user.ID, &user.Username, &user.TeamName, &user.IsActive, &user.MaxOpenReviews,
 	)
 
 	if err == sql.ErrNoRows {
 		return nil, domain.NewAppError(domain.ErrCodeNotFound, "user not found")
 	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &user, nil
+}
+
+func (r *userRepo) GetForUpdate(ctx context.Context, userID string) (*domain.User, error) {
+	query, args, err := r.builder.
+		Select(userColumns...).
+		From("users").
+		Where(sq.Eq{"id": userID}).
+		Suffix("FOR UPDATE").
+		ToSql()
 	if err != nil {
 		return nil, err
 	}
 
+	var user domain.User
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&user.ID, &user.Username, &user.TeamName, &user.IsActive, &user.MaxOpenReviews,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "user not found")
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
 	return &user, nil
 }
 
 func (r *userRepo) GetByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
 	query, args, err := r.builder.
-		Select("id", "username", "team_name", "is_active").
+		Select(userColumns...).
 		From("users").
 		Where(sq.Eq{"team_name": teamName}).
 		ToSql()
@@ -93,101 +127,117 @@ func (r *userRepo) GetByTeam(ctx context.Context, teamName string) ([]domain.Use
 		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
 	users := make([]domain.User, 0)
 	for rows.Next() {
 		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.MaxOpenReviews); err != nil {
 			return nil, err
 		}
 		users = append(users, u)
 	}
 
-	return users, rows.Err()
+	return users, translateError(rows.Err())
 }
 
-func (r *userRepo) GetActiveTeammates(ctx context.Context, authorID string, limit int) ([]domain.User, error) {
-	// Squirrel рекомендуется к использования, хоть и не поддерживает подзапрос, поэтому пишем сырой SQL
+func (r *userRepo) GetReviewCandidates(ctx context.Context, teamName string, excludeIDs []string) ([]domain.ReviewCandidate, error) {
+	// Загрузка считается только по OPEN PR, время последнего назначения - по всем.
+	// Отсутствующие сейчас пользователи не являются кандидатами, хотя is_active у них не меняется.
+	// FOR SHARE в candidates не даёт деактивировать или перевести кандидата, пока транзакция
+	// назначения не завершится; деактивированный до блокировки кандидат отсеивается повторной проверкой.
 	query := `
-		SELECT u.id, u.username, u.team_name, u.is_active
+		WITH candidates AS (
+		    SELECT id FROM users
+		    WHERE team_name = $1 AND is_active = true AND id != ALL($2)
+		    ORDER BY id
+		    FOR SHARE
+		)
+		SELECT u.id, u.username, u.team_name, u.is_active, u.max_open_reviews,
+		       COUNT(pr.id) AS open_reviews,
+		       MAX(prr.assigned_at) AS last_assigned_at
 		FROM users u
-		WHERE u.team_name = (SELECT team_name FROM users WHERE id = $1)
-		  AND u.is_active = true
-		  AND u.id != $1
-		ORDER BY RANDOM()
-		LIMIT $2
+		JOIN candidates c ON c.id = u.id
+		LEFT JOIN pr_reviewers prr ON prr.user_id = u.id
+		LEFT JOIN pull_requests pr ON pr.id = prr.pull_request_id AND pr.status = 'OPEN'
+		WHERE NOT EXISTS (
+		      SELECT 1 FROM absences a
+		      WHERE a.user_id = u.id AND a.starts_at <= NOW() AND a.ends_at > NOW()
+		  )
+		GROUP BY u.id, u.username, u.team_name, u.is_active, u.max_open_reviews
+		ORDER BY u.id
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, authorID, limit)
+	if excludeIDs == nil {
+		excludeIDs = []string{}
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName, pq.Array(excludeIDs))
+
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	defer rows.Close()
 
-	users := make([]domain.User, 0)
+	candidates := make([]domain.ReviewCandidate, 0)
 	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		var c domain.ReviewCandidate
+		if err := rows.Scan(
+			&c.User.ID, &c.User.Username, &c.User.TeamName, &c.User.IsActive, &c.User.MaxOpenReviews,
+			&c.OpenReviews, &c.LastAssignedAt,
+		); err != nil {
 			return nil, err
 		}
-		users = append(users, u)
+		candidates = append(candidates, c)
 	}
 
-	return users, rows.Err()
+	return candidates, translateError(rows.Err())
 }
 
-func (r *userRepo) FindReplacementReviewer(ctx context.Context, tx *sql.Tx, teamName string, excludeIDs []string) (*domain.User, error) {
-	query := `
-		SELECT id, username, team_name, is_active
-		FROM users
-		WHERE team_name = $1
-		  AND is_active = true
-		  AND id != ALL($2)
-		ORDER BY RANDOM()
-		LIMIT 1
-	`
-
-	var user domain.User
-	var err error
-
-	if tx != nil {
-		err = tx.QueryRowContext(ctx, query, teamName, pq.Array(excludeIDs)).Scan(
-			&user.ID, &user.Username, &user.TeamName, &user.IsActive,
-		)
-	} else {
-		err = r.db.QueryRowContext(ctx, query, teamName, pq.Array(excludeIDs)).Scan(
-			&user.ID, &user.Username, &user.TeamName, &user.IsActive,
-		)
+func (r *userRepo) SetActive(ctx context.Context, userID string, isActive bool) error {
+	query, args, err := r.builder.
+		Update("users").
+		Set("is_active", isActive).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
+	if err != nil {
+		return err
 	}
 
-	if err == sql.ErrNoRows {
-		return nil, domain.NewAppError(domain.ErrCodeNoCandidate, "no active replacement candidate in team")
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
 	}
+
+	rows, err := result.RowsAffected()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &user, nil
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
 }
 
-func (r *userRepo) SetActive(ctx context.Context, userID string, isActive bool) error {
+func (r *userRepo) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) error {
 	query, args, err := r.builder.
 		Update("users").
-		Set("is_active", isActive).
+		Set("max_open_reviews", limit).
 		Where(sq.Eq{"id": userID}).
 		ToSql()
 	if err != nil {
 		return err
 	}
 
-	result, err := r.db.ExecContext(ctx, query, args...)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
 	if err != nil {
-		return err
+		return translateError(err)
 	}
 
 	rows, err := result.RowsAffected()
@@ -196,13 +246,13 @@ func (r *userRepo) SetActive(ctx context.Context, userID string, isActive bool)
 	}
 
 	if rows == 0 {
-		return sql.ErrNoRows
+		return domain.NewAppError(domain.ErrCodeNotFound, "user not found")
 	}
 
 	return nil
 }
 
-func (r *userRepo) DeactivateMany(ctx context.Context, tx *sql.Tx, userIDs []string) error {
+func (r *userRepo) DeactivateMany(ctx context.Context, userIDs []string) error {
 	query, args, err := r.builder.
 		Update("users").
 		Set("is_active", false).
@@ -212,46 +262,64 @@ func (r *userRepo) DeactivateMany(ctx context.Context, tx *sql.Tx, userIDs []str
 		return err
 	}
 
-	if tx != nil {
-		_, err = tx.ExecContext(ctx, query, args...)
-	} else {
-		_, err = r.db.ExecContext(ctx, query, args...)
-	}
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
 
-	return err
+	return translateError(err)
 }
 
-func (r *userRepo) GetActiveUsersByTeam(ctx context.Context, tx *sql.Tx, teamName string) ([]domain.User, error) {
+func (r *userRepo) SetUsername(ctx context.Context, userID, username string) error {
 	query, args, err := r.builder.
-		Select("id", "username", "team_name", "is_active").
-		From("users").
-		Where(sq.Eq{"team_name": teamName, "is_active": true}).
+		Update("users").
+		Set("username", username).
+		Where(sq.Eq{"id": userID}).
 		ToSql()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var rows *sql.Rows
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
 
-	if tx != nil {
-		rows, err = tx.QueryContext(ctx, query, args...)
-	} else {
-		rows, err = r.db.QueryContext(ctx, query, args...)
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "user not found")
 	}
 
+	return nil
+}
+
+func (r *userRepo) SetTeam(ctx context.Context, userID, teamName string) error {
+	query, args, err := r.builder.
+		Update("users").
+		Set("team_name", teamName).
+		Where(sq.Eq{"id": userID}).
+		ToSql()
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
 
-	users := make([]domain.User, 0)
-	for rows.Next() {
-		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
-			return nil, err
-		}
-		users = append(users, u)
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+// RemoveFromTeam исключает пользователей из команды: они остаются в базе деактивированными и без команды
+func (r *userRepo) RemoveFromTeam(ctx context.Context, userIDs []string) error {
+	query, args, err := r.builder.
+		Update("users").
+		Set("team_name", nil).
+		Set("is_active", false).
+		Where(sq.Eq{"id": userIDs}).
+		ToSql()
+	if err != nil {
+		return err
 	}
 
-	return users, rows.Err()
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
 }
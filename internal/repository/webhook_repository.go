@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"avito/internal/domain"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+type webhookRepo struct {
+	db      *sql.DB
+	builder sq.StatementBuilderType
+}
+
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepo{
+		db:      db,
+		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+	}
+}
+
+var subscriptionColumns = []string{"id", "url", "secret", "event_types", "is_active", "created_at"}
+
+func (r *webhookRepo) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	query, args, err := r.builder.
+		Insert("webhook_subscriptions").
+		Columns("url", "secret", "event_types", "is_active").
+		Values(sub.URL, sub.Secret, pq.Array(sub.EventTypes), sub.IsActive).
+		Suffix("RETURNING id, created_at").
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	return translateError(conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&sub.ID, &sub.CreatedAt))
+}
+
+func (r *webhookRepo) Get(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	query, args, err := r.builder.
+		Select(subscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	var sub domain.WebhookSubscription
+	err = conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.EventTypes), &sub.IsActive, &sub.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "webhook subscription not found")
+	}
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	return &sub, nil
+}
+
+func (r *webhookRepo) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, r.builder.Select(subscriptionColumns...).From("webhook_subscriptions").OrderBy("id"))
+}
+
+func (r *webhookRepo) ListActive(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, r.builder.
+		Select(subscriptionColumns...).
+		From("webhook_subscriptions").
+		Where(sq.Eq{"is_active": true}).
+		OrderBy("id"))
+}
+
+func (r *webhookRepo) list(ctx context.Context, builder sq.SelectBuilder) ([]domain.WebhookSubscription, error) {
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	subs := []domain.WebhookSubscription{}
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&sub.EventTypes), &sub.IsActive, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, translateError(rows.Err())
+}
+
+func (r *webhookRepo) Update(ctx context.Context, sub *domain.WebhookSubscription) error {
+	query, args, err := r.builder.
+		Update("webhook_subscriptions").
+		Set("url", sub.URL).
+		Set("secret", sub.Secret).
+		Set("event_types", pq.Array(sub.EventTypes)).
+		Set("is_active", sub.IsActive).
+		Where(sq.Eq{"id": sub.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+func (r *webhookRepo) Delete(ctx context.Context, id int64) error {
+	query, args, err := r.builder.
+		Delete("webhook_subscriptions").
+		Where(sq.Eq{"id": id}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return domain.NewAppError(domain.ErrCodeNotFound, "webhook subscription not found")
+	}
+
+	return nil
+}
+
+func (r *webhookRepo) CreateDeliveries(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	insert := r.builder.
+		Insert("webhook_deliveries").
+		Columns("subscription_id", "event_id", "event_type")
+	for _, d := range deliveries {
+		insert = insert.Values(d.SubscriptionID, d.EventID, d.EventType)
+	}
+
+	query, args, err := insert.Suffix("ON CONFLICT (subscription_id, event_id) DO NOTHING").ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+
+	return translateError(err)
+}
+
+func (r *webhookRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.WebhookDispatch, error) {
+	// Попытка засчитывается при взятии в работу: если процесс упадёт посреди отправки,
+	// доставка вернётся в работу после lease и не будет повторяться бесконечно.
+	// Доставки отключённой подписки не отправляются и ждут её повторного включения.
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts = d.attempts + 1, next_attempt_at = $2
+		FROM (
+			SELECT pd.id FROM webhook_deliveries pd
+			JOIN webhook_subscriptions ps ON ps.id = pd.subscription_id
+			WHERE pd.status = 'pending' AND pd.next_attempt_at <= $1 AND ps.is_active
+			ORDER BY pd.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF pd SKIP LOCKED
+		) due, webhook_subscriptions s, outbox_events e
+		WHERE d.id = due.id AND s.id = d.subscription_id AND e.id = d.event_id
+		RETURNING d.id, d.subscription_id, d.event_id, d.event_type, d.status, d.attempts, d.created_at,
+		          s.url, s.secret, e.payload, e.created_at
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	dispatches := []domain.WebhookDispatch{}
+	for rows.Next() {
+		var d domain.WebhookDispatch
+		if err := rows.Scan(
+			&d.Delivery.ID, &d.Delivery.SubscriptionID, &d.Delivery.EventID, &d.Delivery.EventType,
+			&d.Delivery.Status, &d.Delivery.Attempts, &d.Delivery.CreatedAt,
+			&d.URL, &d.Secret, &d.Event.Payload, &d.Event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		d.Event.ID = d.Delivery.EventID
+		d.Event.EventType = d.Delivery.EventType
+		dispatches = append(dispatches, d)
+	}
+	return dispatches, translateError(rows.Err())
+}
+
+func (r *webhookRepo) SaveAttempt(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	query, args, err := r.builder.
+		Update("webhook_deliveries").
+		Set("status", delivery.Status).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("last_error", delivery.LastError).
+		Set("response_status", delivery.ResponseStatus).
+		Set("delivered_at", delivery.DeliveredAt).
+		Where(sq.Eq{"id": delivery.ID}).
+		ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, args...)
+	return translateError(err)
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	query, args, err := r.builder.
+		Select(
+			"id", "subscription_id", "event_id", "event_type", "status", "attempts", "next_attempt_at",
+			"last_error", "response_status", "created_at", "delivered_at",
+		).
+		From("webhook_deliveries").
+		Where(sq.Eq{"subscription_id": subscriptionID}).
+		OrderBy("id DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := rows.Scan(
+			&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt,
+			&d.LastError, &d.ResponseStatus, &d.CreatedAt, &d.DeliveredAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, translateError(rows.Err())
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+type AbsenceService struct {
+	absenceRepo repository.AbsenceRepository
+	userRepo    repository.UserRepository
+	prRepo      repository.PullRequestRepository
+	txMgr       repository.TransactionManager
+	reassigner  *reviewReassigner
+}
+
+func NewAbsenceService(
+	absenceRepo repository.AbsenceRepository,
+	userRepo repository.UserRepository,
+	prRepo repository.PullRequestRepository,
+	settingsRepo repository.TeamSettingsRepository,
+	queueRepo repository.ReviewQueueRepository,
+	outboxRepo repository.OutboxRepository,
+	historyRepo repository.AssignmentEventRepository,
+	txMgr repository.TransactionManager,
+) *AbsenceService {
+	return &AbsenceService{
+		absenceRepo: absenceRepo,
+		userRepo:    userRepo,
+		prRepo:      prRepo,
+		txMgr:       txMgr,
+		reassigner: &reviewReassigner{
+			prRepo:       prRepo,
+			userRepo:     userRepo,
+			settingsRepo: settingsRepo,
+			queueRepo:    queueRepo,
+			events:       &eventPublisher{outboxRepo: outboxRepo, historyRepo: historyRepo},
+		},
+	}
+}
+
+func (s *AbsenceService) Create(ctx context.Context, absence *domain.Absence) error {
+	if _, err := s.userRepo.Get(ctx, absence.UserID); err != nil {
+		return err
+	}
+
+	return s.absenceRepo.Create(ctx, absence)
+}
+
+func (s *AbsenceService) List(ctx context.Context, userID string) ([]domain.Absence, error) {
+	if _, err := s.userRepo.Get(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.absenceRepo.ListByUser(ctx, userID)
+}
+
+func (s *AbsenceService) Delete(ctx context.Context, absenceID int64) error {
+	return s.absenceRepo.Delete(ctx, absenceID)
+}
+
+// ReassignStartedAbsences передаёт открытые ревью пользователей, у которых началось отсутствие.
+// Отсутствие, для которого не хватило свободных ревьюверов при политике reject, остаётся
+// необработанным и будет повторено при следующем запуске.
+func (s *AbsenceService) ReassignStartedAbsences(ctx context.Context) error {
+	return s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		now := time.Now()
+		absences, err := s.absenceRepo.GetStartedUnprocessed(ctx, now)
+		if err != nil {
+			return err
+		}
+
+		for _, absence := range absences {
+			user, err := s.userRepo.Get(ctx, absence.UserID)
+			if err != nil {
+				return err
+			}
+
+			assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, []string{absence.UserID})
+			if err != nil {
+				return err
+			}
+
+			// Ошибка лимита возвращается до любых изменений, поэтому транзакция остаётся рабочей
+			if err := s.reassigner.reassignAway(ctx, user.TeamName, changeReasonAbsence, assignments); err != nil {
+				var appErr *domain.AppError
+				if errors.As(err, &appErr) && appErr.Code == domain.ErrCodeCapacityExhausted {
+					continue
+				}
+				return err
+			}
+
+			if err := s.absenceRepo.MarkReassigned(ctx, absence.ID, now); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+// Причины смены ревьювера в событиях reviewer.reassigned и reviewer.removed
+const (
+	changeReasonManual      = "manual"
+	changeReasonDeactivated = "deactivation"
+	changeReasonAbsence     = "absence"
+	changeReasonRemoved     = "removal"
+	changeReasonTransfer    = "transfer"
+	changeReasonTeamDeleted = "team_deletion"
+	// changeReasonClose - ревьюверы сняты закрытием PR; попадает только в историю назначений
+	changeReasonClose = "close"
+)
+
+// Источники назначения в событии reviewers.assigned
+const (
+	assignSourceCreate = "create"
+	assignSourceReady  = "ready"
+	assignSourceReopen = "reopen"
+	assignSourceQueue  = "queue"
+)
+
+type prEventPayload struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	Name              string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	Status            string   `json:"status"`
+	MergeForced       bool     `json:"merge_forced,omitempty"`
+	MergeForcedBy     string   `json:"merge_forced_by,omitempty"`
+	ReleasedReviewers []string `json:"released_reviewers,omitempty"`
+	Repository        string   `json:"repository,omitempty"`
+	SourceBranch      string   `json:"source_branch,omitempty"`
+	TargetBranch      string   `json:"target_branch,omitempty"`
+	URL               string   `json:"url,omitempty"`
+	Labels            []string `json:"labels,omitempty"`
+}
+
+type reviewersAssignedPayload struct {
+	PullRequestID    string   `json:"pull_request_id"`
+	Reviewers        []string `json:"reviewers"`
+	PendingReviewers int      `json:"pending_reviewers,omitempty"`
+	Source           string   `json:"source"`
+}
+
+type reviewerChangedPayload struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	// Queued - замена отложена до освобождения лимита в команде
+	Queued bool   `json:"queued,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// eventPublisher пишет события в outbox и историю назначений в транзакции изменения:
+// при откате события пропадают вместе с ним
+type eventPublisher struct {
+	outboxRepo  repository.OutboxRepository
+	historyRepo repository.AssignmentEventRepository
+}
+
+func (p *eventPublisher) publish(ctx context.Context, eventType string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return p.outboxRepo.Add(ctx, eventType, data)
+}
+
+func (p *eventPublisher) prEvent(ctx context.Context, eventType string, pr *domain.PullRequest, released []string) error {
+	return p.publish(ctx, eventType, prEventPayload{
+		PullRequestID:     pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            pr.Status,
+		MergeForced:       pr.MergeForced,
+		MergeForcedBy:     pr.MergeForcedBy,
+		ReleasedReviewers: released,
+		Repository:        pr.Repository,
+		SourceBranch:      pr.SourceBranch,
+		TargetBranch:      pr.TargetBranch,
+		URL:               pr.URL,
+		Labels:            pr.Labels,
+	})
+}
+
+// reviewersAssigned публикует назначение, если оно было
+func (p *eventPublisher) reviewersAssigned(ctx context.Context, prID string, reviewers []string, pending int, source string) error {
+	if len(reviewers) == 0 && pending == 0 {
+		return nil
+	}
+
+	history := make([]domain.AssignmentEvent, len(reviewers))
+	for i, reviewerID := range reviewers {
+		history[i] = domain.AssignmentEvent{
+			PullRequestID: prID,
+			EventType:     domain.AssignmentEventAssigned,
+			ReviewerID:    reviewerID,
+			Reason:        source,
+			Actor:         domain.ActorFrom(ctx),
+		}
+	}
+	if err := p.historyRepo.Add(ctx, history); err != nil {
+		return err
+	}
+
+	return p.publish(ctx, domain.EventReviewersAssigned, reviewersAssignedPayload{
+		PullRequestID:    prID,
+		Reviewers:        reviewers,
+		PendingReviewers: pending,
+		Source:           source,
+	})
+}
+
+func (p *eventPublisher) reviewerChanged(ctx context.Context, eventType string, payload reviewerChangedPayload) error {
+	historyType := domain.AssignmentEventReassigned
+	if payload.NewReviewerID == "" {
+		historyType = domain.AssignmentEventRemoved
+	}
+	if err := p.historyRepo.Add(ctx, []domain.AssignmentEvent{{
+		PullRequestID:      payload.PullRequestID,
+		EventType:          historyType,
+		ReviewerID:         payload.NewReviewerID,
+		PreviousReviewerID: payload.OldReviewerID,
+		Reason:             payload.Reason,
+		Actor:              domain.ActorFrom(ctx),
+	}}); err != nil {
+		return err
+	}
+
+	return p.publish(ctx, eventType, payload)
+}
+
+// reviewersReleased записывает в историю снятие ревьюверов; событие outbox об этом публикует prEvent
+func (p *eventPublisher) reviewersReleased(ctx context.Context, prID string, reviewers []string, reason string) error {
+	history := make([]domain.AssignmentEvent, len(reviewers))
+	for i, reviewerID := range reviewers {
+		history[i] = domain.AssignmentEvent{
+			PullRequestID:      prID,
+			EventType:          domain.AssignmentEventRemoved,
+			PreviousReviewerID: reviewerID,
+			Reason:             reason,
+			Actor:              domain.ActorFrom(ctx),
+		}
+	}
+	return p.historyRepo.Add(ctx, history)
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+// IntegrationService применяет события внешних систем (GitHub, GitLab) к PR через PullRequestService
+type IntegrationService struct {
+	prService   *PullRequestService
+	userRepo    repository.UserRepository
+	teamRepo    repository.TeamRepository
+	mappingRepo repository.IntegrationUserRepository
+	projectRepo repository.IntegrationProjectRepository
+}
+
+func NewIntegrationService(
+	prService *PullRequestService,
+	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
+	mappingRepo repository.IntegrationUserRepository,
+	projectRepo repository.IntegrationProjectRepository,
+) *IntegrationService {
+	return &IntegrationService{
+		prService:   prService,
+		userRepo:    userRepo,
+		teamRepo:    teamRepo,
+		mappingRepo: mappingRepo,
+		projectRepo: projectRepo,
+	}
+}
+
+func (s *IntegrationService) MapUser(ctx context.Context, mapping *domain.IntegrationUserMapping) error {
+	if _, err := s.userRepo.Get(ctx, mapping.UserID); err != nil {
+		return err
+	}
+	return s.mappingRepo.Upsert(ctx, mapping)
+}
+
+func (s *IntegrationService) ListUsers(ctx context.Context, provider string) ([]domain.IntegrationUserMapping, error) {
+	return s.mappingRepo.List(ctx, provider)
+}
+
+func (s *IntegrationService) UnmapUser(ctx context.Context, provider, login string) error {
+	return s.mappingRepo.Delete(ctx, provider, login)
+}
+
+func (s *IntegrationService) MapProject(ctx context.Context, mapping *domain.IntegrationProjectMapping) error {
+	exists, err := s.teamRepo.Exists(ctx, mapping.TeamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+	}
+	return s.projectRepo.Upsert(ctx, mapping)
+}
+
+func (s *IntegrationService) ListProjects(ctx context.Context, provider string) ([]domain.IntegrationProjectMapping, error) {
+	return s.projectRepo.List(ctx, provider)
+}
+
+func (s *IntegrationService) UnmapProject(ctx context.Context, provider, project string) error {
+	return s.projectRepo.Delete(ctx, provider, project)
+}
+
+// ignorableIntegrationErrors - ошибки повторной или запоздалой доставки события: внешняя система
+// уже в нужном состоянии, поэтому событие пропускается, а не возвращается как ошибка
+var ignorableIntegrationErrors = map[string]struct{}{
+	domain.ErrCodePRExists:     {},
+	domain.ErrCodePRMerged:     {},
+	domain.ErrCodeInvalidState: {},
+}
+
+// Apply выполняет действие команды над PR
+func (s *IntegrationService) Apply(ctx context.Context, cmd *domain.IntegrationCommand) (*domain.IntegrationResult, error) {
+	result := &domain.IntegrationResult{Action: cmd.Action, Reason: cmd.Reason}
+	if domain.ActorFrom(ctx) == "" {
+		ctx = domain.WithActor(ctx, cmd.Provider)
+	}
+
+	var pr *domain.PullRequest
+	var err error
+
+	switch cmd.Action {
+	case domain.IntegrationActionOpen:
+		pr, err = s.open(ctx, cmd)
+	case domain.IntegrationActionReady:
+		pr, err = s.prService.MarkReady(ctx, cmd.PullRequestID, "", 0)
+	case domain.IntegrationActionClose:
+		pr, err = s.prService.ClosePR(ctx, cmd.PullRequestID, 0)
+	case domain.IntegrationActionReopen:
+		pr, err = s.prService.ReopenPR(ctx, cmd.PullRequestID, "", 0)
+	case domain.IntegrationActionMerge:
+		// PR уже смержен во внешней системе: фиксируем факт, даже если одобрений не хватает.
+		// Запрос интеграции аутентифицирован подписью провайдера, поэтому мерж записываем на него
+		pr, err = s.prService.MergePR(ctx, cmd.PullRequestID, cmd.Provider, 0)
+	default:
+		return result, nil
+	}
+
+	if err != nil {
+		var appErr *domain.AppError
+		if errors.As(err, &appErr) {
+			if _, ignorable := ignorableIntegrationErrors[appErr.Code]; ignorable {
+				result.Reason = appErr.Message
+				return result, nil
+			}
+		}
+		return nil, err
+	}
+
+	result.PR = pr
+	return result, nil
+}
+
+func (s *IntegrationService) open(ctx context.Context, cmd *domain.IntegrationCommand) (*domain.PullRequest, error) {
+	authorID, err := s.mappingRepo.GetUserID(ctx, cmd.Provider, cmd.AuthorLogin)
+	if err != nil {
+		return nil, err
+	}
+
+	// Непривязанный проект ревьюит команда автора
+	var teamName string
+	if cmd.Project != "" {
+		teamName, err = s.projectRepo.GetTeam(ctx, cmd.Provider, cmd.Project)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	status := domain.PRStatusOpen
+	if cmd.Draft {
+		status = domain.PRStatusDraft
+	}
+
+	return s.prService.CreatePR(ctx, &domain.PullRequest{
+		ID:                cmd.PullRequestID,
+		Name:              cmd.Name,
+		AuthorID:          authorID,
+		Status:            status,
+		ReviewTeam:        teamName,
+		AssignedReviewers: []string{},
+		CreatedAt:         time.Now(),
+		PRMetadata:        cmd.Metadata,
+	}, "")
+}
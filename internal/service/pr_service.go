@@ -9,182 +9,895 @@ import (
 )
 
 type PullRequestService struct {
-	prRepo   repository.PullRequestRepository
-	userRepo repository.UserRepository
-	txMgr    repository.TransactionManager
+	prRepo         repository.PullRequestRepository
+	userRepo       repository.UserRepository
+	settingsRepo   repository.TeamSettingsRepository
+	queueRepo      repository.ReviewQueueRepository
+	verdictRepo    repository.ReviewVerdictRepository
+	fallbackRepo   repository.TeamFallbackRepository
+	codeOwnersRepo repository.CodeOwnersRepository
+	ruleRepo       repository.AssignmentRuleRepository
+	historyRepo    repository.AssignmentEventRepository
+	txMgr          repository.TransactionManager
+	queue          *reviewQueue
+	events         *eventPublisher
 }
 
 func NewPullRequestService(
 	prRepo repository.PullRequestRepository,
 	userRepo repository.UserRepository,
+	settingsRepo repository.TeamSettingsRepository,
+	queueRepo repository.ReviewQueueRepository,
+	verdictRepo repository.ReviewVerdictRepository,
+	fallbackRepo repository.TeamFallbackRepository,
+	codeOwnersRepo repository.CodeOwnersRepository,
+	ruleRepo repository.AssignmentRuleRepository,
+	outboxRepo repository.OutboxRepository,
+	historyRepo repository.AssignmentEventRepository,
 	txMgr repository.TransactionManager,
 ) *PullRequestService {
+	events := &eventPublisher{outboxRepo: outboxRepo, historyRepo: historyRepo}
 	return &PullRequestService{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		txMgr:    txMgr,
+		prRepo:         prRepo,
+		userRepo:       userRepo,
+		settingsRepo:   settingsRepo,
+		queueRepo:      queueRepo,
+		verdictRepo:    verdictRepo,
+		fallbackRepo:   fallbackRepo,
+		codeOwnersRepo: codeOwnersRepo,
+		ruleRepo:       ruleRepo,
+		historyRepo:    historyRepo,
+		txMgr:          txMgr,
+		queue: &reviewQueue{
+			prRepo:       prRepo,
+			userRepo:     userRepo,
+			settingsRepo: settingsRepo,
+			queueRepo:    queueRepo,
+			events:       events,
+		},
+		events: events,
 	}
 }
 
-func (s *PullRequestService) CreatePR(ctx context.Context, prID, prName, authorID string) (*domain.PullRequest, error) {
+// CreatePR создаёт PR и назначает ревьюверов; черновику ревьюверы назначаются только при переводе в OPEN.
+// Непустой strategy переопределяет стратегию команды автора.
+func (s *PullRequestService) CreatePR(ctx context.Context, pr *domain.PullRequest, strategy string) (*domain.PullRequest, error) {
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		// Автор блокируется до конца создания: перевод в другую команду не поменяет команду ревьюверов на ходу
+		author, err := s.userRepo.GetForUpdate(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
 
-	exists, err := s.prRepo.Exists(ctx, prID)
+		teamName := reviewTeam(pr, author)
+		teamUsers, err := s.userRepo.GetByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if len(teamUsers) == 0 {
+			return domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+		}
+
+		pr.AssignedReviewers = []string{}
+		pr.PendingReviewers = 0
+
+		if err := s.prRepo.Create(ctx, pr); err != nil {
+			return err
+		}
+
+		if err := s.events.prEvent(ctx, domain.EventPRCreated, pr, nil); err != nil {
+			return err
+		}
+
+		if pr.Status == domain.PRStatusOpen {
+			if err := s.assignReviewers(ctx, pr, teamName, strategy, assignSourceCreate); err != nil {
+				return err
+			}
+		}
+
+		return s.refreshVersion(ctx, pr)
+	})
 	if err != nil {
 		return nil, err
 	}
-	if exists {
-		return nil, domain.NewAppError(domain.ErrCodePRExists, "PR id already exists")
+
+	return pr, nil
+}
+
+// reviewTeam - команда, из которой назначаются ревьюверы PR
+func reviewTeam(pr *domain.PullRequest, author *domain.User) string {
+	if pr.ReviewTeam != "" {
+		return pr.ReviewTeam
+	}
+	return author.TeamName
+}
+
+// assignReviewers подбирает ревьюверов для PR без ревьюверов по политике команды teamName
+func (s *PullRequestService) assignReviewers(ctx context.Context, pr *domain.PullRequest, teamName, strategy, source string) error {
+	settings, err := s.settingsRepo.Get(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	settings = settings.ForPullRequest(pr)
+	if strategy == "" {
+		strategy = settings.ReviewerStrategy
 	}
 
-	author, err := s.userRepo.Get(ctx, authorID)
+	rules, err := s.ruleRepo.List(ctx, teamName)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	fired := domain.MatchingRules(rules, pr)
+	for _, rule := range fired {
+		settings.MaxReviewers = max(settings.MaxReviewers, rule.Reviewers)
 	}
 
-	teamUsers, err := s.userRepo.GetByTeam(ctx, author.TeamName)
+	candidates, err := s.userRepo.GetReviewCandidates(ctx, teamName, []string{pr.AuthorID})
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	owners, err := s.codeOwnersOf(ctx, teamName, pr.ChangedFiles)
+	if err != nil {
+		return err
+	}
+
+	selector := SelectorFor(strategy)
+	selected := selectPreferringOwners(selector, withCapacity(candidates, settings), owners, settings.MaxReviewers)
+
+	// Недостающих ревьюверов добираем из запасных команд
+	excluded := []string{pr.AuthorID}
+	for _, c := range selected {
+		excluded = append(excluded, c.User.ID)
+	}
+	fallback, err := s.selectFromFallbacks(ctx, teamName, selector, excluded, settings.MaxReviewers-len(selected))
+	if err != nil {
+		return err
 	}
-	if len(teamUsers) == 0 {
-		return nil, domain.NewAppError(domain.ErrCodeNotFound, "team not found")
+	selected = append(selected, fallback...)
+
+	// shortage - сколько мест своей команды не удалось занять только из-за лимитов открытых ревью
+	shortage := max(min(settings.MaxReviewers, len(candidates))-len(selected), 0)
+	if err := settings.CheckReviewerCount(len(selected), len(candidates)); err != nil {
+		switch {
+		case shortage == 0:
+			return err
+		case !settings.QueuesOnOverflow():
+			return settings.CapacityError()
+		}
+	}
+
+	decisions := decisionsFor(strategy, teamName, selected, owners)
+	for _, c := range fallback {
+		excluded = append(excluded, c.User.ID)
+	}
+
+	// Ревьюверы из других команд, которых требуют сработавшие правила, добавляются сверх лимита своей команды
+	pr.FiredRules = make([]domain.RuleFiring, len(fired))
+	for i, rule := range fired {
+		pr.FiredRules[i] = domain.RuleFiring{
+			PullRequestID:     pr.ID,
+			TeamName:          teamName,
+			RuleName:          rule.Name,
+			Reviewers:         rule.Reviewers,
+			ExtraTeam:         rule.ExtraTeam,
+			ExtraReviewers:    rule.ExtraReviewers,
+			AssignedReviewers: []string{},
+			Source:            source,
+			FiredAt:           time.Now(),
+		}
+		if rule.ExtraReviewers == 0 {
+			continue
+		}
+
+		extra, err := s.selectFromTeam(ctx, rule.ExtraTeam, selector, excluded, rule.ExtraReviewers)
+		if err != nil {
+			return err
+		}
+		for _, c := range extra {
+			excluded = append(excluded, c.User.ID)
+			pr.FiredRules[i].AssignedReviewers = append(pr.FiredRules[i].AssignedReviewers, c.User.ID)
+			decisions = append(decisions, domain.ReviewerDecision{
+				ReviewerID:     c.User.ID,
+				Strategy:       strategy,
+				OpenReviews:    c.OpenReviews,
+				AssignmentRule: rule.Name,
+			})
+		}
+		selected = append(selected, extra...)
+	}
+	if err := s.ruleRepo.RecordFirings(ctx, pr.FiredRules); err != nil {
+		return err
+	}
+
+	for _, c := range selected {
+		if err := s.prRepo.AddReviewer(ctx, pr.ID, c.User.ID); err != nil {
+			return err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, c.User.ID)
+	}
+	pr.Decisions = decisions
+	pr.Reviews = domain.LatestReviews(pr.AssignedReviewers, nil)
+
+	if shortage > 0 && settings.QueuesOnOverflow() {
+		if err := s.queueRepo.Enqueue(ctx, pr.ID, teamName, shortage); err != nil {
+			return err
+		}
+		pr.PendingReviewers = shortage
+	}
+
+	return s.events.reviewersAssigned(ctx, pr.ID, pr.AssignedReviewers, pr.PendingReviewers, source)
+}
+
+// GetPR возвращает PR с ревьюверами, их вердиктами и историей назначений.
+// Всё читается из одного снимка, чтобы ревьюверы и история не разошлись при параллельном переназначении
+func (s *PullRequestService) GetPR(ctx context.Context, prID string) (*domain.PullRequest, []domain.AssignmentEvent, error) {
+	var pr *domain.PullRequest
+	var history []domain.AssignmentEvent
+	err := s.txMgr.WithinTx(ctx, repository.TxReadOnly, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.Get(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.attachReviews(ctx, pr); err != nil {
+			return err
+		}
+
+		pr.PendingReviewers, err = s.queueRepo.CountByPR(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		history, err = s.historyRepo.ListByPR(ctx, prID)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
 	}
+	return pr, history, nil
+}
 
-	tx, err := s.txMgr.BeginTx(ctx)
+// ListPRs возвращает страницу списка PR; query.Limit вне допустимого диапазона заменяется ближайшим допустимым
+func (s *PullRequestService) ListPRs(ctx context.Context, query domain.PRListQuery) (*domain.PRPage, error) {
+	if query.SortBy == "" {
+		query.SortBy = domain.PRSortCreatedAt
+	}
+	if query.After != nil && query.After.SortBy != query.SortBy {
+		return nil, domain.NewAppError(domain.ErrCodeInvalidInput, "cursor does not match sort")
+	}
+	if query.Limit <= 0 {
+		query.Limit = domain.DefaultPRPageSize
+	}
+	pageSize := min(query.Limit, domain.MaxPRPageSize)
+
+	// Лишний PR показывает, что за страницей есть продолжение
+	query.Limit = pageSize + 1
+	var prs []domain.PullRequest
+	// PR и их ревьюверы читаются разными запросами, снимок у них должен быть общий
+	err := s.txMgr.WithinTx(ctx, repository.TxReadOnly, func(ctx context.Context) error {
+		var err error
+		prs, err = s.prRepo.List(ctx, query)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer tx.Rollback()
 
-	pr := &domain.PullRequest{
-		ID:                prID,
-		Name:              prName,
-		AuthorID:          authorID,
-		Status:            domain.PRStatusOpen,
-		CreatedAt:         time.Now(),
-		AssignedReviewers: []string{},
+	page := &domain.PRPage{PullRequests: prs}
+	if len(prs) > pageSize {
+		page.PullRequests = prs[:pageSize]
+		page.Next = domain.CursorOf(&prs[pageSize-1], query.SortBy)
 	}
+	return page, nil
+}
 
-	err = s.prRepo.Create(ctx, tx, pr)
+// ListRuleFirings возвращает правила назначения, сработавшие на PR, в порядке срабатывания
+func (s *PullRequestService) ListRuleFirings(ctx context.Context, prID string) ([]domain.RuleFiring, error) {
+	exists, err := s.prRepo.Exists(ctx, prID)
 	if err != nil {
 		return nil, err
 	}
+	if !exists {
+		return nil, domain.NewAppError(domain.ErrCodeNotFound, "PR not found")
+	}
+
+	return s.ruleRepo.ListFirings(ctx, prID)
+}
+
+// MarkReady переводит черновик в OPEN и назначает ревьюверов.
+// Во всех изменяющих PR методах ненулевой expectedVersion должен совпасть с текущей версией PR.
+func (s *PullRequestService) MarkReady(ctx context.Context, prID, strategy string, expectedVersion int64) (*domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, domain.PRActionReady, strategy, expectedVersion)
+}
+
+// ClosePR закрывает PR без мержа и освобождает его ревьюверов
+func (s *PullRequestService) ClosePR(ctx context.Context, prID string, expectedVersion int64) (*domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, domain.PRActionClose, "", expectedVersion)
+}
+
+// ReopenPR возвращает закрытый PR в OPEN с новыми ревьюверами
+func (s *PullRequestService) ReopenPR(ctx context.Context, prID, strategy string, expectedVersion int64) (*domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, domain.PRActionReopen, strategy, expectedVersion)
+}
+
+// statusEvents - событие, публикуемое при смене статуса действием
+var statusEvents = map[string]string{
+	domain.PRActionReady:  domain.EventPRReady,
+	domain.PRActionClose:  domain.EventPRClosed,
+	domain.PRActionReopen: domain.EventPRReopened,
+}
+
+func (s *PullRequestService) changeStatus(ctx context.Context, prID, action, strategy string, expectedVersion int64) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+
+		if err := pr.Apply(action, time.Now()); err != nil {
+			return err
+		}
+
+		if err := s.prRepo.Update(ctx, pr); err != nil {
+			return err
+		}
+
+		author, err := s.userRepo.Get(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		released := pr.AssignedReviewers
+		if action == domain.PRActionClose {
+			if err := s.releaseReviewers(ctx, pr, reviewTeam(pr, author)); err != nil {
+				return err
+			}
+		} else {
+			released = nil
+		}
+
+		if err := s.events.prEvent(ctx, statusEvents[action], pr, released); err != nil {
+			return err
+		}
+
+		if domain.NeedsReviewers(action) {
+			if err := s.assignReviewers(ctx, pr, reviewTeam(pr, author), strategy, action); err != nil {
+				return err
+			}
+		}
 
-	reviewers, err := s.userRepo.GetActiveTeammates(ctx, authorID, 2)
+		if err := s.attachReviews(ctx, pr); err != nil {
+			return err
+		}
+		return s.refreshVersion(ctx, pr)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	for _, r := range reviewers {
-		if err := s.prRepo.AddReviewer(ctx, tx, pr.ID, r.ID); err != nil {
-			return nil, err
+	return pr, nil
+}
+
+// releaseReviewers снимает всех ревьюверов PR и отдаёт освободившиеся места очереди команды
+func (s *PullRequestService) releaseReviewers(ctx context.Context, pr *domain.PullRequest, teamName string) error {
+	removals := make([]domain.ReviewAssignment, len(pr.AssignedReviewers))
+	for i, reviewerID := range pr.AssignedReviewers {
+		removals[i] = domain.ReviewAssignment{PullRequestID: pr.ID, ReviewerID: reviewerID, AuthorID: pr.AuthorID}
+	}
+	if len(removals) > 0 {
+		if err := s.prRepo.RemoveReviewersBulk(ctx, removals); err != nil {
+			return err
 		}
-		pr.AssignedReviewers = append(pr.AssignedReviewers, r.ID)
 	}
+	if err := s.events.reviewersReleased(ctx, pr.ID, pr.AssignedReviewers, changeReasonClose); err != nil {
+		return err
+	}
+
+	if err := s.queueRepo.DeleteByPR(ctx, pr.ID); err != nil {
+		return err
+	}
+	pr.AssignedReviewers = []string{}
+
+	return s.queue.drain(ctx, teamName)
+}
+
+// MergePR мержит PR, если выполнена политика одобрений команды автора.
+// Непустой forcedBy - проверенный исполнитель, которому разрешено пропустить проверку одобрений;
+// факт обхода и forcedBy сохраняются в PR.
+// PR заблокирован до конца мержа, поэтому параллельное переназначение не потеряется.
+func (s *PullRequestService) MergePR(ctx context.Context, prID string, forcedBy string, expectedVersion int64) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+
+		next, err := domain.NextPRStatus(pr.Status, domain.PRActionMerge)
+		if err != nil {
+			return err
+		}
+		// Уже смерженный PR возвращаем как есть
+		if next == pr.Status {
+			return s.attachReviews(ctx, pr)
+		}
+
+		author, err := s.userRepo.Get(ctx, pr.AuthorID)
+		if err != nil {
+			return err
+		}
+
+		teamName := reviewTeam(pr, author)
+		settings, err := s.settingsRepo.Get(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if err := s.attachReviews(ctx, pr); err != nil {
+			return err
+		}
+
+		if err := settings.CheckApprovals(pr.Reviews); err != nil {
+			if forcedBy == "" {
+				return err
+			}
+			pr.MergeForced = true
+			pr.MergeForcedBy = forcedBy
+		}
+
+		if err := pr.Apply(domain.PRActionMerge, time.Now()); err != nil {
+			return err
+		}
+
+		if err := s.prRepo.Update(ctx, pr); err != nil {
+			return err
+		}
+
+		// Смерженному PR ревьюверы больше не нужны, а освободившиеся места отдаём очереди команды
+		if err := s.queueRepo.DeleteByPR(ctx, pr.ID); err != nil {
+			return err
+		}
+
+		if err := s.events.prEvent(ctx, domain.EventPRMerged, pr, nil); err != nil {
+			return err
+		}
 
-	if err := tx.Commit(); err != nil {
+		return s.queue.drain(ctx, teamName)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return pr, nil
 }
 
-func (s *PullRequestService) MergePR(ctx context.Context, prID string) (*domain.PullRequest, error) {
-	pr, err := s.prRepo.Get(ctx, prID)
+// UpdatePR меняет название и метаданные PR; смерженный PR не меняется.
+// Ревьюверы не переподбираются: правила размера применяются при следующем назначении.
+func (s *PullRequestService) UpdatePR(
+	ctx context.Context, prID string, update func(*domain.PullRequest), expectedVersion int64,
+) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+
+		if err := pr.CheckAction(domain.PRActionUpdate); err != nil {
+			return err
+		}
+
+		update(pr)
+
+		if err := s.prRepo.Update(ctx, pr); err != nil {
+			return err
+		}
+
+		if err := s.events.prEvent(ctx, domain.EventPRUpdated, pr, nil); err != nil {
+			return err
+		}
 
+		return s.attachReviews(ctx, pr)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if pr.Status == domain.PRStatusMerged {
-		return pr, nil
+	return pr, nil
+}
+
+// ReassignReviewer заменяет ревьювера на другого из его команды. Непустой strategy переопределяет стратегию команды.
+// Одобрившего PR ревьювера можно заменить только с force. Транзакция SERIALIZABLE: две параллельные
+// замены не выберут одного и того же ревьювера сверх его лимита.
+func (s *PullRequestService) ReassignReviewer(
+	ctx context.Context, prID, oldUserID, strategy string, force bool, expectedVersion int64,
+) (*domain.PullRequest, string, error) {
+	var pr *domain.PullRequest
+	var newReviewerID string
+	err := s.txMgr.WithinTx(ctx, repository.TxSerializable, func(ctx context.Context) error {
+		newReviewerID = ""
+
+		var err error
+		pr, err = s.prRepo.GetForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+
+		if err := pr.CheckAction(domain.PRActionReassign); err != nil {
+			return err
+		}
+
+		isAssigned := false
+		for _, reviewerID := range pr.AssignedReviewers {
+			if reviewerID == oldUserID {
+				isAssigned = true
+				break
+			}
+		}
+		if !isAssigned {
+			return domain.NewAppError(domain.ErrCodeNotAssigned, "reviewer is not assigned to this PR")
+		}
+
+		if !force {
+			latest, err := s.verdictRepo.GetLatestByPR(ctx, prID)
+			if err != nil {
+				return err
+			}
+			for _, v := range latest {
+				if v.ReviewerID == oldUserID && v.State == domain.ReviewStateApproved {
+					return domain.NewAppError(domain.ErrCodeReviewerApproved, "reviewer has already approved this PR")
+				}
+			}
+		}
+
+		oldReviewer, err := s.userRepo.Get(ctx, oldUserID)
+		if err != nil {
+			return err
+		}
+
+		excludeIDs := []string{pr.AuthorID, oldUserID}
+		for _, reviewerID := range pr.AssignedReviewers {
+			if reviewerID != oldUserID {
+				excludeIDs = append(excludeIDs, reviewerID)
+			}
+		}
+
+		settings, err := s.settingsRepo.Get(ctx, oldReviewer.TeamName)
+		if err != nil {
+			return err
+		}
+		teamStrategy := strategy
+		if teamStrategy == "" {
+			teamStrategy = settings.ReviewerStrategy
+		}
+
+		candidates, err := s.userRepo.GetReviewCandidates(ctx, oldReviewer.TeamName, excludeIDs)
+		if err != nil {
+			return err
+		}
+
+		owners, err := s.codeOwnersOf(ctx, oldReviewer.TeamName, pr.ChangedFiles)
+		if err != nil {
+			return err
+		}
+
+		selector := SelectorFor(teamStrategy)
+		selected := selectPreferringOwners(selector, withCapacity(candidates, settings), owners, 1)
+		if len(selected) == 0 {
+			selected, err = s.selectFromFallbacks(ctx, oldReviewer.TeamName, selector, excludeIDs, 1)
+			if err != nil {
+				return err
+			}
+		}
+		if len(selected) == 0 && len(candidates) == 0 {
+			return domain.NewAppError(domain.ErrCodeNoCandidate, "no active replacement candidate in team")
+		}
+
+		if len(selected) == 0 {
+			if !settings.QueuesOnOverflow() {
+				return settings.CapacityError()
+			}
+			return s.queueReplacement(ctx, pr, oldUserID, oldReviewer.TeamName)
+		}
+		newReviewer := selected[0].User
+		newReviewerID = newReviewer.ID
+
+		if err := s.prRepo.RemoveReviewer(ctx, prID, oldUserID); err != nil {
+			return err
+		}
+
+		if err := s.prRepo.AddReviewer(ctx, prID, newReviewer.ID); err != nil {
+			return err
+		}
+
+		newReviewers := []string{}
+		for _, rid := range pr.AssignedReviewers {
+			if rid != oldUserID {
+				newReviewers = append(newReviewers, rid)
+			}
+		}
+		newReviewers = append(newReviewers, newReviewer.ID)
+		pr.AssignedReviewers = newReviewers
+		pr.Decisions = decisionsFor(teamStrategy, oldReviewer.TeamName, selected, owners)
+
+		if err := s.events.reviewerChanged(ctx, domain.EventReviewerReassigned, reviewerChangedPayload{
+			PullRequestID: prID,
+			OldReviewerID: oldUserID,
+			NewReviewerID: newReviewer.ID,
+			Reason:        changeReasonManual,
+		}); err != nil {
+			return err
+		}
+
+		if err := s.attachReviews(ctx, pr); err != nil {
+			return err
+		}
+		return s.refreshVersion(ctx, pr)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return pr, newReviewerID, nil
+}
+
+// queueReplacement снимает ревьювера и откладывает назначение замены, пока у кого-то в команде не освободится место
+func (s *PullRequestService) queueReplacement(
+	ctx context.Context, pr *domain.PullRequest, oldUserID, teamName string,
+) error {
+	if err := s.prRepo.RemoveReviewer(ctx, pr.ID, oldUserID); err != nil {
+		return err
+	}
+	if err := s.queueRepo.Enqueue(ctx, pr.ID, teamName, 1); err != nil {
+		return err
 	}
 
-	tx, err := s.txMgr.BeginTx(ctx)
+	pending, err := s.queueRepo.CountByPR(ctx, pr.ID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer tx.Rollback()
 
-	now := time.Now()
-	pr.Status = domain.PRStatusMerged
-	pr.MergedAt = &now
+	newReviewers := []string{}
+	for _, rid := range pr.AssignedReviewers {
+		if rid != oldUserID {
+			newReviewers = append(newReviewers, rid)
+		}
+	}
+	pr.AssignedReviewers = newReviewers
+	pr.PendingReviewers = pending
+
+	if err := s.events.reviewerChanged(ctx, domain.EventReviewerReassigned, reviewerChangedPayload{
+		PullRequestID: pr.ID,
+		OldReviewerID: oldUserID,
+		Queued:        true,
+		Reason:        changeReasonManual,
+	}); err != nil {
+		return err
+	}
 
-	if err := s.prRepo.Update(ctx, tx, pr); err != nil {
-		return nil, err
+	if err := s.attachReviews(ctx, pr); err != nil {
+		return err
 	}
+	return s.refreshVersion(ctx, pr)
+}
+
+// SubmitReview сохраняет вердикт назначенного ревьювера. Вердикты не перезаписываются:
+// актуальным считается последний. Вердикт увеличивает версию PR.
+func (s *PullRequestService) SubmitReview(
+	ctx context.Context, verdict *domain.ReviewVerdict, expectedVersion int64,
+) (*domain.PullRequest, error) {
+	var pr *domain.PullRequest
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		var err error
+		pr, err = s.prRepo.GetForUpdate(ctx, verdict.PullRequestID)
+		if err != nil {
+			return err
+		}
+
+		if err := pr.CheckVersion(expectedVersion); err != nil {
+			return err
+		}
+
+		if err := pr.CheckAction(domain.PRActionReview); err != nil {
+			return err
+		}
+
+		isAssigned := false
+		for _, reviewerID := range pr.AssignedReviewers {
+			if reviewerID == verdict.ReviewerID {
+				isAssigned = true
+				break
+			}
+		}
+		if !isAssigned {
+			return domain.NewAppError(domain.ErrCodeNotAssigned, "reviewer is not assigned to this PR")
+		}
 
-	if err := tx.Commit(); err != nil {
+		if err := s.verdictRepo.Create(ctx, verdict); err != nil {
+			return err
+		}
+
+		if err := s.attachReviews(ctx, pr); err != nil {
+			return err
+		}
+		return s.refreshVersion(ctx, pr)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return pr, nil
 }
 
-func (s *PullRequestService) ReassignReviewer(ctx context.Context, prID, oldUserID string) (*domain.PullRequest, string, error) {
-
-	tx, err := s.txMgr.BeginTx(ctx)
+// refreshVersion перечитывает версию PR: назначение и снятие ревьюверов и вердикты увеличивают её в обход pr
+func (s *PullRequestService) refreshVersion(ctx context.Context, pr *domain.PullRequest) error {
+	version, err := s.prRepo.GetVersion(ctx, pr.ID)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
-	defer tx.Rollback()
+	pr.Version = version
+	return nil
+}
 
-	pr, err := s.prRepo.GetForUpdate(ctx, tx, prID)
+// attachReviews заполняет последние вердикты назначенных ревьюверов
+func (s *PullRequestService) attachReviews(ctx context.Context, pr *domain.PullRequest) error {
+	latest, err := s.verdictRepo.GetLatestByPR(ctx, pr.ID)
 	if err != nil {
-		return nil, "", err
+		return err
 	}
+	pr.Reviews = domain.LatestReviews(pr.AssignedReviewers, latest)
+	return nil
+}
 
-	if pr.Status == domain.PRStatusMerged {
-		return nil, "", domain.NewAppError(domain.ErrCodePRMerged, "cannot reassign on merged PR")
+// ProcessReviewQueues раздаёт отложенные места ревьюверов во всех командах, где они есть.
+// Нужен для случаев, когда лимит освобождается не мержем: смена лимита, активация пользователя.
+func (s *PullRequestService) ProcessReviewQueues(ctx context.Context) error {
+	teams, err := s.queueRepo.GetQueuedTeams(ctx)
+	if err != nil {
+		return err
 	}
 
-	isAssigned := false
-	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID == oldUserID {
-			isAssigned = true
-			break
+	for _, teamName := range teams {
+		if err := s.drainTeamQueue(ctx, teamName); err != nil {
+			return err
 		}
 	}
-	if !isAssigned {
-		return nil, "", domain.NewAppError(domain.ErrCodeNotAssigned, "reviewer is not assigned to this PR")
+	return nil
+}
+
+func (s *PullRequestService) drainTeamQueue(ctx context.Context, teamName string) error {
+	return s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		return s.queue.drain(ctx, teamName)
+	})
+}
+
+// selectFromFallbacks выбирает до count ревьюверов из запасных команд teamName по порядку приоритета.
+// В каждой запасной команде действуют её собственные лимиты открытых ревью.
+func (s *PullRequestService) selectFromFallbacks(
+	ctx context.Context, teamName string, selector ReviewerSelector, excludeIDs []string, count int,
+) ([]domain.ReviewCandidate, error) {
+	if count <= 0 {
+		return nil, nil
 	}
 
-	oldReviewer, err := s.userRepo.Get(ctx, oldUserID)
+	fallbackTeams, err := s.fallbackRepo.Get(ctx, teamName)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	excludeIDs := []string{pr.AuthorID, oldUserID}
-	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID != oldUserID {
-			excludeIDs = append(excludeIDs, reviewerID)
+	var selected []domain.ReviewCandidate
+	for _, fallbackTeam := range fallbackTeams {
+		chosen, err := s.selectFromTeam(ctx, fallbackTeam, selector, excludeIDs, count-len(selected))
+		if err != nil {
+			return nil, err
+		}
+
+		selected = append(selected, chosen...)
+		if len(selected) == count {
+			break
 		}
 	}
+	return selected, nil
+}
 
-	newReviewer, err := s.userRepo.FindReplacementReviewer(ctx, tx, oldReviewer.TeamName, excludeIDs)
+// selectFromTeam выбирает до count ревьюверов из чужой команды с учётом её лимитов открытых ревью
+func (s *PullRequestService) selectFromTeam(
+	ctx context.Context, teamName string, selector ReviewerSelector, excludeIDs []string, count int,
+) ([]domain.ReviewCandidate, error) {
+	settings, err := s.settingsRepo.Get(ctx, teamName)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
-	if err := s.prRepo.RemoveReviewer(ctx, tx, prID, oldUserID); err != nil {
-		return nil, "", err
+	candidates, err := s.userRepo.GetReviewCandidates(ctx, teamName, excludeIDs)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.prRepo.AddReviewer(ctx, tx, prID, newReviewer.ID); err != nil {
-		return nil, "", err
+	return selector.Select(withCapacity(candidates, settings), count), nil
+}
+
+// codeOwnersOf возвращает владельцев затронутых путей по CODEOWNERS команды; nil, если файла нет
+func (s *PullRequestService) codeOwnersOf(
+	ctx context.Context, teamName string, paths []string,
+) (map[string]*domain.CodeOwnersRule, error) {
+	if len(paths) == 0 {
+		return nil, nil
 	}
 
-	newReviewers := []string{}
-	for _, rid := range pr.AssignedReviewers {
-		if rid != oldUserID {
-			newReviewers = append(newReviewers, rid)
-		}
+	content, err := s.codeOwnersRepo.Get(ctx, teamName)
+	if err != nil || content == "" {
+		return nil, err
 	}
-	newReviewers = append(newReviewers, newReviewer.ID)
-	pr.AssignedReviewers = newReviewers
 
-	if err := tx.Commit(); err != nil {
-		return nil, "", err
+	codeOwners, err := domain.ParseCodeOwners(content)
+	if err != nil {
+		return nil, err
+	}
+	return codeOwners.OwnersOf(paths), nil
+}
+
+// selectPreferringOwners выбирает до count ревьюверов: сначала среди владельцев затронутых путей,
+// оставшиеся места - среди прочих кандидатов. Обе группы отбираются одним selector.
+func selectPreferringOwners(
+	selector ReviewerSelector, candidates []domain.ReviewCandidate, owners map[string]*domain.CodeOwnersRule, count int,
+) []domain.ReviewCandidate {
+	if len(owners) == 0 {
+		return selector.Select(candidates, count)
 	}
 
-	return pr, newReviewer.ID, nil
+	var ownerPool, rest []domain.ReviewCandidate
+	for _, c := range candidates {
+		if _, ok := owners[c.User.ID]; ok {
+			ownerPool = append(ownerPool, c)
+		} else {
+			rest = append(rest, c)
+		}
+	}
+
+	selected := selector.Select(ownerPool, count)
+	return append(selected, selector.Select(rest, count-len(selected))...)
+}
+
+// decisionsFor описывает выбор ревьюверов; ревьюверы не из teamName помечаются запасной командой,
+// владельцы затронутых путей - правилом CODEOWNERS
+func decisionsFor(
+	strategy, teamName string, selected []domain.ReviewCandidate, owners map[string]*domain.CodeOwnersRule,
+) []domain.ReviewerDecision {
+	decisions := make([]domain.ReviewerDecision, len(selected))
+	for i, c := range selected {
+		decisions[i] = domain.ReviewerDecision{
+			ReviewerID:  c.User.ID,
+			Strategy:    strategy,
+			OpenReviews: c.OpenReviews,
+		}
+		if c.User.TeamName != teamName {
+			decisions[i].FallbackTeam = c.User.TeamName
+		} else {
+			decisions[i].CodeOwnersRule = owners[c.User.ID]
+		}
+	}
+	return decisions
 }
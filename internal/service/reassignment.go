@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+// reviewReassigner снимает пользователей с открытых ревью, подбирая замену из команды.
+// Используется везде, где ревьювер перестаёт быть доступен: деактивация, отсутствие.
+type reviewReassigner struct {
+	prRepo       repository.PullRequestRepository
+	userRepo     repository.UserRepository
+	settingsRepo repository.TeamSettingsRepository
+	queueRepo    repository.ReviewQueueRepository
+	events       *eventPublisher
+}
+
+// reassignAway заменяет ревьюверов из assignments кандидатами команды teamName. Если кандидатов нет,
+// ревьювер просто снимается; если все кандидаты упёрлись в лимит - действует политика команды.
+// reason попадает в публикуемые события.
+func (r *reviewReassigner) reassignAway(ctx context.Context, teamName, reason string, assignments []domain.ReviewAssignment) error {
+	if len(assignments) == 0 {
+		return nil
+	}
+
+	settings, err := r.settingsRepo.Get(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	selector := SelectorFor(settings.ReviewerStrategy)
+
+	candidates, err := r.userRepo.GetReviewCandidates(ctx, teamName, nil)
+	if err != nil {
+		return err
+	}
+
+	prIDsSet := make(map[string]struct{})
+	for _, a := range assignments {
+		prIDsSet[a.PullRequestID] = struct{}{}
+	}
+	uniquePRIDs := make([]string, 0, len(prIDsSet))
+	for id := range prIDsSet {
+		uniquePRIDs = append(uniquePRIDs, id)
+	}
+
+	currentReviewersMap, err := r.prRepo.GetReviewersByPRs(ctx, uniquePRIDs)
+	if err != nil {
+		return err
+	}
+
+	replacements := []domain.ReviewReplacement{}
+	removals := []domain.ReviewAssignment{}
+	queuedPRs := []string{}
+	changes := []reviewerChangedPayload{}
+
+	for _, assignment := range assignments {
+		prID := assignment.PullRequestID
+		excluded := append([]string{assignment.AuthorID}, currentReviewersMap[prID]...)
+		pool := excludeCandidates(candidates, excluded)
+
+		selected := selector.Select(withCapacity(pool, settings), 1)
+		if len(selected) == 0 {
+			// Кандидаты есть, но у всех исчерпан лимит - решает политика команды
+			if len(pool) > 0 {
+				if !settings.QueuesOnOverflow() {
+					return settings.CapacityError()
+				}
+				queuedPRs = append(queuedPRs, prID)
+			}
+			removals = append(removals, assignment)
+			changes = append(changes, reviewerChangedPayload{
+				PullRequestID: prID,
+				OldReviewerID: assignment.ReviewerID,
+				Queued:        len(pool) > 0,
+				Reason:        reason,
+			})
+			continue
+		}
+
+		newReviewerID := selected[0].User.ID
+		replacements = append(replacements, domain.ReviewReplacement{
+			PullRequestID: prID,
+			OldUserID:     assignment.ReviewerID,
+			NewUserID:     newReviewerID,
+		})
+		currentReviewersMap[prID] = append(currentReviewersMap[prID], newReviewerID)
+		markAssigned(candidates, newReviewerID)
+		changes = append(changes, reviewerChangedPayload{
+			PullRequestID: prID,
+			OldReviewerID: assignment.ReviewerID,
+			NewReviewerID: newReviewerID,
+			Reason:        reason,
+		})
+	}
+
+	if len(replacements) > 0 {
+		if err := r.prRepo.ReplaceReviewersBulk(ctx, replacements); err != nil {
+			return err
+		}
+	}
+	if len(removals) > 0 {
+		if err := r.prRepo.RemoveReviewersBulk(ctx, removals); err != nil {
+			return err
+		}
+	}
+	for _, prID := range queuedPRs {
+		if err := r.queueRepo.Enqueue(ctx, prID, teamName, 1); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range changes {
+		eventType := domain.EventReviewerReassigned
+		if change.NewReviewerID == "" {
+			eventType = domain.EventReviewerRemoved
+		}
+		if err := r.events.reviewerChanged(ctx, eventType, change); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
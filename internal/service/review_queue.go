@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+// withCapacity оставляет только кандидатов, у которых не исчерпан лимит открытых ревью
+func withCapacity(candidates []domain.ReviewCandidate, settings *domain.TeamSettings) []domain.ReviewCandidate {
+	result := make([]domain.ReviewCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if c.HasCapacity(settings.DefaultMaxOpenReviews) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// reviewQueue раздаёт отложенные места ревьюверов, когда у участников команды освобождается лимит
+type reviewQueue struct {
+	prRepo       repository.PullRequestRepository
+	userRepo     repository.UserRepository
+	settingsRepo repository.TeamSettingsRepository
+	queueRepo    repository.ReviewQueueRepository
+	events       *eventPublisher
+}
+
+// drain назначает ревьюверов на отложенные места команды в порядке очереди, пока есть свободные кандидаты
+func (q *reviewQueue) drain(ctx context.Context, teamName string) error {
+	queued, err := q.queueRepo.ListByTeam(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if len(queued) == 0 {
+		return nil
+	}
+
+	settings, err := q.settingsRepo.Get(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	selector := SelectorFor(settings.ReviewerStrategy)
+
+	candidates, err := q.userRepo.GetReviewCandidates(ctx, teamName, nil)
+	if err != nil {
+		return err
+	}
+
+	reviewers := make(map[string][]string)
+	for _, item := range queued {
+		current, ok := reviewers[item.PullRequestID]
+		if !ok {
+			current = item.AssignedReviewers
+		}
+
+		excluded := append([]string{item.AuthorID}, current...)
+		selected := selector.Select(withCapacity(excludeCandidates(candidates, excluded), settings), 1)
+		if len(selected) == 0 {
+			// У другого PR может быть другой набор исключений, поэтому не прерываемся
+			continue
+		}
+
+		reviewerID := selected[0].User.ID
+		if err := q.prRepo.AddReviewer(ctx, item.PullRequestID, reviewerID); err != nil {
+			return err
+		}
+		if err := q.queueRepo.Delete(ctx, item.ID); err != nil {
+			return err
+		}
+
+		reviewers[item.PullRequestID] = append(current, reviewerID)
+		markAssigned(candidates, reviewerID)
+
+		if err := q.events.reviewersAssigned(ctx, item.PullRequestID, []string{reviewerID}, 0, assignSourceQueue); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
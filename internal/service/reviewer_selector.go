@@ -0,0 +1,111 @@
+package service
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// ReviewerSelector выбирает до count ревьюверов из уже отфильтрованного списка кандидатов
+// (активные, не автор, не назначенные ранее). Порядок результата - порядок назначения.
+type ReviewerSelector interface {
+	Select(candidates []domain.ReviewCandidate, count int) []domain.ReviewCandidate
+}
+
+var reviewerSelectors = map[string]ReviewerSelector{
+	domain.ReviewerStrategyRandom:      randomSelector{},
+	domain.ReviewerStrategyRoundRobin:  roundRobinSelector{},
+	domain.ReviewerStrategyLeastLoaded: leastLoadedSelector{},
+}
+
+// SelectorFor возвращает селектор для стратегии команды, неизвестная стратегия трактуется как random
+func SelectorFor(strategy string) ReviewerSelector {
+	if selector, ok := reviewerSelectors[strategy]; ok {
+		return selector
+	}
+	return reviewerSelectors[domain.ReviewerStrategyRandom]
+}
+
+// randomSelector - равновероятный выбор
+type randomSelector struct{}
+
+func (randomSelector) Select(candidates []domain.ReviewCandidate, count int) []domain.ReviewCandidate {
+	return firstN(shuffled(candidates), count)
+}
+
+// roundRobinSelector - по очереди: первыми идут те, кого дольше всех не назначали
+type roundRobinSelector struct{}
+
+func (roundRobinSelector) Select(candidates []domain.ReviewCandidate, count int) []domain.ReviewCandidate {
+	result := shuffled(candidates)
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i].LastAssignedAt, result[j].LastAssignedAt
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+	return firstN(result, count)
+}
+
+// leastLoadedSelector - первыми идут кандидаты с наименьшим числом открытых ревью
+type leastLoadedSelector struct{}
+
+func (leastLoadedSelector) Select(candidates []domain.ReviewCandidate, count int) []domain.ReviewCandidate {
+	result := shuffled(candidates)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].OpenReviews < result[j].OpenReviews
+	})
+	return firstN(result, count)
+}
+
+// shuffled возвращает перемешанную копию, чтобы равные кандидаты выбирались случайно
+func shuffled(candidates []domain.ReviewCandidate) []domain.ReviewCandidate {
+	result := make([]domain.ReviewCandidate, len(candidates))
+	copy(result, candidates)
+	rand.Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result
+}
+
+func firstN(candidates []domain.ReviewCandidate, n int) []domain.ReviewCandidate {
+	if n < 0 {
+		n = 0
+	}
+	if len(candidates) > n {
+		return candidates[:n]
+	}
+	return candidates
+}
+
+// excludeCandidates возвращает кандидатов, чьи id не входят в excludeIDs
+func excludeCandidates(candidates []domain.ReviewCandidate, excludeIDs []string) []domain.ReviewCandidate {
+	excluded := make(map[string]struct{}, len(excludeIDs))
+	for _, id := range excludeIDs {
+		excluded[id] = struct{}{}
+	}
+
+	result := make([]domain.ReviewCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := excluded[c.User.ID]; !ok {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// markAssigned учитывает новое назначение в загрузке кандидата, чтобы следующие
+// выборы в рамках одной операции видели актуальные данные
+func markAssigned(candidates []domain.ReviewCandidate, userID string) {
+	now := time.Now()
+	for i := range candidates {
+		if candidates[i].User.ID == userID {
+			candidates[i].OpenReviews++
+			candidates[i].LastAssignedAt = &now
+			return
+		}
+	}
+}
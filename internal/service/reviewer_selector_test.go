@@ -0,0 +1,185 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"avito/internal/domain"
+)
+
+func candidate(id string, openReviews int, lastAssigned *time.Time) domain.ReviewCandidate {
+	return domain.ReviewCandidate{
+		User:           domain.User{ID: id, TeamName: "backend", IsActive: true},
+		OpenReviews:    openReviews,
+		LastAssignedAt: lastAssigned,
+	}
+}
+
+func selectedIDs(selected []domain.ReviewCandidate) []string {
+	ids := make([]string, len(selected))
+	for i, c := range selected {
+		ids[i] = c.User.ID
+	}
+	return ids
+}
+
+func TestSelectorFor(t *testing.T) {
+	if _, ok := SelectorFor(domain.ReviewerStrategyLeastLoaded).(leastLoadedSelector); !ok {
+		t.Error("Expected least loaded selector")
+	}
+	if _, ok := SelectorFor(domain.ReviewerStrategyRoundRobin).(roundRobinSelector); !ok {
+		t.Error("Expected round robin selector")
+	}
+	if _, ok := SelectorFor("unknown").(randomSelector); !ok {
+		t.Error("Expected random selector for unknown strategy")
+	}
+}
+
+func TestRandomSelector_Select(t *testing.T) {
+	candidates := []domain.ReviewCandidate{
+		candidate("u1", 0, nil),
+		candidate("u2", 0, nil),
+		candidate("u3", 0, nil),
+	}
+
+	selected := randomSelector{}.Select(candidates, 2)
+	if len(selected) != 2 {
+		t.Fatalf("Expected 2 reviewers, got %d", len(selected))
+	}
+	if selected[0].User.ID == selected[1].User.ID {
+		t.Errorf("Expected distinct reviewers, got %v", selectedIDs(selected))
+	}
+
+	selector := randomSelector{}
+	if got := selector.Select(candidates, 5); len(got) != 3 {
+		t.Errorf("Expected all 3 candidates when asking for more, got %d", len(got))
+	}
+	if got := selector.Select(nil, 2); len(got) != 0 {
+		t.Errorf("Expected no reviewers from empty pool, got %d", len(got))
+	}
+	if candidates[0].User.ID != "u1" || candidates[2].User.ID != "u3" {
+		t.Error("Select must not reorder the input slice")
+	}
+}
+
+func TestLeastLoadedSelector_Select(t *testing.T) {
+	candidates := []domain.ReviewCandidate{
+		candidate("busy", 15, nil),
+		candidate("free", 0, nil),
+		candidate("some", 3, nil),
+	}
+
+	selected := leastLoadedSelector{}.Select(candidates, 2)
+	ids := selectedIDs(selected)
+	if len(ids) != 2 || ids[0] != "free" || ids[1] != "some" {
+		t.Errorf("Expected [free some], got %v", ids)
+	}
+}
+
+func TestLeastLoadedSelector_TiesAreRandom(t *testing.T) {
+	candidates := []domain.ReviewCandidate{
+		candidate("a", 1, nil),
+		candidate("b", 1, nil),
+		candidate("busy", 9, nil),
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 200; i++ {
+		selected := leastLoadedSelector{}.Select(candidates, 1)
+		seen[selected[0].User.ID] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Errorf("Expected both tied candidates to be picked, got %v", seen)
+	}
+	if seen["busy"] {
+		t.Error("Busiest candidate must never win against less loaded ones")
+	}
+}
+
+func TestRoundRobinSelector_Select(t *testing.T) {
+	now := time.Now()
+	recent := now.Add(-time.Minute)
+	old := now.Add(-time.Hour)
+
+	candidates := []domain.ReviewCandidate{
+		candidate("recent", 0, &recent),
+		candidate("old", 5, &old),
+		candidate("never", 0, nil),
+	}
+
+	ids := selectedIDs(roundRobinSelector{}.Select(candidates, 3))
+	want := []string{"never", "old", "recent"}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("Expected %v, got %v", want, ids)
+		}
+	}
+}
+
+func TestMarkAssigned(t *testing.T) {
+	candidates := []domain.ReviewCandidate{
+		candidate("u1", 0, nil),
+		candidate("u2", 1, nil),
+	}
+
+	markAssigned(candidates, "u1")
+	if candidates[0].OpenReviews != 1 || candidates[0].LastAssignedAt == nil {
+		t.Errorf("Expected u1 load to be updated, got %+v", candidates[0])
+	}
+
+	selected := leastLoadedSelector{}.Select(excludeCandidates(candidates, []string{"u2"}), 1)
+	if len(selected) != 1 || selected[0].User.ID != "u1" {
+		t.Errorf("Expected u1 after excluding u2, got %v", selectedIDs(selected))
+	}
+}
+
+func TestWithCapacity(t *testing.T) {
+	personal := 1
+	teamDefault := 2
+
+	full := candidate("full", 1, nil)
+	full.User.MaxOpenReviews = &personal
+	candidates := []domain.ReviewCandidate{
+		full,
+		candidate("busy", 2, nil),
+		candidate("free", 1, nil),
+	}
+
+	settings := domain.DefaultTeamSettings("backend")
+	if got := withCapacity(candidates, &settings); len(got) != 2 {
+		t.Errorf("Expected only personal limit to apply without team default, got %v", selectedIDs(got))
+	}
+
+	settings.DefaultMaxOpenReviews = &teamDefault
+	got := selectedIDs(withCapacity(candidates, &settings))
+	if len(got) != 1 || got[0] != "free" {
+		t.Errorf("Expected [free], got %v", got)
+	}
+}
+
+func TestSelectPreferringOwners(t *testing.T) {
+	candidates := []domain.ReviewCandidate{
+		candidate("u1", 0, nil),
+		candidate("u2", 0, nil),
+		candidate("owner", 5, nil),
+	}
+	rule := &domain.CodeOwnersRule{Line: 1, Pattern: "*.go", Owners: []string{"owner", "absent"}}
+	owners := map[string]*domain.CodeOwnersRule{"owner": rule, "absent": rule}
+
+	selected := selectPreferringOwners(leastLoadedSelector{}, candidates, owners, 2)
+	if len(selected) != 2 || selected[0].User.ID != "owner" {
+		t.Fatalf("Expected owner first despite load, got %v", selectedIDs(selected))
+	}
+	if selected[1].User.ID == "owner" {
+		t.Errorf("Expected remaining place filled by a non-owner, got %v", selectedIDs(selected))
+	}
+
+	decisions := decisionsFor(domain.ReviewerStrategyLeastLoaded, "backend", selected, owners)
+	if decisions[0].CodeOwnersRule != rule || decisions[1].CodeOwnersRule != nil {
+		t.Errorf("Expected only the owner decision to carry the rule, got %+v", decisions)
+	}
+
+	if got := selectPreferringOwners(leastLoadedSelector{}, candidates, nil, 1); len(got) != 1 || got[0].User.ID == "owner" {
+		t.Errorf("Expected plain strategy without owners, got %v", selectedIDs(got))
+	}
+}
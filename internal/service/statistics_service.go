@@ -18,11 +18,12 @@ func NewStatisticsService(statsRepo repository.StatisticsRepository) *Statistics
 	}
 }
 
-func (s *StatisticsService) GetStatistics(ctx context.Context) (*domain.Statistics, error) {
+// GetStatistics считает назначения и PR, отобранные filter; пользователи и команды не фильтруются
+func (s *StatisticsService) GetStatistics(ctx context.Context, filter domain.PRFilter) (*domain.Statistics, error) {
 	stats := &domain.Statistics{}
 
 	slog.Info("saving user stats")
-	assignmentsByUser, err := s.statsRepo.GetAssignmentsByUser(ctx)
+	assignmentsByUser, err := s.statsRepo.GetAssignmentsByUser(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -34,13 +35,19 @@ func (s *StatisticsService) GetStatistics(ctx context.Context) (*domain.Statisti
 
 	slog.Info("found stats for assignments", "total", stats.TotalAssignments)
 
-	assignmentsByPR, err := s.statsRepo.GetAssignmentsByPR(ctx)
+	assignmentsByPR, err := s.statsRepo.GetAssignmentsByPR(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 	stats.AssignmentsByPR = assignmentsByPR
 
-	totalPRs, err := s.statsRepo.GetTotalPRs(ctx)
+	openAssignmentsByUser, err := s.statsRepo.GetOpenAssignmentsByUser(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	stats.OpenAssignmentsByUser = openAssignmentsByUser
+
+	totalPRs, err := s.statsRepo.GetTotalPRs(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,8 @@ package service
 
 import (
 	"context"
-	"math/rand"
+	"fmt"
+	"strings"
 	"time"
 
 	"avito/internal/domain"
@@ -10,23 +11,51 @@ import (
 )
 
 type TeamService struct {
-	teamRepo repository.TeamRepository
-	userRepo repository.UserRepository
-	prRepo   repository.PullRequestRepository
-	txMgr    repository.TransactionManager
+	teamRepo       repository.TeamRepository
+	userRepo       repository.UserRepository
+	prRepo         repository.PullRequestRepository
+	settingsRepo   repository.TeamSettingsRepository
+	queueRepo      repository.ReviewQueueRepository
+	fallbackRepo   repository.TeamFallbackRepository
+	codeOwnersRepo repository.CodeOwnersRepository
+	ruleRepo       repository.AssignmentRuleRepository
+	txMgr          repository.TransactionManager
+	reassigner     *reviewReassigner
+	events         *eventPublisher
 }
 
 func NewTeamService(
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
 	prRepo repository.PullRequestRepository,
+	settingsRepo repository.TeamSettingsRepository,
+	queueRepo repository.ReviewQueueRepository,
+	fallbackRepo repository.TeamFallbackRepository,
+	codeOwnersRepo repository.CodeOwnersRepository,
+	ruleRepo repository.AssignmentRuleRepository,
+	outboxRepo repository.OutboxRepository,
+	historyRepo repository.AssignmentEventRepository,
 	txMgr repository.TransactionManager,
 ) *TeamService {
+	events := &eventPublisher{outboxRepo: outboxRepo, historyRepo: historyRepo}
 	return &TeamService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
-		prRepo:   prRepo,
-		txMgr:    txMgr,
+		teamRepo:       teamRepo,
+		userRepo:       userRepo,
+		prRepo:         prRepo,
+		settingsRepo:   settingsRepo,
+		queueRepo:      queueRepo,
+		fallbackRepo:   fallbackRepo,
+		codeOwnersRepo: codeOwnersRepo,
+		ruleRepo:       ruleRepo,
+		txMgr:          txMgr,
+		reassigner: &reviewReassigner{
+			prRepo:       prRepo,
+			userRepo:     userRepo,
+			settingsRepo: settingsRepo,
+			queueRepo:    queueRepo,
+			events:       events,
+		},
+		events: events,
 	}
 }
 
@@ -39,133 +68,481 @@ func (s *TeamService) CreateTeam(ctx context.Context, team *domain.Team) error {
 		return domain.NewAppError(domain.ErrCodeTeamExists, "team_name already exists")
 	}
 
-	tx, err := s.txMgr.BeginTx(ctx)
-	if err != nil {
-		return err
+	return s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		if err := s.teamRepo.Create(ctx, team); err != nil {
+			return err
+		}
+
+		if team.ReviewerStrategy != "" {
+			settings := domain.DefaultTeamSettings(team.Name)
+			settings.ReviewerStrategy = team.ReviewerStrategy
+			if err := s.settingsRepo.Upsert(ctx, &settings); err != nil {
+				return err
+			}
+		}
+
+		return s.addMembers(ctx, team.Name, team.Members)
+	})
+}
+
+// AddMembers добавляет в команду новых пользователей или исключённых ранее из своих команд.
+// Участника другой команды переводит только TransferUser.
+func (s *TeamService) AddMembers(ctx context.Context, teamName string, members []domain.TeamMember) (*domain.Team, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	if err := s.teamRepo.Create(ctx, tx, team.Name); err != nil {
-		return err
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		return s.addMembers(ctx, teamName, members)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	for _, member := range team.Members {
+	return s.teamRepo.Get(ctx, teamName)
+}
+
+func (s *TeamService) addMembers(ctx context.Context, teamName string, members []domain.TeamMember) error {
+	for _, member := range members {
 		user := &domain.User{
 			ID:       member.UserID,
 			Username: member.Username,
-			TeamName: team.Name,
+			TeamName: teamName,
 			IsActive: member.IsActive,
 		}
-		if err := s.userRepo.Create(ctx, tx, user); err != nil {
+		added, err := s.userRepo.AddToTeam(ctx, user)
+		if err != nil {
 			return err
 		}
-	}
+		if added {
+			continue
+		}
 
-	return tx.Commit()
+		existing, err := s.userRepo.Get(ctx, member.UserID)
+		if err != nil {
+			return err
+		}
+		if existing.TeamName == teamName {
+			return domain.NewAppError(domain.ErrCodeUserInTeam, fmt.Sprintf("user %s is already a member of team %s", member.UserID, teamName))
+		}
+		return domain.NewAppError(domain.ErrCodeUserInTeam, fmt.Sprintf("user %s belongs to team %s; transfer the user instead", member.UserID, existing.TeamName))
+	}
+	return nil
 }
 
-func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
-	return s.teamRepo.Get(ctx, teamName)
-}
+// RemoveMembers исключает пользователей из команды и переназначает их открытые ревью, как при деактивации,
+// и так же в SERIALIZABLE. Пользователи остаются в базе без команды, чтобы не терять историю PR.
+func (s *TeamService) RemoveMembers(ctx context.Context, teamName string, userIDs []string) (*domain.Team, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
 
-func (s *TeamService) MassDeactivateUsers(ctx context.Context, teamName string, userIDs []string) error {
-	tx, err := s.txMgr.BeginTx(ctx)
+	err := s.txMgr.WithinTx(ctx, repository.TxSerializable, func(ctx context.Context) error {
+		for _, userID := range userIDs {
+			user, err := s.userRepo.GetForUpdate(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if user.TeamName != teamName {
+				return domain.NewAppError(domain.ErrCodeNotFound, fmt.Sprintf("user %s is not a member of team %s", userID, teamName))
+			}
+		}
+
+		if err := s.userRepo.RemoveFromTeam(ctx, userIDs); err != nil {
+			return err
+		}
+
+		assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, userIDs)
+		if err != nil {
+			return err
+		}
+
+		return s.reassigner.reassignAway(ctx, teamName, changeReasonRemoved, assignments)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer tx.Rollback()
 
-	if err := s.userRepo.DeactivateMany(ctx, tx, userIDs); err != nil {
-		return err
+	return s.teamRepo.Get(ctx, teamName)
+}
+
+// TransferUser переводит пользователя в команду teamName. Его открытые ревью переназначаются
+// внутри прежней команды, как при деактивации (в SERIALIZABLE); авторство PR не меняется.
+func (s *TeamService) TransferUser(ctx context.Context, userID, teamName string) (*domain.User, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
 	}
 
-	assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, tx, userIDs)
+	var user *domain.User
+	err := s.txMgr.WithinTx(ctx, repository.TxSerializable, func(ctx context.Context) error {
+		var err error
+		user, err = s.userRepo.GetForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.TeamName == teamName {
+			return domain.NewAppError(domain.ErrCodeUserInTeam, fmt.Sprintf("user %s is already a member of team %s", userID, teamName))
+		}
+
+		if err := s.userRepo.SetTeam(ctx, userID, teamName); err != nil {
+			return err
+		}
+
+		assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, []string{userID})
+		if err != nil {
+			return err
+		}
+
+		return s.reassigner.reassignAway(ctx, user.TeamName, changeReasonTransfer, assignments)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if len(assignments) == 0 {
-		return tx.Commit()
+	user.TeamName = teamName
+	return user, nil
+}
+
+// GetTeam возвращает участников команды и её стратегию выбора ревьюверов
+func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*domain.Team, error) {
+	team, err := s.teamRepo.Get(ctx, teamName)
+	if err != nil {
+		return nil, err
 	}
 
-	activeUsers, err := s.userRepo.GetActiveUsersByTeam(ctx, tx, teamName)
+	settings, err := s.settingsRepo.Get(ctx, teamName)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	team.ReviewerStrategy = settings.ReviewerStrategy
+
+	return team, nil
+}
 
-	prIDsSet := make(map[string]struct{})
-	for _, a := range assignments {
-		prIDsSet[a.PullRequestID] = struct{}{}
+// RenameTeam переименовывает команду; ссылки на неё обновляются в той же транзакции
+func (s *TeamService) RenameTeam(ctx context.Context, oldName, newName string) (*domain.Team, error) {
+	if err := s.ensureTeamExists(ctx, oldName); err != nil {
+		return nil, err
 	}
-	uniquePRIDs := make([]string, 0, len(prIDsSet))
-	for id := range prIDsSet {
-		uniquePRIDs = append(uniquePRIDs, id)
+	exists, err := s.teamRepo.Exists(ctx, newName)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, domain.NewAppError(domain.ErrCodeTeamExists, "team_name already exists")
 	}
 
-	currentReviewersMap, err := s.prRepo.GetReviewersByPRs(ctx, tx, uniquePRIDs)
+	err = s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		return s.teamRepo.Rename(ctx, oldName, newName)
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	replacements := []domain.ReviewReplacement{}
-	removals := []domain.ReviewAssignment{}
+	return s.teamRepo.Get(ctx, newName)
+}
 
-	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+// DeleteTeam удаляет команду. Черновики и открытые PR команды закрываются (openPRs = close) или
+// передаются команде reassignTo с заменой её ревьюверов (openPRs = reassign); без выбора удаление
+// возможно, только если таких PR нет. Участники исключаются из команды, как в RemoveMembers.
+// Команду, из которой правила назначения других команд добирают ревьюверов, удалить нельзя,
+// пока эти правила не изменены. Возвращает id закрытых или переданных PR.
+func (s *TeamService) DeleteTeam(ctx context.Context, teamName, openPRs, reassignTo string) ([]string, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+	dependents, err := s.ruleRepo.ListByExtraTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if len(dependents) > 0 {
+		names := make([]string, len(dependents))
+		for i, rule := range dependents {
+			names[i] = rule.TeamName + "/" + rule.Name
+		}
+		return nil, domain.NewAppError(domain.ErrCodeInvalidState,
+			fmt.Sprintf("team %s is the extra team of assignment rules %s; change those rules first", teamName, strings.Join(names, ", ")))
+	}
+	if openPRs == domain.OpenPRsReassign {
+		if err := s.ensureTeamExists(ctx, reassignTo); err != nil {
+			return nil, err
+		}
+	}
 
-	for _, assignment := range assignments {
-		prID := assignment.PullRequestID
-		currentReviewers := currentReviewersMap[prID]
+	var prIDs []string
+	err = s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		prs, err := s.prRepo.ListUnfinishedByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
 
-		var candidate *domain.User
+		members, err := s.userRepo.GetByTeam(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		memberIDs := make([]string, len(members))
+		isMember := make(map[string]bool, len(members))
+		for i, m := range members {
+			memberIDs[i] = m.ID
+			isMember[m.ID] = true
+		}
 
-		if len(activeUsers) > 0 {
-			startIdx := rng.Intn(len(activeUsers))
-			for i := 0; i < len(activeUsers); i++ {
-				idx := (startIdx + i) % len(activeUsers)
-				u := activeUsers[idx]
+		prIDs = make([]string, len(prs))
+		for i := range prs {
+			prIDs[i] = prs[i].ID
+		}
 
-				// Constraints
-				if u.ID == assignment.AuthorID {
-					continue
+		switch {
+		case len(prs) == 0:
+		case openPRs == domain.OpenPRsClose:
+			for i := range prs {
+				if err := s.closeForDeletion(ctx, &prs[i]); err != nil {
+					return err
 				}
-				alreadyReviewer := false
-				for _, rID := range currentReviewers {
-					if rID == u.ID {
-						alreadyReviewer = true
-						break
-					}
+			}
+		case openPRs == domain.OpenPRsReassign:
+			if err := s.prRepo.SetReviewTeam(ctx, prIDs, reassignTo); err != nil {
+				return err
+			}
+			assignments := []domain.ReviewAssignment{}
+			for _, pr := range prs {
+				// Отложенные места переходят в очередь новой команды; её разберёт фоновая обработка очередей
+				queued, err := s.queueRepo.CountByPR(ctx, pr.ID)
+				if err != nil {
+					return err
 				}
-				if alreadyReviewer {
-					continue
+				if err := s.queueRepo.DeleteByPR(ctx, pr.ID); err != nil {
+					return err
+				}
+				if err := s.queueRepo.Enqueue(ctx, pr.ID, reassignTo, queued); err != nil {
+					return err
+				}
+				for _, reviewerID := range pr.AssignedReviewers {
+					if isMember[reviewerID] {
+						assignments = append(assignments, domain.ReviewAssignment{PullRequestID: pr.ID, ReviewerID: reviewerID, AuthorID: pr.AuthorID})
+					}
 				}
-
-				candidate = &u
-				break
 			}
+			if err := s.reassigner.reassignAway(ctx, reassignTo, changeReasonTeamDeleted, assignments); err != nil {
+				return err
+			}
+		default:
+			return domain.NewAppError(domain.ErrCodeInvalidState,
+				fmt.Sprintf("team %s has %d draft or open pull requests; set open_prs to close or reassign", teamName, len(prs)))
 		}
 
-		if candidate != nil {
-			replacements = append(replacements, domain.ReviewReplacement{
-				PullRequestID: prID,
-				OldUserID:     assignment.ReviewerID,
-				NewUserID:     candidate.ID,
-			})
-			currentReviewersMap[prID] = append(currentReviewersMap[prID], candidate.ID)
-		} else {
-			removals = append(removals, assignment)
+		// Оставшиеся ревью участников - PR других команд; замены в удаляемой команде нет, ревьюверы снимаются
+		if len(memberIDs) > 0 {
+			if err := s.userRepo.RemoveFromTeam(ctx, memberIDs); err != nil {
+				return err
+			}
+			assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, memberIDs)
+			if err != nil {
+				return err
+			}
+			if err := s.reassigner.reassignAway(ctx, teamName, changeReasonTeamDeleted, assignments); err != nil {
+				return err
+			}
 		}
+
+		return s.teamRepo.Delete(ctx, teamName)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if len(replacements) > 0 {
-		if err := s.prRepo.ReplaceReviewersBulk(ctx, tx, replacements); err != nil {
+	return prIDs, nil
+}
+
+// closeForDeletion закрывает PR удаляемой команды и снимает его ревьюверов.
+// Очередь команды не разбирается: она удаляется вместе с командой.
+func (s *TeamService) closeForDeletion(ctx context.Context, pr *domain.PullRequest) error {
+	if err := pr.Apply(domain.PRActionClose, time.Now()); err != nil {
+		return err
+	}
+	if err := s.prRepo.Update(ctx, pr); err != nil {
+		return err
+	}
+
+	released := pr.AssignedReviewers
+	removals := make([]domain.ReviewAssignment, len(released))
+	for i, reviewerID := range released {
+		removals[i] = domain.ReviewAssignment{PullRequestID: pr.ID, ReviewerID: reviewerID, AuthorID: pr.AuthorID}
+	}
+	if len(removals) > 0 {
+		if err := s.prRepo.RemoveReviewersBulk(ctx, removals); err != nil {
 			return err
 		}
 	}
-	if len(removals) > 0 {
-		if err := s.prRepo.RemoveReviewersBulk(ctx, tx, removals); err != nil {
+	if err := s.events.reviewersReleased(ctx, pr.ID, released, changeReasonTeamDeleted); err != nil {
+		return err
+	}
+	if err := s.queueRepo.DeleteByPR(ctx, pr.ID); err != nil {
+		return err
+	}
+	pr.AssignedReviewers = []string{}
+
+	return s.events.prEvent(ctx, domain.EventPRClosed, pr, released)
+}
+
+func (s *TeamService) GetSettings(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	return s.settingsRepo.Get(ctx, teamName)
+}
+
+// UpdateSettings применяет update к текущим настройкам команды и сохраняет результат
+func (s *TeamService) UpdateSettings(ctx context.Context, teamName string, update func(*domain.TeamSettings)) (*domain.TeamSettings, error) {
+	var settings *domain.TeamSettings
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		var err error
+		settings, err = s.settingsRepo.Get(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		update(settings)
+		if err := settings.Validate(); err != nil {
 			return err
 		}
+
+		return s.settingsRepo.Upsert(ctx, settings)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (s *TeamService) GetFallbacks(ctx context.Context, teamName string) ([]string, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	return s.fallbackRepo.Get(ctx, teamName)
+}
+
+// SetFallbacks заменяет запасные команды ревьюверов; порядок в fallbackTeams задаёт приоритет
+func (s *TeamService) SetFallbacks(ctx context.Context, teamName string, fallbackTeams []string) ([]string, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+	for _, fallbackTeam := range fallbackTeams {
+		if err := s.ensureTeamExists(ctx, fallbackTeam); err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		return s.fallbackRepo.Replace(ctx, teamName, fallbackTeams)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fallbackTeams, nil
+}
+
+// GetCodeOwners возвращает загруженный файл CODEOWNERS команды вместе с разобранными правилами
+func (s *TeamService) GetCodeOwners(ctx context.Context, teamName string) (string, *domain.CodeOwners, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return "", nil, err
+	}
+
+	content, err := s.codeOwnersRepo.Get(ctx, teamName)
+	if err != nil {
+		return "", nil, err
+	}
+	if content == "" {
+		return "", nil, domain.NewAppError(domain.ErrCodeNotFound, "CODEOWNERS not found")
+	}
+
+	owners, err := domain.ParseCodeOwners(content)
+	if err != nil {
+		return "", nil, err
+	}
+	return content, owners, nil
+}
+
+// SetCodeOwners заменяет файл CODEOWNERS команды; файл с ошибками не сохраняется
+func (s *TeamService) SetCodeOwners(ctx context.Context, teamName, content string) (*domain.CodeOwners, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	owners, err := domain.ParseCodeOwners(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.codeOwnersRepo.Upsert(ctx, teamName, content); err != nil {
+		return nil, err
+	}
+	return owners, nil
+}
+
+func (s *TeamService) DeleteCodeOwners(ctx context.Context, teamName string) error {
+	return s.codeOwnersRepo.Delete(ctx, teamName)
+}
+
+func (s *TeamService) GetRules(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	return s.ruleRepo.List(ctx, teamName)
+}
+
+// SetRules заменяет правила назначения команды; порядок в rules задаёт приоритет
+func (s *TeamService) SetRules(ctx context.Context, teamName string, rules []domain.AssignmentRule) ([]domain.AssignmentRule, error) {
+	if err := s.ensureTeamExists(ctx, teamName); err != nil {
+		return nil, err
+	}
+	for i := range rules {
+		rules[i].TeamName = teamName
+		if err := rules[i].Validate(); err != nil {
+			return nil, err
+		}
+		if rules[i].ExtraTeam != "" {
+			if err := s.ensureTeamExists(ctx, rules[i].ExtraTeam); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		return s.ruleRepo.Replace(ctx, teamName, rules)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+func (s *TeamService) ensureTeamExists(ctx context.Context, teamName string) error {
+	exists, err := s.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.NewAppError(domain.ErrCodeNotFound, fmt.Sprintf("team %s not found", teamName))
 	}
+	return nil
+}
+
+// MassDeactivateUsers выполняется в SERIALIZABLE: замены подбираются по загрузке ревьюверов,
+// которую параллельные назначения и деактивации не должны менять до фиксации
+func (s *TeamService) MassDeactivateUsers(ctx context.Context, teamName string, userIDs []string) error {
+	return s.txMgr.WithinTx(ctx, repository.TxSerializable, func(ctx context.Context) error {
+		if err := s.userRepo.DeactivateMany(ctx, userIDs); err != nil {
+			return err
+		}
+
+		assignments, err := s.prRepo.GetOpenAssignmentsByReviewers(ctx, userIDs)
+		if err != nil {
+			return err
+		}
 
-	return tx.Commit()
+		return s.reassigner.reassignAway(ctx, teamName, changeReasonDeactivated, assignments)
+	})
 }
@@ -4,14 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 
 	"avito/internal/domain"
+	"avito/internal/repository"
 )
 
 type mockTeamRepo struct {
 	existsFn func(ctx context.Context, teamName string) (bool, error)
-	createFn func(ctx context.Context, tx *sql.Tx, teamName string) error
+	createFn func(ctx context.Context, team *domain.Team) error
 	getFn    func(ctx context.Context, teamName string) (*domain.Team, error)
 }
 
@@ -22,9 +24,17 @@ func (m *mockTeamRepo) Exists(ctx context.Context, teamName string) (bool, error
 	return false, nil
 }
 
-func (m *mockTeamRepo) Create(ctx context.Context, tx *sql.Tx, teamName string) error {
+func (m *mockTeamRepo) Rename(ctx context.Context, oldName, newName string) error {
+	return nil
+}
+
+func (m *mockTeamRepo) Delete(ctx context.Context, teamName string) error {
+	return nil
+}
+
+func (m *mockTeamRepo) Create(ctx context.Context, team *domain.Team) error {
 	if m.createFn != nil {
-		return m.createFn(ctx, tx, teamName)
+		return m.createFn(ctx, team)
 	}
 	return nil
 }
@@ -36,16 +46,27 @@ func (m *mockTeamRepo) Get(ctx context.Context, teamName string) (*domain.Team,
 	return nil, sql.ErrNoRows
 }
 
+type mockTeamSettingsRepo struct{}
+
+func (m *mockTeamSettingsRepo) Get(ctx context.Context, teamName string) (*domain.TeamSettings, error) {
+	settings := domain.DefaultTeamSettings(teamName)
+	return &settings, nil
+}
+
+func (m *mockTeamSettingsRepo) Upsert(ctx context.Context, settings *domain.TeamSettings) error {
+	return nil
+}
+
 type mockUserRepo struct {
-	createFn func(ctx context.Context, tx *sql.Tx, user *domain.User) error
-	getFn    func(ctx context.Context, userID string) (*domain.User, error)
+	addToTeamFn func(ctx context.Context, user *domain.User) (bool, error)
+	getFn       func(ctx context.Context, userID string) (*domain.User, error)
 }
 
-func (m *mockUserRepo) Create(ctx context.Context, tx *sql.Tx, user *domain.User) error {
-	if m.createFn != nil {
-		return m.createFn(ctx, tx, user)
+func (m *mockUserRepo) AddToTeam(ctx context.Context, user *domain.User) (bool, error) {
+	if m.addToTeamFn != nil {
+		return m.addToTeamFn(ctx, user)
 	}
-	return nil
+	return true, nil
 }
 
 func (m *mockUserRepo) Get(ctx context.Context, userID string) (*domain.User, error) {
@@ -63,75 +84,200 @@ func (m *mockUserRepo) GetByTeam(ctx context.Context, teamName string) ([]domain
 	return nil, nil
 }
 
-func (m *mockUserRepo) GetActiveTeammates(ctx context.Context, authorID string, limit int) ([]domain.User, error) {
+func (m *mockUserRepo) GetReviewCandidates(ctx context.Context, teamName string, excludeIDs []string) ([]domain.ReviewCandidate, error) {
+	return nil, nil
+}
+
+func (m *mockUserRepo) SetActive(ctx context.Context, userID string, isActive bool) error {
+	return nil
+}
+
+func (m *mockUserRepo) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) error {
+	return nil
+}
+
+func (m *mockUserRepo) DeactivateMany(ctx context.Context, userIDs []string) error {
+	return nil
+}
+
+func (m *mockUserRepo) GetForUpdate(ctx context.Context, userID string) (*domain.User, error) {
+	return m.Get(ctx, userID)
+}
+
+func (m *mockUserRepo) SetUsername(ctx context.Context, userID, username string) error {
+	return nil
+}
+
+func (m *mockUserRepo) SetTeam(ctx context.Context, userID, teamName string) error {
+	return nil
+}
+
+func (m *mockUserRepo) RemoveFromTeam(ctx context.Context, userIDs []string) error {
+	return nil
+}
+
+type mockReviewQueueRepo struct{}
+
+func (m *mockReviewQueueRepo) Enqueue(ctx context.Context, prID, teamName string, slots int) error {
+	return nil
+}
+func (m *mockReviewQueueRepo) ListByTeam(ctx context.Context, teamName string) ([]domain.QueuedReview, error) {
+	return nil, nil
+}
+func (m *mockReviewQueueRepo) Delete(ctx context.Context, id int64) error { return nil }
+func (m *mockReviewQueueRepo) DeleteByPR(ctx context.Context, prID string) error {
+	return nil
+}
+func (m *mockReviewQueueRepo) CountByPR(ctx context.Context, prID string) (int, error) {
+	return 0, nil
+}
+func (m *mockReviewQueueRepo) GetQueuedTeams(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+type mockTeamFallbackRepo struct{}
+
+func (m *mockTeamFallbackRepo) Get(ctx context.Context, teamName string) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockTeamFallbackRepo) Replace(ctx context.Context, teamName string, fallbackTeams []string) error {
+	return nil
+}
+
+type mockCodeOwnersRepo struct{}
+
+func (m *mockCodeOwnersRepo) Get(ctx context.Context, teamName string) (string, error) {
+	return "", nil
+}
+
+func (m *mockCodeOwnersRepo) Upsert(ctx context.Context, teamName, content string) error {
+	return nil
+}
+
+func (m *mockCodeOwnersRepo) Delete(ctx context.Context, teamName string) error {
+	return nil
+}
+
+type mockAssignmentRuleRepo struct {
+	listByExtraTeamFn func(ctx context.Context, teamName string) ([]domain.AssignmentRule, error)
+}
+
+func (m *mockAssignmentRuleRepo) List(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
 	return nil, nil
 }
 
-func (m *mockUserRepo) FindReplacementReviewer(ctx context.Context, tx *sql.Tx, teamName string, excludeIDs []string) (*domain.User, error) {
+func (m *mockAssignmentRuleRepo) ListByExtraTeam(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
+	if m.listByExtraTeamFn != nil {
+		return m.listByExtraTeamFn(ctx, teamName)
+	}
 	return nil, nil
 }
 
-func (m *mockUserRepo) SetActive(ctx context.Context, userID string, isActive bool) error {
+func (m *mockAssignmentRuleRepo) Replace(ctx context.Context, teamName string, rules []domain.AssignmentRule) error {
+	return nil
+}
+
+func (m *mockAssignmentRuleRepo) RecordFirings(ctx context.Context, firings []domain.RuleFiring) error {
 	return nil
 }
 
-func (m *mockUserRepo) DeactivateMany(ctx context.Context, tx *sql.Tx, userIDs []string) error {
+func (m *mockAssignmentRuleRepo) ListFirings(ctx context.Context, prID string) ([]domain.RuleFiring, error) {
+	return nil, nil
+}
+
+type mockAssignmentEventRepo struct{}
+
+func (m *mockAssignmentEventRepo) Add(ctx context.Context, events []domain.AssignmentEvent) error {
+	return nil
+}
+
+func (m *mockAssignmentEventRepo) ListByPR(ctx context.Context, prID string) ([]domain.AssignmentEvent, error) {
+	return nil, nil
+}
+
+type mockOutboxRepo struct{}
+
+func (m *mockOutboxRepo) Add(ctx context.Context, eventType string, payload []byte) error {
 	return nil
 }
 
-func (m *mockUserRepo) GetActiveUsersByTeam(ctx context.Context, tx *sql.Tx, teamName string) ([]domain.User, error) {
+func (m *mockOutboxRepo) ClaimUndispatched(ctx context.Context, limit int) ([]domain.OutboxEvent, error) {
 	return nil, nil
 }
 
+func (m *mockOutboxRepo) MarkDispatched(ctx context.Context, eventIDs []int64) error {
+	return nil
+}
+
 type mockTxManager struct {
-	beginFn func(ctx context.Context) (*sql.Tx, error)
+	withinFn func(ctx context.Context, opts repository.TxOptions, fn func(ctx context.Context) error) error
 }
 
-func (m *mockTxManager) BeginTx(ctx context.Context) (*sql.Tx, error) {
-	if m.beginFn != nil {
-		return m.beginFn(ctx)
+func (m *mockTxManager) WithinTx(ctx context.Context, opts repository.TxOptions, fn func(ctx context.Context) error) error {
+	if m.withinFn != nil {
+		return m.withinFn(ctx, opts, fn)
 	}
 
-	return &sql.Tx{}, nil
+	return fn(ctx)
 }
 
 type mockPRRepo struct{}
 
-func (m *mockPRRepo) Create(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error {
+func (m *mockPRRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
 	return nil
 }
+func (m *mockPRRepo) List(ctx context.Context, query domain.PRListQuery) ([]domain.PullRequest, error) {
+	return nil, nil
+}
+
 func (m *mockPRRepo) Get(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) GetForUpdate(ctx context.Context, tx *sql.Tx, prID string) (*domain.PullRequest, error) {
+func (m *mockPRRepo) GetForUpdate(ctx context.Context, prID string) (*domain.PullRequest, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) Update(ctx context.Context, tx *sql.Tx, pr *domain.PullRequest) error {
+func (m *mockPRRepo) Update(ctx context.Context, pr *domain.PullRequest) error {
 	return nil
 }
+func (m *mockPRRepo) GetVersion(ctx context.Context, prID string) (int64, error) {
+	return 0, nil
+}
 func (m *mockPRRepo) Exists(ctx context.Context, prID string) (bool, error) { return false, nil }
-func (m *mockPRRepo) GetByReviewer(ctx context.Context, userID string) ([]domain.PullRequestShort, error) {
+func (m *mockPRRepo) GetByReviewer(ctx context.Context, query domain.ReviewQuery) ([]domain.PullRequestShort, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) AddReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error {
+
+func (m *mockPRRepo) CountByReviewer(ctx context.Context, userID, status string) (int, error) {
+	return 0, nil
+}
+func (m *mockPRRepo) AddReviewer(ctx context.Context, prID, userID string) error {
 	return nil
 }
-func (m *mockPRRepo) RemoveReviewer(ctx context.Context, tx *sql.Tx, prID, userID string) error {
+func (m *mockPRRepo) RemoveReviewer(ctx context.Context, prID, userID string) error {
 	return nil
 }
 func (m *mockPRRepo) GetReviewers(ctx context.Context, prID string) ([]string, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) GetOpenAssignmentsByReviewers(ctx context.Context, tx *sql.Tx, reviewerIDs []string) ([]domain.ReviewAssignment, error) {
+func (m *mockPRRepo) GetOpenAssignmentsByReviewers(ctx context.Context, reviewerIDs []string) ([]domain.ReviewAssignment, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) ReplaceReviewersBulk(ctx context.Context, tx *sql.Tx, replacements []domain.ReviewReplacement) error {
+func (m *mockPRRepo) ReplaceReviewersBulk(ctx context.Context, replacements []domain.ReviewReplacement) error {
 	return nil
 }
-func (m *mockPRRepo) GetReviewersByPRs(ctx context.Context, tx *sql.Tx, prIDs []string) (map[string][]string, error) {
+func (m *mockPRRepo) GetReviewersByPRs(ctx context.Context, prIDs []string) (map[string][]string, error) {
 	return nil, nil
 }
-func (m *mockPRRepo) RemoveReviewersBulk(ctx context.Context, tx *sql.Tx, assignments []domain.ReviewAssignment) error {
+func (m *mockPRRepo) RemoveReviewersBulk(ctx context.Context, assignments []domain.ReviewAssignment) error {
+	return nil
+}
+
+func (m *mockPRRepo) ListUnfinishedByTeam(ctx context.Context, teamName string) ([]domain.PullRequest, error) {
+	return []domain.PullRequest{}, nil
+}
+
+func (m *mockPRRepo) SetReviewTeam(ctx context.Context, prIDs []string, teamName string) error {
 	return nil
 }
 
@@ -148,7 +294,7 @@ func TestTeamService_CreateTeam(t *testing.T) {
 		prRepo := &mockPRRepo{}
 		txMgr := &mockTxManager{}
 
-		service := NewTeamService(teamRepo, userRepo, prRepo, txMgr)
+		service := NewTeamService(teamRepo, userRepo, prRepo, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
 
 		team := &domain.Team{
 			Name: "backend",
@@ -180,7 +326,7 @@ func TestTeamService_CreateTeam(t *testing.T) {
 		prRepo := &mockPRRepo{}
 		txMgr := &mockTxManager{}
 
-		service := NewTeamService(teamRepo, userRepo, prRepo, txMgr)
+		service := NewTeamService(teamRepo, userRepo, prRepo, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
 
 		team := &domain.Team{
 			Name: "backend",
@@ -197,6 +343,56 @@ func TestTeamService_CreateTeam(t *testing.T) {
 	})
 }
 
+func TestTeamService_Membership_UnknownTeam(t *testing.T) {
+	ctx := context.Background()
+	teamRepo := &mockTeamRepo{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return false, nil
+		},
+	}
+	service := NewTeamService(teamRepo, &mockUserRepo{}, &mockPRRepo{}, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, &mockTxManager{})
+
+	_, addErr := service.AddMembers(ctx, "ghost", []domain.TeamMember{{UserID: "u1", Username: "Alice", IsActive: true}})
+	_, removeErr := service.RemoveMembers(ctx, "ghost", []string{"u1"})
+	_, transferErr := service.TransferUser(ctx, "u1", "ghost")
+
+	for _, err := range []error{addErr, removeErr, transferErr} {
+		appErr, ok := err.(*domain.AppError)
+		if !ok || appErr.Code != domain.ErrCodeNotFound {
+			t.Errorf("Expected NOT_FOUND error, got %v", err)
+		}
+	}
+}
+
+func TestTeamService_AddMembers_UserInOtherTeam(t *testing.T) {
+	ctx := context.Background()
+	teamRepo := &mockTeamRepo{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) {
+			return true, nil
+		},
+	}
+	// Пользователя успела добавить в другую команду параллельная транзакция
+	userRepo := &mockUserRepo{
+		addToTeamFn: func(ctx context.Context, user *domain.User) (bool, error) {
+			return false, nil
+		},
+		getFn: func(ctx context.Context, userID string) (*domain.User, error) {
+			return &domain.User{ID: userID, Username: "Alice", TeamName: "frontend", IsActive: true}, nil
+		},
+	}
+	service := NewTeamService(teamRepo, userRepo, &mockPRRepo{}, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, &mockTxManager{})
+
+	_, err := service.AddMembers(ctx, "backend", []domain.TeamMember{{UserID: "u1", Username: "Alice", IsActive: true}})
+
+	appErr, ok := err.(*domain.AppError)
+	if !ok || appErr.Code != domain.ErrCodeUserInTeam {
+		t.Fatalf("Expected USER_IN_TEAM error, got %v", err)
+	}
+	if !strings.Contains(appErr.Message, "frontend") {
+		t.Errorf("Expected message to name the current team, got %q", appErr.Message)
+	}
+}
+
 func TestTeamService_GetTeam(t *testing.T) {
 	ctx := context.Background()
 
@@ -217,7 +413,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 		prRepo := &mockPRRepo{}
 		txMgr := &mockTxManager{}
 
-		service := NewTeamService(teamRepo, userRepo, prRepo, txMgr)
+		service := NewTeamService(teamRepo, userRepo, prRepo, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
 		team, err := service.GetTeam(ctx, "backend")
 
 		if err != nil {
@@ -238,7 +434,7 @@ func TestTeamService_GetTeam(t *testing.T) {
 		prRepo := &mockPRRepo{}
 		txMgr := &mockTxManager{}
 
-		service := NewTeamService(teamRepo, userRepo, prRepo, txMgr)
+		service := NewTeamService(teamRepo, userRepo, prRepo, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
 		_, err := service.GetTeam(ctx, "nonexistent")
 
 		if err == nil {
@@ -251,3 +447,99 @@ func TestTeamService_GetTeam(t *testing.T) {
 		}
 	})
 }
+
+func TestTeamService_MassDeactivateUsers_Serializable(t *testing.T) {
+	ctx := context.Background()
+	conflict := domain.NewAppError(domain.ErrCodeConcurrentUpdate, "concurrent update, retry the request")
+
+	var opts repository.TxOptions
+	txMgr := &mockTxManager{
+		withinFn: func(ctx context.Context, o repository.TxOptions, fn func(ctx context.Context) error) error {
+			opts = o
+			return conflict
+		},
+	}
+
+	service := NewTeamService(&mockTeamRepo{}, &mockUserRepo{}, &mockPRRepo{}, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
+	err := service.MassDeactivateUsers(ctx, "backend", []string{"u1"})
+
+	if err != conflict {
+		t.Errorf("Expected transaction error, got %v", err)
+	}
+	if !opts.Serializable {
+		t.Error("Expected SERIALIZABLE transaction")
+	}
+}
+
+func TestTeamService_MembershipChanges_Serializable(t *testing.T) {
+	ctx := context.Background()
+	conflict := domain.NewAppError(domain.ErrCodeConcurrentUpdate, "concurrent update, retry the request")
+
+	var opts repository.TxOptions
+	txMgr := &mockTxManager{
+		withinFn: func(ctx context.Context, o repository.TxOptions, fn func(ctx context.Context) error) error {
+			opts = o
+			return conflict
+		},
+	}
+	teamRepo := &mockTeamRepo{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) { return true, nil },
+	}
+	service := NewTeamService(teamRepo, &mockUserRepo{}, &mockPRRepo{}, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, &mockAssignmentRuleRepo{}, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
+
+	calls := map[string]func() error{
+		"RemoveMembers": func() error {
+			_, err := service.RemoveMembers(ctx, "backend", []string{"u1"})
+			return err
+		},
+		"TransferUser": func() error {
+			_, err := service.TransferUser(ctx, "u1", "frontend")
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			opts = repository.TxOptions{}
+			if err := call(); err != conflict {
+				t.Errorf("Expected transaction error, got %v", err)
+			}
+			if !opts.Serializable {
+				t.Error("Expected SERIALIZABLE transaction")
+			}
+		})
+	}
+}
+
+func TestTeamService_DeleteTeam_ReferencedByRules(t *testing.T) {
+	ctx := context.Background()
+	teamRepo := &mockTeamRepo{
+		existsFn: func(ctx context.Context, teamName string) (bool, error) { return true, nil },
+	}
+	ruleRepo := &mockAssignmentRuleRepo{
+		listByExtraTeamFn: func(ctx context.Context, teamName string) ([]domain.AssignmentRule, error) {
+			return []domain.AssignmentRule{{TeamName: "backend", Name: "security", ExtraTeam: teamName}}, nil
+		},
+	}
+	txCalled := false
+	txMgr := &mockTxManager{
+		withinFn: func(ctx context.Context, o repository.TxOptions, fn func(ctx context.Context) error) error {
+			txCalled = true
+			return fn(ctx)
+		},
+	}
+	service := NewTeamService(teamRepo, &mockUserRepo{}, &mockPRRepo{}, &mockTeamSettingsRepo{}, &mockReviewQueueRepo{}, &mockTeamFallbackRepo{}, &mockCodeOwnersRepo{}, ruleRepo, &mockOutboxRepo{}, &mockAssignmentEventRepo{}, txMgr)
+
+	_, err := service.DeleteTeam(ctx, "appsec", "", "")
+
+	appErr, ok := err.(*domain.AppError)
+	if !ok || appErr.Code != domain.ErrCodeInvalidState {
+		t.Fatalf("Expected INVALID_STATE error, got %v", err)
+	}
+	if !strings.Contains(appErr.Message, "backend/security") {
+		t.Errorf("Expected dependent rule in message, got %q", appErr.Message)
+	}
+	if txCalled {
+		t.Error("Expected team not to be deleted")
+	}
+}
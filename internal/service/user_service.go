@@ -9,11 +9,13 @@ import (
 
 type UserService struct {
 	userRepo repository.UserRepository
+	txMgr    repository.TransactionManager
 }
 
-func NewUserService(userRepo repository.UserRepository) *UserService {
+func NewUserService(userRepo repository.UserRepository, txMgr repository.TransactionManager) *UserService {
 	return &UserService{
 		userRepo: userRepo,
+		txMgr:    txMgr,
 	}
 }
 
@@ -35,12 +37,57 @@ func (s *UserService) SetActive(ctx context.Context, userID string, isActive boo
 	return user, nil
 }
 
-func (s *UserService) GetReviewPRs(ctx context.Context, userID string, prRepo repository.PullRequestRepository) ([]domain.PullRequestShort, error) {
-	// Check if user exists
-	_, err := s.userRepo.Get(ctx, userID)
+func (s *UserService) SetUsername(ctx context.Context, userID, username string) (*domain.User, error) {
+	if err := s.userRepo.SetUsername(ctx, userID, username); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.Get(ctx, userID)
+}
+
+// SetMaxOpenReviews задаёт личный лимит открытых ревью, nil - использовать лимит команды
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, limit *int) (*domain.User, error) {
+	if err := s.userRepo.SetMaxOpenReviews(ctx, userID, limit); err != nil {
+		return nil, err
+	}
+
+	return s.userRepo.Get(ctx, userID)
+}
+
+// GetReviewPRs возвращает страницу PR ревьювера и их общее число; страница и total читаются из одного снимка
+func (s *UserService) GetReviewPRs(ctx context.Context, query domain.ReviewQuery, prRepo repository.PullRequestRepository) (*domain.ReviewPage, error) {
+	pageSize := query.Limit
+	if pageSize > 0 {
+		// Лишний PR показывает, что за страницей есть продолжение
+		query.Limit = pageSize + 1
+	}
+
+	var total int
+	var prs []domain.PullRequestShort
+	err := s.txMgr.WithinTx(ctx, repository.TxReadOnly, func(ctx context.Context) error {
+		// Check if user exists
+		if _, err := s.userRepo.Get(ctx, query.ReviewerID); err != nil {
+			return err
+		}
+
+		var err error
+		total, err = prRepo.CountByReviewer(ctx, query.ReviewerID, query.Status)
+		if err != nil {
+			return err
+		}
+
+		prs, err = prRepo.GetByReviewer(ctx, query)
+		return err
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return prRepo.GetByReviewer(ctx, userID)
+	page := &domain.ReviewPage{PullRequests: prs, Total: total}
+	if pageSize > 0 && len(prs) > pageSize {
+		page.PullRequests = prs[:pageSize]
+		last := prs[pageSize-1]
+		page.Next = &domain.PRCursor{SortBy: domain.PRSortCreatedAt, Value: last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
 }
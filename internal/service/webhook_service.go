@@ -0,0 +1,230 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"avito/internal/domain"
+	"avito/internal/repository"
+)
+
+// Заголовки исходящего webhook
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+const (
+	webhookOutboxBatch   = 100
+	webhookDeliveryBatch = 50
+	// webhookDeliveryLease - на сколько откладывается повтор взятой в работу доставки,
+	// чтобы упавший посреди отправки процесс не потерял её
+	webhookDeliveryLease = 5 * time.Minute
+	webhookTimeout       = 10 * time.Second
+
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+type WebhookService struct {
+	webhookRepo repository.WebhookRepository
+	outboxRepo  repository.OutboxRepository
+	txMgr       repository.TransactionManager
+	client      *http.Client
+}
+
+func NewWebhookService(
+	webhookRepo repository.WebhookRepository,
+	outboxRepo repository.OutboxRepository,
+	txMgr repository.TransactionManager,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		outboxRepo:  outboxRepo,
+		txMgr:       txMgr,
+		client:      &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Create сохраняет подписку; если секрет не задан, генерирует его
+func (s *WebhookService) Create(ctx context.Context, sub *domain.WebhookSubscription) error {
+	if sub.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+		sub.Secret = secret
+	}
+	return s.webhookRepo.Create(ctx, sub)
+}
+
+func (s *WebhookService) Get(ctx context.Context, id int64) (*domain.WebhookSubscription, error) {
+	return s.webhookRepo.Get(ctx, id)
+}
+
+func (s *WebhookService) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.webhookRepo.List(ctx)
+}
+
+func (s *WebhookService) Update(ctx context.Context, sub *domain.WebhookSubscription) (*domain.WebhookSubscription, error) {
+	current, err := s.webhookRepo.Get(ctx, sub.ID)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Secret == "" {
+		sub.Secret = current.Secret
+	}
+	sub.CreatedAt = current.CreatedAt
+
+	if err := s.webhookRepo.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) Delete(ctx context.Context, id int64) error {
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+// ListDeliveries возвращает последние доставки подписки, limit <= 0 - значение по умолчанию
+func (s *WebhookService) ListDeliveries(ctx context.Context, subscriptionID int64, limit int) ([]domain.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.Get(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+	return s.webhookRepo.ListDeliveries(ctx, subscriptionID, min(limit, maxDeliveriesLimit))
+}
+
+// Dispatch раскладывает новые события outbox по подпискам и отправляет доставки, время которых пришло
+func (s *WebhookService) Dispatch(ctx context.Context) error {
+	if err := s.fanOut(ctx); err != nil {
+		return err
+	}
+
+	dispatches, err := s.webhookRepo.ClaimDue(ctx, time.Now(), webhookDeliveryLease, webhookDeliveryBatch)
+	if err != nil {
+		return err
+	}
+
+	for i := range dispatches {
+		delivery := &dispatches[i].Delivery
+		statusCode, errMsg := s.send(ctx, &dispatches[i])
+		delivery.RecordAttempt(statusCode, errMsg, time.Now())
+		if err := s.webhookRepo.SaveAttempt(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fanOut создаёт доставки для неразосланных событий и помечает события разосланными
+func (s *WebhookService) fanOut(ctx context.Context) error {
+	return s.txMgr.WithinTx(ctx, repository.TxDefault, func(ctx context.Context) error {
+		events, err := s.outboxRepo.ClaimUndispatched(ctx, webhookOutboxBatch)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		subs, err := s.webhookRepo.ListActive(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		deliveries := []domain.WebhookDelivery{}
+		eventIDs := make([]int64, 0, len(events))
+		for _, event := range events {
+			eventIDs = append(eventIDs, event.ID)
+			for _, sub := range subs {
+				if !sub.Accepts(event.EventType) {
+					continue
+				}
+				deliveries = append(deliveries, domain.WebhookDelivery{
+					SubscriptionID: sub.ID,
+					EventID:        event.ID,
+					EventType:      event.EventType,
+					Status:         domain.DeliveryStatusPending,
+					NextAttemptAt:  now,
+				})
+			}
+		}
+
+		if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+			return err
+		}
+		return s.outboxRepo.MarkDispatched(ctx, eventIDs)
+	})
+}
+
+type webhookBody struct {
+	ID        int64           `json:"id"`
+	Event     string          `json:"event"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// send выполняет одну попытку доставки и возвращает код ответа и текст ошибки
+func (s *WebhookService) send(ctx context.Context, dispatch *domain.WebhookDispatch) (int, string) {
+	body, err := json.Marshal(webhookBody{
+		ID:        dispatch.Event.ID,
+		Event:     dispatch.Event.EventType,
+		CreatedAt: dispatch.Event.CreatedAt,
+		Data:      dispatch.Event.Payload,
+	})
+	if err != nil {
+		return 0, err.Error()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err.Error()
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, dispatch.Event.EventType)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(dispatch.Delivery.ID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(dispatch.Secret, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Sprintf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, ""
+}
+
+// SignWebhookPayload - значение заголовка подписи: sha256=<hex HMAC-SHA256 тела по секрету подписки>
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
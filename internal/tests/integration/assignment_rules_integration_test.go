@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"avito/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setRules(t *testing.T, baseURL, teamName string, rules ...dto.AssignmentRule) *http.Response {
+	return doRequest(t, baseURL, HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/rules/set",
+		Body:   dto.TeamRules{TeamName: teamName, Rules: rules},
+	})
+}
+
+func TestAssignmentRulesIntegration_SetAndGet(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+	createTeamWithUsers(t, env.BaseURL(), "security", "sec1")
+
+	minLines := 1000
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "security", When: dto.AssignmentCondition{Labels: []string{"Security"}}, Then: dto.AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}},
+		dto.AssignmentRule{Name: "large", When: dto.AssignmentCondition{MinLines: &minLines}, Then: dto.AssignmentAction{Reviewers: 3}},
+	)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/rules?team_name=backend"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var rules dto.TeamRules
+	parseJSON(t, resp, &rules)
+	require.Len(t, rules.Rules, 2)
+	assert.Equal(t, "security", rules.Rules[0].Name)
+	assert.Equal(t, []string{"security"}, rules.Rules[0].When.Labels)
+	assert.Equal(t, "large", rules.Rules[1].Name)
+	require.NotNil(t, rules.Rules[1].When.MinLines)
+	assert.Equal(t, 1000, *rules.Rules[1].When.MinLines)
+
+	resp = setRules(t, env.BaseURL(), "backend")
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/rules?team_name=backend"})
+	parseJSON(t, resp, &rules)
+	assert.Empty(t, rules.Rules)
+}
+
+func TestAssignmentRulesIntegration_UnknownExtraTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "security", When: dto.AssignmentCondition{Labels: []string{"security"}}, Then: dto.AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}},
+	)
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestAssignmentRulesIntegration_LabelAddsReviewerFromTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+	createTeamWithUsers(t, env.BaseURL(), "security", "sec1")
+
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "security", When: dto.AssignmentCondition{Labels: []string{"security"}}, Then: dto.AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}},
+	)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	pr := createPRWithMetadata(t, env.BaseURL(), "pr-1", "author", dto.PullRequestMetadata{Labels: []string{"Security"}})
+	require.Len(t, pr.AssignedReviewers, 3)
+	assert.Contains(t, pr.AssignedReviewers, "sec1")
+
+	require.Len(t, pr.AssignmentDecisions, 3)
+	assert.Equal(t, dto.DecisionRuleAssignment, pr.AssignmentDecisions[2].Rule)
+	assert.Equal(t, "security", pr.AssignmentDecisions[2].AssignmentRule)
+	assert.Equal(t, "sec1", pr.AssignmentDecisions[2].UserID)
+
+	require.Len(t, pr.FiredRules, 1)
+	assert.Equal(t, "security", pr.FiredRules[0].Rule)
+	assert.Equal(t, []string{"sec1"}, pr.FiredRules[0].AssignedReviewers)
+
+	pr = createPRWithMetadata(t, env.BaseURL(), "pr-2", "author", dto.PullRequestMetadata{Labels: []string{"bug"}})
+	assert.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, "sec1")
+	assert.Empty(t, pr.FiredRules)
+}
+
+func TestAssignmentRulesIntegration_LargePRGetsMoreReviewers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3", "rev4")
+
+	minLines := 1000
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "large", When: dto.AssignmentCondition{MinLines: &minLines}, Then: dto.AssignmentAction{Reviewers: 3}},
+	)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	pr := createPRWithMetadata(t, env.BaseURL(), "pr-large", "author", dto.PullRequestMetadata{Additions: 900, Deletions: 200})
+	assert.Len(t, pr.AssignedReviewers, 3)
+
+	pr = createPRWithMetadata(t, env.BaseURL(), "pr-small", "author", dto.PullRequestMetadata{Additions: 90})
+	assert.Len(t, pr.AssignedReviewers, 2)
+}
+
+func TestAssignmentRulesIntegration_FiringsRecorded(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+	createTeamWithUsers(t, env.BaseURL(), "security", "sec1")
+
+	minLines := 1000
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "security", When: dto.AssignmentCondition{Labels: []string{"security"}}, Then: dto.AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}},
+		dto.AssignmentRule{Name: "large", When: dto.AssignmentCondition{MinLines: &minLines}, Then: dto.AssignmentAction{Reviewers: 3}},
+		dto.AssignmentRule{Name: "web", When: dto.AssignmentCondition{Repository: "org/web"}, Then: dto.AssignmentAction{Reviewers: 1}},
+	)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	createPRWithMetadata(t, env.BaseURL(), "pr-1", "author", dto.PullRequestMetadata{
+		Repository: "org/api", Labels: []string{"security"}, Additions: 1500,
+	})
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/rules?pull_request_id=pr-1"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var firings struct {
+		PullRequestID string           `json:"pull_request_id"`
+		FiredRules    []dto.RuleFiring `json:"fired_rules"`
+	}
+	parseJSON(t, resp, &firings)
+	assert.Equal(t, "pr-1", firings.PullRequestID)
+	require.Len(t, firings.FiredRules, 2)
+	assert.Equal(t, "security", firings.FiredRules[0].Rule)
+	assert.Equal(t, "create", firings.FiredRules[0].Source)
+	assert.Equal(t, []string{"sec1"}, firings.FiredRules[0].AssignedReviewers)
+	assert.Equal(t, "large", firings.FiredRules[1].Rule)
+	assert.Equal(t, 3, firings.FiredRules[1].Then.Reviewers)
+	assert.Empty(t, firings.FiredRules[1].AssignedReviewers)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/rules?pull_request_id=missing"})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
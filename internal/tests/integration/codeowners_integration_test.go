@@ -0,0 +1,123 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"avito/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uploadCodeOwners(t *testing.T, baseURL, teamName, content string) *http.Response {
+	return doRequest(t, baseURL, HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/codeowners/upload",
+		Body:   dto.TeamCodeOwnersRequest{TeamName: teamName, Content: content},
+	})
+}
+
+func createPRWithFiles(t *testing.T, baseURL, prID, authorID string, files ...string) *dto.PullRequestResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/create",
+		Body:   dto.PullRequestCreateRequest{ID: prID, Name: "Feature", AuthorID: authorID, ChangedFiles: files},
+	})
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	var prResp struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &prResp)
+	return &prResp.PR
+}
+
+func TestCodeOwnersIntegration_UploadAndGet(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice", "bob")
+
+	content := "# owners\n*.go @alice\n/docs/ bob\n"
+	resp := uploadCodeOwners(t, env.BaseURL(), "backend", content)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/codeowners?team_name=backend"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var owners dto.TeamCodeOwnersResponse
+	parseJSON(t, resp, &owners)
+	assert.Equal(t, content, owners.Content)
+	require.Len(t, owners.Rules, 2)
+	assert.Equal(t, dto.CodeOwnersRuleInfo{Line: 2, Pattern: "*.go", Owners: []string{"alice"}}, owners.Rules[0])
+	assert.Equal(t, dto.CodeOwnersRuleInfo{Line: 3, Pattern: "/docs/", Owners: []string{"bob"}}, owners.Rules[1])
+}
+
+func TestCodeOwnersIntegration_UploadInvalidOwner(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+
+	resp := uploadCodeOwners(t, env.BaseURL(), "backend", "*.go @org/team\n")
+	assertErrorCode(t, resp, "INVALID_INPUT")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/codeowners?team_name=backend"})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestCodeOwnersIntegration_OwnersPreferred(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "owner")
+
+	resp := uploadCodeOwners(t, env.BaseURL(), "backend", "/billing/ @owner\n")
+	assertStatusCode(t, resp, http.StatusOK)
+
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3"} {
+		pr := createPRWithFiles(t, env.BaseURL(), prID, "author", "billing/invoice.go", "README.md")
+		require.Len(t, pr.AssignedReviewers, 2)
+		assert.Equal(t, "owner", pr.AssignedReviewers[0])
+		assert.Equal(t, []string{"billing/invoice.go", "README.md"}, pr.ChangedFiles)
+
+		require.Len(t, pr.AssignmentDecisions, 2)
+		assert.Equal(t, dto.DecisionRuleCodeOwners, pr.AssignmentDecisions[0].Rule)
+		require.NotNil(t, pr.AssignmentDecisions[0].CodeOwnersRule)
+		assert.Equal(t, "/billing/", pr.AssignmentDecisions[0].CodeOwnersRule.Pattern)
+		assert.Equal(t, 1, pr.AssignmentDecisions[0].CodeOwnersRule.Line)
+		assert.Equal(t, dto.DecisionRuleStrategy, pr.AssignmentDecisions[1].Rule)
+		assert.Nil(t, pr.AssignmentDecisions[1].CodeOwnersRule)
+	}
+}
+
+func TestCodeOwnersIntegration_InactiveOwnerFallsBackToTeammates(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "owner")
+	setUserActive(t, env.BaseURL(), "owner", false)
+
+	resp := uploadCodeOwners(t, env.BaseURL(), "backend", "*.go @owner\n")
+	assertStatusCode(t, resp, http.StatusOK)
+
+	pr := createPRWithFiles(t, env.BaseURL(), "pr-1", "author", "main.go")
+	assert.Equal(t, []string{"rev1"}, pr.AssignedReviewers)
+	require.Len(t, pr.AssignmentDecisions, 1)
+	assert.Equal(t, dto.DecisionRuleStrategy, pr.AssignmentDecisions[0].Rule)
+}
+
+func TestCodeOwnersIntegration_Delete(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+
+	resp := uploadCodeOwners(t, env.BaseURL(), "backend", "* @alice\n")
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/codeowners/delete",
+		Body:   dto.DeleteCodeOwnersRequest{TeamName: "backend"},
+	})
+	assertStatusCode(t, resp, http.StatusNoContent)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/codeowners/delete",
+		Body:   dto.DeleteCodeOwnersRequest{TeamName: "backend"},
+	})
+	assertErrorCode(t, resp, "NOT_FOUND")
+}
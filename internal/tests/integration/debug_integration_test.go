@@ -0,0 +1,22 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDebugIntegration_VarsRequireAdminToken(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/debug/vars"})
+	assertStatusCode(t, resp, http.StatusForbidden)
+	assertErrorCode(t, resp, "FORBIDDEN")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodGet,
+		Path:    "/debug/vars",
+		Headers: map[string]string{"X-Admin-Token": testAdminToken},
+	})
+	defer resp.Body.Close()
+	assertStatusCode(t, resp, http.StatusOK)
+}
@@ -0,0 +1,145 @@
+package integration
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito/internal/dto"
+	"avito/internal/handlers"
+	"avito/internal/service"
+)
+
+const githubFixturePR = "gh-octo-org-api-server-bb235ab7-42"
+
+func readGitHubFixture(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("..", "..", "dto", "testdata", "github", name))
+	require.NoError(t, err)
+	return data
+}
+
+// sendGitHubEvent отправляет записанный payload так же, как его присылает GitHub
+func sendGitHubEvent(t *testing.T, baseURL, event string, body []byte, secret string) *http.Response {
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/integrations/github/webhook", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.GitHubEventHeader, event)
+	req.Header.Set(handlers.GitHubSignatureHeader, service.SignWebhookPayload(secret, body))
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func sendGitHubFixture(t *testing.T, baseURL, fixture string) dto.IntegrationEventResponse {
+	resp := sendGitHubEvent(t, baseURL, "pull_request", readGitHubFixture(t, fixture), testGitHubSecret)
+	assertStatusCode(t, resp, http.StatusOK)
+	var result dto.IntegrationEventResponse
+	parseJSON(t, resp, &result)
+	return result
+}
+
+func mapGitHubUser(t *testing.T, baseURL, login, userID string) {
+	reqBody := dto.IntegrationUserMappingRequest{Login: login, UserID: userID}
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/integrations/github/users/map", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+}
+
+func TestGitHubIntegration_OpenedAndMerged(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	mapGitHubUser(t, env.BaseURL(), "Octo-Dev", "author")
+
+	opened := sendGitHubFixture(t, env.BaseURL(), "pull_request_opened.json")
+	assert.Equal(t, "processed", opened.Status)
+	require.NotNil(t, opened.PR)
+	assert.Equal(t, githubFixturePR, opened.PR.ID)
+	assert.Equal(t, "author", opened.PR.AuthorID)
+	assert.Equal(t, "Add rate limiting to public endpoints", opened.PR.Name)
+	assert.Equal(t, []string{"rev1"}, opened.PR.AssignedReviewers)
+
+	// Повторная доставка того же события не создаёт ошибку
+	redelivered := sendGitHubFixture(t, env.BaseURL(), "pull_request_opened.json")
+	assert.Equal(t, "ignored", redelivered.Status)
+
+	merged := sendGitHubFixture(t, env.BaseURL(), "pull_request_closed_merged.json")
+	require.NotNil(t, merged.PR)
+	assert.Equal(t, "MERGED", merged.PR.Status)
+}
+
+func TestGitHubIntegration_DraftReadyCloseReopen(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	mapGitHubUser(t, env.BaseURL(), "octo-dev", "author")
+
+	draft := sendGitHubFixture(t, env.BaseURL(), "pull_request_opened_draft.json")
+	require.NotNil(t, draft.PR)
+	assert.Equal(t, "DRAFT", draft.PR.Status)
+	assert.Empty(t, draft.PR.AssignedReviewers)
+
+	ready := sendGitHubFixture(t, env.BaseURL(), "pull_request_ready_for_review.json")
+	require.NotNil(t, ready.PR)
+	assert.Equal(t, "OPEN", ready.PR.Status)
+	assert.Equal(t, []string{"rev1"}, ready.PR.AssignedReviewers)
+
+	closed := sendGitHubFixture(t, env.BaseURL(), "pull_request_closed_unmerged.json")
+	require.NotNil(t, closed.PR)
+	assert.Equal(t, "CLOSED", closed.PR.Status)
+
+	reopened := sendGitHubFixture(t, env.BaseURL(), "pull_request_reopened.json")
+	require.NotNil(t, reopened.PR)
+	assert.Equal(t, "OPEN", reopened.PR.Status)
+
+	labeled := sendGitHubFixture(t, env.BaseURL(), "pull_request_labeled.json")
+	assert.Equal(t, "ignored", labeled.Status)
+	assert.NotEmpty(t, labeled.Reason)
+}
+
+func TestGitHubIntegration_InvalidSignature(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	resp := sendGitHubEvent(t, env.BaseURL(), "pull_request", readGitHubFixture(t, "pull_request_opened.json"), "wrong-secret")
+	assertStatusCode(t, resp, http.StatusUnauthorized)
+	assertErrorCode(t, resp, "UNAUTHORIZED")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/integrations/github/webhook", Body: map[string]string{}})
+	assertStatusCode(t, resp, http.StatusUnauthorized)
+	resp.Body.Close()
+}
+
+func TestGitHubIntegration_PingIgnored(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	resp := sendGitHubEvent(t, env.BaseURL(), "ping", readGitHubFixture(t, "ping.json"), testGitHubSecret)
+	assertStatusCode(t, resp, http.StatusOK)
+	var result dto.IntegrationEventResponse
+	parseJSON(t, resp, &result)
+	assert.Equal(t, "ignored", result.Status)
+}
+
+func TestGitHubIntegration_UnmappedAuthor(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	resp := sendGitHubEvent(t, env.BaseURL(), "pull_request", readGitHubFixture(t, "pull_request_opened.json"), testGitHubSecret)
+	assertStatusCode(t, resp, http.StatusNotFound)
+	assertErrorCode(t, resp, "NOT_FOUND")
+
+	mapGitHubUser(t, env.BaseURL(), "octo-dev", "author")
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/integrations/github/users"})
+	assertStatusCode(t, resp, http.StatusOK)
+	var users handlers.IntegrationUsersResponse
+	parseJSON(t, resp, &users)
+	require.Len(t, users.Users, 1)
+	assert.Equal(t, "octo-dev", users.Users[0].Login)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/integrations/github/users/unmap", Body: dto.DeleteIntegrationUserMappingRequest{Login: "Octo-Dev"}})
+	assertStatusCode(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+}
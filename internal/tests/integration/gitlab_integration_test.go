@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito/internal/dto"
+	"avito/internal/handlers"
+)
+
+const gitlabFixturePR = "gl-Payments-billing-api-a6e84c15-17"
+
+// sendGitLabFixture отправляет записанный Merge Request Hook так же, как его присылает GitLab
+func sendGitLabFixture(t *testing.T, baseURL, fixture, token string) *http.Response {
+	body, err := os.ReadFile(filepath.Join("..", "..", "dto", "testdata", "gitlab", fixture))
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, baseURL+"/integrations/gitlab/webhook", bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.GitLabEventHeader, "Merge Request Hook")
+	req.Header.Set(handlers.GitLabTokenHeader, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	return resp
+}
+
+func applyGitLabFixture(t *testing.T, baseURL, fixture string) dto.IntegrationEventResponse {
+	resp := sendGitLabFixture(t, baseURL, fixture, testGitLabToken)
+	assertStatusCode(t, resp, http.StatusOK)
+	var result dto.IntegrationEventResponse
+	parseJSON(t, resp, &result)
+	return result
+}
+
+func mapGitLabProject(t *testing.T, baseURL, project, teamName string) *http.Response {
+	reqBody := dto.IntegrationProjectMappingRequest{Project: project, TeamName: teamName}
+	return doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/integrations/gitlab/projects/map", Body: reqBody})
+}
+
+func TestGitLabIntegration_ProjectTeamReviewsMR(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "dana", "teammate")
+	createTeamWithUsers(t, env.BaseURL(), "payments", "payer")
+
+	reqBody := dto.IntegrationUserMappingRequest{Login: "d.ivanova", UserID: "dana"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/integrations/gitlab/users/map", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	resp = mapGitLabProject(t, env.BaseURL(), "payments/Billing-API", "payments")
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	opened := applyGitLabFixture(t, env.BaseURL(), "merge_request_open.json")
+	require.NotNil(t, opened.PR)
+	assert.Equal(t, gitlabFixturePR, opened.PR.ID)
+	assert.Equal(t, "dana", opened.PR.AuthorID)
+	assert.Equal(t, "payments", opened.PR.ReviewTeam)
+	assert.Equal(t, []string{"payer"}, opened.PR.AssignedReviewers)
+
+	closed := applyGitLabFixture(t, env.BaseURL(), "merge_request_close.json")
+	require.NotNil(t, closed.PR)
+	assert.Equal(t, "CLOSED", closed.PR.Status)
+
+	reopened := applyGitLabFixture(t, env.BaseURL(), "merge_request_reopen.json")
+	require.NotNil(t, reopened.PR)
+	assert.Equal(t, []string{"payer"}, reopened.PR.AssignedReviewers)
+
+	merged := applyGitLabFixture(t, env.BaseURL(), "merge_request_merge.json")
+	require.NotNil(t, merged.PR)
+	assert.Equal(t, "MERGED", merged.PR.Status)
+}
+
+func TestGitLabIntegration_UnmappedProjectUsesAuthorTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "dana", "teammate")
+
+	reqBody := dto.IntegrationUserMappingRequest{Login: "d.ivanova", UserID: "dana"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/integrations/gitlab/users/map", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	draft := applyGitLabFixture(t, env.BaseURL(), "merge_request_open_draft.json")
+	require.NotNil(t, draft.PR)
+	assert.Equal(t, "DRAFT", draft.PR.Status)
+	assert.Empty(t, draft.PR.ReviewTeam)
+
+	updated := applyGitLabFixture(t, env.BaseURL(), "merge_request_update_title.json")
+	assert.Equal(t, "ignored", updated.Status)
+
+	ready := applyGitLabFixture(t, env.BaseURL(), "merge_request_update_ready.json")
+	require.NotNil(t, ready.PR)
+	assert.Equal(t, []string{"teammate"}, ready.PR.AssignedReviewers)
+}
+
+func TestGitLabIntegration_InvalidToken(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	resp := sendGitLabFixture(t, env.BaseURL(), "merge_request_open.json", "wrong-token")
+	assertStatusCode(t, resp, http.StatusUnauthorized)
+	assertErrorCode(t, resp, "UNAUTHORIZED")
+}
+
+func TestGitLabIntegration_MapProjectUnknownTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	resp := mapGitLabProject(t, env.BaseURL(), "payments/billing-api", "ghost")
+	assertStatusCode(t, resp, http.StatusNotFound)
+	assertErrorCode(t, resp, "NOT_FOUND")
+}
@@ -19,6 +19,8 @@ type HTTPRequest struct {
 	Method string
 	Path   string
 	Body   interface{}
+	// Headers - дополнительные заголовки запроса
+	Headers map[string]string
 }
 
 func doRequest(t *testing.T, baseURL string, req HTTPRequest) *http.Response {
@@ -35,6 +37,9 @@ func doRequest(t *testing.T, baseURL string, req HTTPRequest) *http.Response {
 	if req.Body != nil {
 		httpReq.Header.Set("Content-Type", "application/json")
 	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
 
 	client := &http.Client{}
 	resp, err := client.Do(httpReq)
@@ -196,37 +201,31 @@ func createTeamWithUsers(t *testing.T, baseURL, teamName string, userIDs ...stri
 func addUserToTeam(t *testing.T, baseURL, teamName, userID string, isActive bool) {
 	t.Helper()
 
-	// Get current team
+	reqBody := dto.AddMembersRequest{
+		TeamName: teamName,
+		Members:  []dto.TeamMember{{UserID: userID, Username: fmt.Sprintf("User_%s", userID), IsActive: isActive}},
+	}
+
 	resp := doRequest(t, baseURL, HTTPRequest{
-		Method: http.MethodGet,
-		Path:   "/team/get?team_name=" + teamName,
+		Method: http.MethodPost,
+		Path:   "/team/members/add",
+		Body:   reqBody,
 	})
+
 	assertStatusCode(t, resp, http.StatusOK)
+}
 
-	var currentTeam dto.TeamResponse
-	parseJSON(t, resp, &currentTeam)
+func submitReview(t *testing.T, baseURL, prID, reviewerID, state string) *dto.PullRequestResponse {
+	reqBody := dto.SubmitReviewRequest{PullRequestID: prID, ReviewerID: reviewerID, State: state}
 
-	// Add new member
-	newMember := dto.TeamMember{
-		UserID:   userID,
-		Username: fmt.Sprintf("User_%s", userID),
-		IsActive: isActive,
-	}
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/review", Body: reqBody})
 
-	// Update team with new member (using upsert logic)
-	allMembers := append(currentTeam.Members, newMember)
+	assertStatusCode(t, resp, http.StatusOK)
 
-	reqBody := dto.TeamRequest{
-		Name:    teamName,
-		Members: allMembers,
+	var prResp struct {
+		PR dto.PullRequestResponse `json:"pr"`
 	}
+	parseJSON(t, resp, &prResp)
 
-	resp = doRequest(t, baseURL, HTTPRequest{
-		Method: http.MethodPost,
-		Path:   "/team/add",
-		Body:   reqBody,
-	})
-
-	// Should return 200 (upsert) since team exists
-	assertStatusCode(t, resp, http.StatusOK)
+	return &prResp.PR
 }
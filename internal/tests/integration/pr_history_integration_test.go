@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"avito/internal/dto"
+	"avito/internal/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func getPRDetails(t *testing.T, baseURL, prID string) *handlers.PRDetailsResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/get?pull_request_id=" + prID})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var details handlers.PRDetailsResponse
+	parseJSON(t, resp, &details)
+	return &details
+}
+
+func TestPRHistoryIntegration_GetReturnsPR(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+	created := createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.Equal(t, "pr-1", details.PR.ID)
+	assert.Equal(t, "Feature", details.PR.Name)
+	assert.ElementsMatch(t, created.AssignedReviewers, details.PR.AssignedReviewers)
+	assert.Len(t, details.PR.Reviews, len(created.AssignedReviewers))
+
+	require.Len(t, details.History, 2)
+	for _, event := range details.History {
+		assert.Equal(t, "assigned", event.Event)
+		assert.Equal(t, "create", event.Reason)
+		assert.Contains(t, created.AssignedReviewers, event.ReviewerID)
+	}
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/get?pull_request_id=missing"})
+	assertStatusCode(t, resp, http.StatusNotFound)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/get"})
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func TestPRHistoryIntegration_RecordsEveryChange(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/create",
+		Body:    dto.PullRequestCreateRequest{ID: "pr-1", Name: "Feature", AuthorID: "author"},
+		Headers: map[string]string{handlers.ActorHeader: "author"},
+	})
+	assertStatusCode(t, resp, http.StatusCreated)
+	var created struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &created)
+	require.Len(t, created.PR.AssignedReviewers, 2)
+
+	first := created.PR.AssignedReviewers[0]
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/reassign",
+		Body:    map[string]string{"pull_request_id": "pr-1", "old_user_id": first},
+		Headers: map[string]string{handlers.ActorHeader: "lead"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var reassigned handlers.PRReassignResponse
+	parseJSON(t, resp, &reassigned)
+
+	// Снятый вручную ревьювер снова свободен и заменяет деактивированного
+	second := created.PR.AssignedReviewers[1]
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/users/deactivate",
+		Body:   dto.MassDeactivateRequest{TeamName: "backend", UserIDs: []string{second}},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/close",
+		Body:   map[string]string{"pull_request_id": "pr-1"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	history := getPRDetails(t, env.BaseURL(), "pr-1").History
+	require.Len(t, history, 6)
+
+	assert.Equal(t, dto.AssignmentEvent{Event: "assigned", ReviewerID: first, Reason: "create", Actor: "author", CreatedAt: history[0].CreatedAt}, history[0])
+	assert.Equal(t, "assigned", history[1].Event)
+
+	assert.Equal(t, "reassigned", history[2].Event)
+	assert.Equal(t, first, history[2].PreviousReviewerID)
+	assert.Equal(t, reassigned.ReplacedBy, history[2].ReviewerID)
+	assert.Equal(t, "manual", history[2].Reason)
+	assert.Equal(t, "lead", history[2].Actor)
+
+	assert.Equal(t, "reassigned", history[3].Event)
+	assert.Equal(t, second, history[3].PreviousReviewerID)
+	assert.Equal(t, first, history[3].ReviewerID)
+	assert.Equal(t, "deactivation", history[3].Reason)
+
+	var released []string
+	for _, event := range history[4:] {
+		assert.Equal(t, "removed", event.Event)
+		assert.Equal(t, "close", event.Reason)
+		released = append(released, event.PreviousReviewerID)
+	}
+	assert.ElementsMatch(t, []string{first, reassigned.ReplacedBy}, released)
+
+	for i := 1; i < len(history); i++ {
+		assert.False(t, history[i].CreatedAt.Before(history[i-1].CreatedAt), "history must be chronological")
+	}
+}
@@ -1,11 +1,14 @@
 package integration
 
 import (
+	"fmt"
 	"net/http"
 	"sync"
 	"testing"
 
 	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/handlers"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -182,24 +185,26 @@ func TestPRIntegration_ConcurrentCreateOnlyOneSucceeds(t *testing.T) {
 	env := setupTestEnvironment(t)
 	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
 	var wg sync.WaitGroup
-	errors := make([]error, 10)
+	statuses := make([]int, 10)
 	for i := 0; i < 10; i++ {
 		wg.Add(1)
 		go func(idx int) {
 			defer wg.Done()
 			reqBody := map[string]string{"pull_request_id": "pr-race", "pull_request_name": "Race", "author_id": "author"}
 			resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: reqBody})
-			if resp.StatusCode != http.StatusCreated {
-				errors[idx] = assert.AnError
-			}
+			resp.Body.Close()
+			statuses[idx] = resp.StatusCode
 		}(i)
 	}
 	wg.Wait()
 	successCount := 0
-	for _, err := range errors {
-		if err == nil {
+	for _, status := range statuses {
+		if status == http.StatusCreated {
 			successCount++
+			continue
 		}
+		// Проигравшие гонку получают PR_EXISTS, а не ошибку уникальности из базы
+		assert.Equal(t, http.StatusConflict, status)
 	}
 	assert.Equal(t, 1, successCount, "only one goroutine should create PR successfully")
 }
@@ -221,6 +226,135 @@ func TestPRIntegration_ConcurrentMergeIdempotent(t *testing.T) {
 	assert.Equal(t, "MERGED", pr.Status)
 }
 
+func TestPRIntegration_ConcurrentReassignSameReviewer(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3", "rev4", "rev5")
+	pr := createPR(t, env.BaseURL(), "pr-reassign-race", "Feature", "author")
+	require.NotEmpty(t, pr.AssignedReviewers)
+	oldReviewer := pr.AssignedReviewers[0]
+
+	var wg sync.WaitGroup
+	statuses := make([]int, 5)
+	for i := range statuses {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			reqBody := map[string]string{"pull_request_id": "pr-reassign-race", "old_user_id": oldReviewer}
+			resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/reassign", Body: reqBody})
+			resp.Body.Close()
+			statuses[idx] = resp.StatusCode
+		}(i)
+	}
+	wg.Wait()
+
+	// Конфликты сериализации повторяются внутри сервиса: остальные запросы видят уже снятого ревьювера
+	successCount := 0
+	for _, status := range statuses {
+		if status == http.StatusOK {
+			successCount++
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, status)
+	}
+	assert.Equal(t, 1, successCount)
+
+	details := getPRDetails(t, env.BaseURL(), "pr-reassign-race")
+	assert.NotContains(t, details.PR.AssignedReviewers, oldReviewer)
+	assert.Len(t, details.PR.AssignedReviewers, len(pr.AssignedReviewers))
+}
+
+func TestPRIntegration_VersionPreconditions(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+	pr := createPR(t, env.BaseURL(), "pr-version", "Feature", "author")
+	require.NotEmpty(t, pr.AssignedReviewers)
+	staleVersion := pr.Version
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/get?pull_request_id=pr-version"})
+	assertStatusCode(t, resp, http.StatusOK)
+	assert.Equal(t, fmt.Sprintf(`"%d"`, staleVersion), resp.Header.Get("ETag"))
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/reassign",
+		Body:    map[string]string{"pull_request_id": "pr-version", "old_user_id": pr.AssignedReviewers[0]},
+		Headers: map[string]string{"If-Match": fmt.Sprintf(`"%d"`, staleVersion)},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var reassigned handlers.PRReassignResponse
+	etag := resp.Header.Get("ETag")
+	parseJSON(t, resp, &reassigned)
+	assert.Greater(t, reassigned.PR.Version, staleVersion)
+	assert.Equal(t, fmt.Sprintf(`"%d"`, reassigned.PR.Version), etag)
+
+	// Второй клиент начинал с той же версии и узнаёт, что его представление устарело
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/reassign",
+		Body:    map[string]string{"pull_request_id": "pr-version", "old_user_id": pr.AssignedReviewers[1]},
+		Headers: map[string]string{"If-Match": fmt.Sprintf(`"%d"`, staleVersion)},
+	})
+	assertStatusCode(t, resp, http.StatusPreconditionFailed)
+	assertErrorCode(t, resp, domain.ErrCodeVersionMismatch)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/merge",
+		Body:   dto.MergePRRequest{PullRequestID: "pr-version", ExpectedVersion: staleVersion},
+	})
+	assertStatusCode(t, resp, http.StatusPreconditionFailed)
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/close",
+		Body:    dto.PRTransitionRequest{PullRequestID: "pr-version"},
+		Headers: map[string]string{"If-Match": "not-a-version"},
+	})
+	assertStatusCode(t, resp, http.StatusBadRequest)
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/close",
+		Body:   dto.PRTransitionRequest{PullRequestID: "pr-version", ExpectedVersion: reassigned.PR.Version},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var closed handlers.PRResponse
+	parseJSON(t, resp, &closed)
+	assert.Equal(t, domain.PRStatusClosed, closed.PR.Status)
+	assert.Greater(t, closed.PR.Version, reassigned.PR.Version)
+}
+
+func TestPRIntegration_ReviewBumpsVersion(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+	pr := createPR(t, env.BaseURL(), "pr-review-version", "Feature", "author")
+	require.NotEmpty(t, pr.AssignedReviewers)
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/review",
+		Body:   dto.SubmitReviewRequest{PullRequestID: "pr-review-version", ReviewerID: pr.AssignedReviewers[0], State: domain.ReviewStateChangesRequested},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	etag := resp.Header.Get("ETag")
+	var reviewed handlers.PRResponse
+	parseJSON(t, resp, &reviewed)
+	assert.Greater(t, reviewed.PR.Version, pr.Version)
+	assert.Equal(t, fmt.Sprintf(`"%d"`, reviewed.PR.Version), etag)
+
+	// Клиент, не видевший вердикт, не может действовать по устаревшей версии
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/close",
+		Body:   dto.PRTransitionRequest{PullRequestID: "pr-review-version", ExpectedVersion: pr.Version},
+	})
+	assertStatusCode(t, resp, http.StatusPreconditionFailed)
+	resp.Body.Close()
+}
+
 func TestPRIntegration_CreateAuthorNotFound(t *testing.T) {
 	env := setupTestEnvironment(t)
 	// Try to create PR with non-existent author
@@ -278,3 +412,283 @@ func TestPRIntegration_ReassignOldReviewerNotFound(t *testing.T) {
 	assertStatusCode(t, resp, http.StatusConflict)
 	assertErrorCode(t, resp, "NOT_ASSIGNED")
 }
+
+func TestPRIntegration_CreateLeastLoaded(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+	first := createPR(t, env.BaseURL(), "pr-load-1", "Feature", "author")
+	require.Len(t, first.AssignedReviewers, 2)
+
+	var idle string
+	for _, id := range []string{"rev1", "rev2", "rev3"} {
+		if id != first.AssignedReviewers[0] && id != first.AssignedReviewers[1] {
+			idle = id
+		}
+	}
+
+	reqBody := map[string]string{"pull_request_id": "pr-load-2", "pull_request_name": "Feature", "author_id": "author", "strategy": "least_loaded"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	var prResp struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &prResp)
+
+	assert.Contains(t, prResp.PR.AssignedReviewers, idle)
+	require.Len(t, prResp.PR.AssignmentDecisions, 2)
+	assert.Equal(t, idle, prResp.PR.AssignmentDecisions[0].UserID)
+	assert.Equal(t, 0, prResp.PR.AssignmentDecisions[0].OpenReviews)
+	assert.Equal(t, 1, prResp.PR.AssignmentDecisions[1].OpenReviews)
+	assert.Equal(t, "least_loaded", prResp.PR.AssignmentDecisions[1].Strategy)
+}
+
+func TestPRIntegration_CapacityExhausted(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "default_max_open_reviews": 1}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	first := createPR(t, env.BaseURL(), "pr-cap-1", "Feature", "author")
+	assert.Equal(t, []string{"rev1"}, first.AssignedReviewers)
+
+	reqBody := map[string]string{"pull_request_id": "pr-cap-2", "pull_request_name": "Feature", "author_id": "author"}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "CAPACITY_EXHAUSTED")
+}
+
+func TestPRIntegration_CapacityQueueDrainedOnMerge(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "default_max_open_reviews": 1, "capacity_overflow": "queue"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	createPR(t, env.BaseURL(), "pr-queue-1", "Feature", "author")
+	queued := createPR(t, env.BaseURL(), "pr-queue-2", "Feature", "author")
+	assert.Empty(t, queued.AssignedReviewers)
+	assert.Equal(t, 1, queued.PendingReviewers)
+
+	mergePR(t, env.BaseURL(), "pr-queue-1")
+
+	review := getUserReview(t, env.BaseURL(), "rev1")
+	ids := []string{}
+	for _, pr := range review.PullRequests {
+		ids = append(ids, pr.ID)
+	}
+	assert.Contains(t, ids, "pr-queue-2")
+}
+
+func TestPRIntegration_PersonalCapacityOverridesTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+
+	reqBody := map[string]interface{}{"user_id": "rev1", "max_open_reviews": 0}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/users/setMaxOpenReviews", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	pr := createPR(t, env.BaseURL(), "pr-personal", "Feature", "author")
+	assert.Equal(t, []string{"rev2"}, pr.AssignedReviewers)
+}
+
+func TestPRIntegration_SubmitReviewLatestStateWins(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	createPR(t, env.BaseURL(), "pr-review", "Test", "author")
+
+	submitReview(t, env.BaseURL(), "pr-review", "reviewer1", "CHANGES_REQUESTED")
+	pr := submitReview(t, env.BaseURL(), "pr-review", "reviewer1", "APPROVED")
+
+	require.Len(t, pr.Reviews, 1)
+	assert.Equal(t, "reviewer1", pr.Reviews[0].UserID)
+	assert.Equal(t, "APPROVED", pr.Reviews[0].State)
+	assert.NotNil(t, pr.Reviews[0].SubmittedAt)
+
+	var history int
+	require.NoError(t, env.DB.QueryRow(`SELECT COUNT(*) FROM review_verdicts WHERE pull_request_id = 'pr-review'`).Scan(&history))
+	assert.Equal(t, 2, history)
+}
+
+func TestPRIntegration_SubmitReviewNotAssigned(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	createPR(t, env.BaseURL(), "pr-review", "Test", "author")
+
+	reqBody := dto.SubmitReviewRequest{PullRequestID: "pr-review", ReviewerID: "author", State: "APPROVED"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/review", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NOT_ASSIGNED")
+}
+
+func TestPRIntegration_ReassignApprovedReviewerRequiresForce(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1", "reviewer2", "reviewer3")
+	pr := createPR(t, env.BaseURL(), "pr-approved", "Test", "author")
+	approver := pr.AssignedReviewers[0]
+	submitReview(t, env.BaseURL(), "pr-approved", approver, "APPROVED")
+
+	reqBody := map[string]interface{}{"pull_request_id": "pr-approved", "old_user_id": approver}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/reassign", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "REVIEWER_APPROVED")
+
+	reqBody["force"] = true
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/reassign", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+	var reassigned ReassignResponse
+	parseJSON(t, resp, &reassigned)
+	assert.NotContains(t, reassigned.PR.AssignedReviewers, approver)
+	for _, review := range reassigned.PR.Reviews {
+		assert.Equal(t, "PENDING", review.State)
+	}
+}
+
+func requireApprovals(t *testing.T, env *TestEnvironment, teamName string, count int) {
+	reqBody := map[string]interface{}{"team_name": teamName, "required_approvals": count}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+}
+
+func TestPRIntegration_MergeRequiresApprovals(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1", "reviewer2")
+	requireApprovals(t, env, "backend", 2)
+	createPR(t, env.BaseURL(), "pr-gated", "Test", "author")
+
+	mergeBody := map[string]interface{}{"pull_request_id": "pr-gated"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/merge", Body: mergeBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NOT_APPROVED")
+
+	submitReview(t, env.BaseURL(), "pr-gated", "reviewer1", "APPROVED")
+	submitReview(t, env.BaseURL(), "pr-gated", "reviewer2", "CHANGES_REQUESTED")
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/merge", Body: mergeBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NOT_APPROVED")
+
+	submitReview(t, env.BaseURL(), "pr-gated", "reviewer2", "APPROVED")
+	pr := mergePR(t, env.BaseURL(), "pr-gated")
+	assert.Equal(t, "MERGED", pr.Status)
+	assert.False(t, pr.MergeForced)
+}
+
+func TestPRIntegration_ForceMergeRequiresAdmin(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	requireApprovals(t, env, "backend", 1)
+	createPR(t, env.BaseURL(), "pr-force", "Test", "author")
+
+	mergeBody := map[string]interface{}{"pull_request_id": "pr-force", "force": true}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/merge", Body: mergeBody})
+	assertStatusCode(t, resp, http.StatusForbidden)
+	assertErrorCode(t, resp, "FORBIDDEN")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method:  http.MethodPost,
+		Path:    "/pullRequest/merge",
+		Body:    mergeBody,
+		Headers: map[string]string{"X-Admin-Token": testAdminToken, "X-Actor": "lead"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var merged struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &merged)
+	assert.Equal(t, "MERGED", merged.PR.Status)
+	assert.True(t, merged.PR.MergeForced)
+	assert.Equal(t, domain.ActorAdmin, merged.PR.MergeForcedBy)
+
+	var forced bool
+	var forcedBy string
+	require.NoError(t, env.DB.QueryRow(`SELECT merge_forced, merge_forced_by FROM pull_requests WHERE id = 'pr-force'`).Scan(&forced, &forcedBy))
+	assert.True(t, forced)
+	assert.Equal(t, domain.ActorAdmin, forcedBy)
+}
+
+func TestPRIntegration_DefaultPolicyBlocksChangesRequested(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	createPR(t, env.BaseURL(), "pr-changes", "Test", "author")
+	submitReview(t, env.BaseURL(), "pr-changes", "reviewer1", "CHANGES_REQUESTED")
+
+	mergeBody := map[string]interface{}{"pull_request_id": "pr-changes"}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/merge", Body: mergeBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NOT_APPROVED")
+}
+
+func changePRStatus(t *testing.T, baseURL, action, prID string, expectedStatus int) *http.Response {
+	reqBody := dto.PRTransitionRequest{PullRequestID: prID}
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/" + action, Body: reqBody})
+	assertStatusCode(t, resp, expectedStatus)
+	return resp
+}
+
+func TestPRIntegration_DraftGetsReviewersWhenReady(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1", "reviewer2")
+
+	reqBody := dto.PullRequestCreateRequest{ID: "pr-draft", Name: "Draft", AuthorID: "author", Draft: true}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusCreated)
+	var created struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &created)
+	assert.Equal(t, "DRAFT", created.PR.Status)
+	assert.Len(t, created.PR.AssignedReviewers, 0)
+
+	resp = changePRStatus(t, env.BaseURL(), "merge", "pr-draft", http.StatusConflict)
+	assertErrorCode(t, resp, "INVALID_STATE")
+
+	resp = changePRStatus(t, env.BaseURL(), "ready", "pr-draft", http.StatusOK)
+	var ready struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &ready)
+	assert.Equal(t, "OPEN", ready.PR.Status)
+	assert.Len(t, ready.PR.AssignedReviewers, 2)
+}
+
+func TestPRIntegration_CloseReleasesReviewersAndReopen(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	createPR(t, env.BaseURL(), "pr-close", "Test", "author")
+
+	resp := changePRStatus(t, env.BaseURL(), "close", "pr-close", http.StatusOK)
+	var closed struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &closed)
+	assert.Equal(t, "CLOSED", closed.PR.Status)
+	assert.NotNil(t, closed.PR.ClosedAt)
+	assert.Len(t, closed.PR.AssignedReviewers, 0)
+	assert.Len(t, getUserReview(t, env.BaseURL(), "reviewer1").PullRequests, 0)
+
+	resp = changePRStatus(t, env.BaseURL(), "close", "pr-close", http.StatusConflict)
+	assertErrorCode(t, resp, "INVALID_STATE")
+
+	resp = changePRStatus(t, env.BaseURL(), "reopen", "pr-close", http.StatusOK)
+	var reopened struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &reopened)
+	assert.Equal(t, "OPEN", reopened.PR.Status)
+	assert.Nil(t, reopened.PR.ClosedAt)
+	assert.Equal(t, []string{"reviewer1"}, reopened.PR.AssignedReviewers)
+}
+
+func TestPRIntegration_CloseMergedPR(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	createPR(t, env.BaseURL(), "pr-merged", "Test", "author")
+	mergePR(t, env.BaseURL(), "pr-merged")
+
+	resp := changePRStatus(t, env.BaseURL(), "close", "pr-merged", http.StatusConflict)
+	assertErrorCode(t, resp, "PR_MERGED")
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"avito/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listPRs(t *testing.T, baseURL string, params url.Values) *dto.PullRequestListResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/list?" + params.Encode()})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var page dto.PullRequestListResponse
+	parseJSON(t, resp, &page)
+	return &page
+}
+
+func prIDs(prs []dto.PullRequestResponse) []string {
+	ids := make([]string, len(prs))
+	for i, pr := range prs {
+		ids[i] = pr.ID
+	}
+	return ids
+}
+
+func TestPRListIntegration_Filters(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice", "bob")
+	createTeamWithUsers(t, env.BaseURL(), "frontend", "carol", "dave")
+
+	createPR(t, env.BaseURL(), "pr-1", "Fix login bug", "alice")
+	createPR(t, env.BaseURL(), "pr-2", "Add payments", "alice")
+	createPR(t, env.BaseURL(), "pr-3", "Login page redesign", "carol")
+	mergePR(t, env.BaseURL(), "pr-2")
+
+	page := listPRs(t, env.BaseURL(), url.Values{})
+	assert.Equal(t, []string{"pr-3", "pr-2", "pr-1"}, prIDs(page.PullRequests))
+	assert.Empty(t, page.NextCursor)
+
+	page = listPRs(t, env.BaseURL(), url.Values{"author_id": {"alice"}, "status": {"OPEN"}})
+	assert.Equal(t, []string{"pr-1"}, prIDs(page.PullRequests))
+
+	page = listPRs(t, env.BaseURL(), url.Values{"reviewer_id": {"bob"}})
+	assert.Equal(t, []string{"pr-2", "pr-1"}, prIDs(page.PullRequests))
+	assert.Contains(t, page.PullRequests[0].AssignedReviewers, "bob")
+
+	page = listPRs(t, env.BaseURL(), url.Values{"team_name": {"frontend"}})
+	assert.Equal(t, []string{"pr-3"}, prIDs(page.PullRequests))
+
+	page = listPRs(t, env.BaseURL(), url.Values{"name": {"LOGIN"}})
+	assert.Equal(t, []string{"pr-3", "pr-1"}, prIDs(page.PullRequests))
+
+	page = listPRs(t, env.BaseURL(), url.Values{"name": {"%"}})
+	assert.Empty(t, page.PullRequests)
+
+	page = listPRs(t, env.BaseURL(), url.Values{"sort": {"merged_at"}})
+	assert.Equal(t, []string{"pr-2"}, prIDs(page.PullRequests))
+
+	page = listPRs(t, env.BaseURL(), url.Values{"created_from": {"2000-01-01T00:00:00Z"}, "created_to": {"2001-01-01T00:00:00Z"}})
+	assert.Empty(t, page.PullRequests)
+}
+
+func TestPRListIntegration_CursorPagination(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice", "bob")
+
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4", "pr-5"} {
+		createPR(t, env.BaseURL(), prID, "Feature", "alice")
+	}
+
+	var seen []string
+	params := url.Values{"limit": {"2"}}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 5, "pagination must terminate")
+		page := listPRs(t, env.BaseURL(), params)
+		assert.LessOrEqual(t, len(page.PullRequests), 2)
+		seen = append(seen, prIDs(page.PullRequests)...)
+		if page.NextCursor == "" {
+			break
+		}
+		params.Set("cursor", page.NextCursor)
+	}
+	assert.Equal(t, []string{"pr-5", "pr-4", "pr-3", "pr-2", "pr-1"}, seen)
+}
+
+func TestPRListIntegration_InvalidParams(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	for _, query := range []string{"status=UNKNOWN", "sort=name", "limit=0", "limit=101", "created_from=yesterday", "cursor=garbage"} {
+		resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/pullRequest/list?" + query})
+		assertStatusCode(t, resp, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"avito/internal/dto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createPRWithMetadata(t *testing.T, baseURL, prID, authorID string, metadata dto.PullRequestMetadata) *dto.PullRequestResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/pullRequest/create",
+		Body:   dto.PullRequestCreateRequest{ID: prID, Name: "Feature", AuthorID: authorID, PullRequestMetadata: metadata},
+	})
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	var prResp struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &prResp)
+	return &prResp.PR
+}
+
+func TestPRMetadataIntegration_CreateReturnsMetadata(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	pr := createPRWithMetadata(t, env.BaseURL(), "pr-1", "author", dto.PullRequestMetadata{
+		Repository:   "org/api",
+		SourceBranch: "feature/limits",
+		TargetBranch: "main",
+		URL:          "https://git.example.com/org/api/pull/1",
+		Labels:       []string{"Security", "backend"},
+		Additions:    120,
+		Deletions:    30,
+	})
+
+	assert.Equal(t, "org/api", pr.Repository)
+	assert.Equal(t, "feature/limits", pr.SourceBranch)
+	assert.Equal(t, "main", pr.TargetBranch)
+	assert.Equal(t, "https://git.example.com/org/api/pull/1", pr.URL)
+	assert.Equal(t, []string{"security", "backend"}, pr.Labels)
+	assert.Equal(t, 120, pr.Additions)
+	assert.Equal(t, 30, pr.Deletions)
+
+	merged := mergePR(t, env.BaseURL(), "pr-1")
+	assert.Equal(t, "org/api", merged.Repository)
+	assert.Equal(t, 150, merged.Additions+merged.Deletions)
+}
+
+func TestPRMetadataIntegration_ChangedFilesCountDefaultsToList(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	pr := createPRWithFiles(t, env.BaseURL(), "pr-1", "author", "a.go", "b.go")
+	assert.Equal(t, 2, pr.ChangedFilesCount)
+}
+
+func TestPRMetadataIntegration_Update(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createPRWithMetadata(t, env.BaseURL(), "pr-1", "author", dto.PullRequestMetadata{Repository: "org/api", Labels: []string{"bug"}})
+
+	body := map[string]interface{}{
+		"pull_request_id":   "pr-1",
+		"pull_request_name": "Renamed",
+		"target_branch":     "release",
+		"labels":            []string{},
+		"additions":         42,
+	}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/update", Body: body})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var prResp struct {
+		PR dto.PullRequestResponse `json:"pr"`
+	}
+	parseJSON(t, resp, &prResp)
+	assert.Equal(t, "Renamed", prResp.PR.Name)
+	assert.Equal(t, "org/api", prResp.PR.Repository)
+	assert.Equal(t, "release", prResp.PR.TargetBranch)
+	assert.Empty(t, prResp.PR.Labels)
+	assert.Equal(t, 42, prResp.PR.Additions)
+	assert.Equal(t, []string{"rev1"}, prResp.PR.AssignedReviewers)
+}
+
+func TestPRMetadataIntegration_UpdateMergedPR(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+	mergePR(t, env.BaseURL(), "pr-1")
+
+	body := map[string]interface{}{"pull_request_id": "pr-1", "additions": 1}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/update", Body: body})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "PR_MERGED")
+
+	body = map[string]interface{}{"pull_request_id": "missing", "additions": 1}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/update", Body: body})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestPRMetadataIntegration_LargePRGetsMoreReviewers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3", "rev4")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "large_pr_lines": 500, "large_pr_reviewers": 3}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var settings dto.TeamSettingsResponse
+	parseJSON(t, resp, &settings)
+	assert.Equal(t, 500, settings.LargePRLines)
+	assert.Equal(t, 3, settings.LargePRReviewers)
+
+	small := createPRWithMetadata(t, env.BaseURL(), "pr-small", "author", dto.PullRequestMetadata{Additions: 400, Deletions: 100})
+	assert.Len(t, small.AssignedReviewers, 2)
+
+	large := createPRWithMetadata(t, env.BaseURL(), "pr-large", "author", dto.PullRequestMetadata{Additions: 400, Deletions: 101})
+	require.Len(t, large.AssignedReviewers, 3)
+	assert.NotContains(t, large.AssignedReviewers, "author")
+}
+
+func TestPRMetadataIntegration_LargePRSettingsValidation(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "large_pr_lines": 500}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertErrorCode(t, resp, "INVALID_INPUT")
+}
@@ -23,24 +23,49 @@ import (
 	"avito/internal/service"
 )
 
+const (
+	testAdminToken   = "test-admin-token"
+	testGitHubSecret = "test-github-secret"
+	testGitLabToken  = "test-gitlab-token"
+)
+
 type TestEnvironment struct {
-	DB           *sql.DB
-	Router       http.Handler
-	Server       *httptest.Server
-	Container    *postgresContainer.PostgresContainer
-	TeamHandler  *handlers.TeamHandler
-	UserHandler  *handlers.UserHandler
-	PRHandler    *handlers.PullRequestHandler
-	StatsHandler *handlers.StatisticsHandler
-	TeamService  *service.TeamService
-	UserService  *service.UserService
-	PRService    *service.PullRequestService
-	StatsService *service.StatisticsService
-	TeamRepo     repository.TeamRepository
-	UserRepo     repository.UserRepository
-	PRRepo       repository.PullRequestRepository
-	StatsRepo    repository.StatisticsRepository
-	TxMgr        repository.TransactionManager
+	DB                     *sql.DB
+	Router                 http.Handler
+	Server                 *httptest.Server
+	Container              *postgresContainer.PostgresContainer
+	TeamHandler            *handlers.TeamHandler
+	UserHandler            *handlers.UserHandler
+	PRHandler              *handlers.PullRequestHandler
+	StatsHandler           *handlers.StatisticsHandler
+	AbsenceHandler         *handlers.AbsenceHandler
+	WebhookHandler         *handlers.WebhookHandler
+	GitHubHandler          *handlers.GitHubHandler
+	GitLabHandler          *handlers.GitLabHandler
+	TeamService            *service.TeamService
+	UserService            *service.UserService
+	PRService              *service.PullRequestService
+	StatsService           *service.StatisticsService
+	AbsenceService         *service.AbsenceService
+	WebhookService         *service.WebhookService
+	IntegrationService     *service.IntegrationService
+	TeamRepo               repository.TeamRepository
+	UserRepo               repository.UserRepository
+	PRRepo                 repository.PullRequestRepository
+	StatsRepo              repository.StatisticsRepository
+	SettingsRepo           repository.TeamSettingsRepository
+	QueueRepo              repository.ReviewQueueRepository
+	AbsenceRepo            repository.AbsenceRepository
+	VerdictRepo            repository.ReviewVerdictRepository
+	FallbackRepo           repository.TeamFallbackRepository
+	CodeOwnersRepo         repository.CodeOwnersRepository
+	RuleRepo               repository.AssignmentRuleRepository
+	HistoryRepo            repository.AssignmentEventRepository
+	OutboxRepo             repository.OutboxRepository
+	WebhookRepo            repository.WebhookRepository
+	IntegrationUserRepo    repository.IntegrationUserRepository
+	IntegrationProjectRepo repository.IntegrationProjectRepository
+	TxMgr                  repository.TransactionManager
 }
 
 func setupTestDB(t *testing.T) (*sql.DB, *postgresContainer.PostgresContainer, func()) {
@@ -96,28 +121,47 @@ func setupTestEnvironment(t *testing.T) *TestEnvironment {
 	userRepo := repository.NewUserRepository(db)
 	prRepo := repository.NewPullRequestRepository(db)
 	statsRepo := repository.NewStatisticsRepository(db)
+	settingsRepo := repository.NewTeamSettingsRepository(db)
+	queueRepo := repository.NewReviewQueueRepository(db)
+	absenceRepo := repository.NewAbsenceRepository(db)
+	verdictRepo := repository.NewReviewVerdictRepository(db)
+	fallbackRepo := repository.NewTeamFallbackRepository(db)
+	codeOwnersRepo := repository.NewCodeOwnersRepository(db)
+	ruleRepo := repository.NewAssignmentRuleRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	historyRepo := repository.NewAssignmentEventRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	integrationUserRepo := repository.NewIntegrationUserRepository(db)
+	integrationProjectRepo := repository.NewIntegrationProjectRepository(db)
 	txMgr := repository.NewTransactionManager(db)
 
-	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, txMgr)
-	userService := service.NewUserService(userRepo)
-	prService := service.NewPullRequestService(prRepo, userRepo, txMgr)
+	teamService := service.NewTeamService(teamRepo, userRepo, prRepo, settingsRepo, queueRepo, fallbackRepo, codeOwnersRepo, ruleRepo, outboxRepo, historyRepo, txMgr)
+	userService := service.NewUserService(userRepo, txMgr)
+	prService := service.NewPullRequestService(prRepo, userRepo, settingsRepo, queueRepo, verdictRepo, fallbackRepo, codeOwnersRepo, ruleRepo, outboxRepo, historyRepo, txMgr)
 	statsService := service.NewStatisticsService(statsRepo)
+	webhookService := service.NewWebhookService(webhookRepo, outboxRepo, txMgr)
+	integrationService := service.NewIntegrationService(prService, userRepo, teamRepo, integrationUserRepo, integrationProjectRepo)
+	absenceService := service.NewAbsenceService(absenceRepo, userRepo, prRepo, settingsRepo, queueRepo, outboxRepo, historyRepo, txMgr)
 
 	teamHandler := handlers.NewTeamHandler(teamService)
 	userHandler := handlers.NewUserHandler(userService, prRepo)
-	prHandler := handlers.NewPullRequestHandler(prService)
+	prHandler := handlers.NewPullRequestHandler(prService, testAdminToken)
 	statsHandler := handlers.NewStatisticsHandler(statsService)
+	absenceHandler := handlers.NewAbsenceHandler(absenceService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	gitlabHandler := handlers.NewGitLabHandler(integrationService, testGitLabToken)
+	githubHandler := handlers.NewGitHubHandler(integrationService, testGitHubSecret)
 
-	router := handlers.Router(teamHandler, userHandler, prHandler, statsHandler)
+	router := handlers.Router(teamHandler, userHandler, prHandler, statsHandler, absenceHandler, webhookHandler, githubHandler, gitlabHandler, testAdminToken)
 
 	server := httptest.NewServer(router)
 	t.Cleanup(server.Close)
 
-	return &TestEnvironment{DB: db, Router: router, Server: server, Container: container, TeamHandler: teamHandler, UserHandler: userHandler, PRHandler: prHandler, StatsHandler: statsHandler, TeamService: teamService, UserService: userService, PRService: prService, StatsService: statsService, TeamRepo: teamRepo, UserRepo: userRepo, PRRepo: prRepo, StatsRepo: statsRepo, TxMgr: txMgr}
+	return &TestEnvironment{DB: db, Router: router, Server: server, Container: container, TeamHandler: teamHandler, UserHandler: userHandler, PRHandler: prHandler, StatsHandler: statsHandler, AbsenceHandler: absenceHandler, WebhookHandler: webhookHandler, GitHubHandler: githubHandler, GitLabHandler: gitlabHandler, TeamService: teamService, UserService: userService, PRService: prService, StatsService: statsService, AbsenceService: absenceService, WebhookService: webhookService, IntegrationService: integrationService, TeamRepo: teamRepo, UserRepo: userRepo, PRRepo: prRepo, StatsRepo: statsRepo, SettingsRepo: settingsRepo, QueueRepo: queueRepo, AbsenceRepo: absenceRepo, VerdictRepo: verdictRepo, FallbackRepo: fallbackRepo, CodeOwnersRepo: codeOwnersRepo, RuleRepo: ruleRepo, HistoryRepo: historyRepo, OutboxRepo: outboxRepo, WebhookRepo: webhookRepo, IntegrationUserRepo: integrationUserRepo, IntegrationProjectRepo: integrationProjectRepo, TxMgr: txMgr}
 }
 
 func cleanDatabase(t *testing.T, db *sql.DB) {
-	_, err := db.Exec(`TRUNCATE TABLE pr_reviewers CASCADE; TRUNCATE TABLE pull_requests CASCADE; TRUNCATE TABLE users CASCADE; TRUNCATE TABLE teams CASCADE;`)
+	_, err := db.Exec(`TRUNCATE TABLE assignment_events CASCADE; TRUNCATE TABLE assignment_rule_firings CASCADE; TRUNCATE TABLE assignment_rules CASCADE; TRUNCATE TABLE team_codeowners CASCADE; TRUNCATE TABLE integration_project_teams CASCADE; TRUNCATE TABLE integration_user_mappings CASCADE; TRUNCATE TABLE webhook_deliveries CASCADE; TRUNCATE TABLE outbox_events CASCADE; TRUNCATE TABLE webhook_subscriptions CASCADE; TRUNCATE TABLE team_fallbacks CASCADE; TRUNCATE TABLE review_verdicts CASCADE; TRUNCATE TABLE absences CASCADE; TRUNCATE TABLE review_queue CASCADE; TRUNCATE TABLE team_settings CASCADE; TRUNCATE TABLE pr_reviewers CASCADE; TRUNCATE TABLE pull_requests CASCADE; TRUNCATE TABLE users CASCADE; TRUNCATE TABLE teams CASCADE;`)
 	require.NoError(t, err)
 }
 
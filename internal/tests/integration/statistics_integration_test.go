@@ -3,6 +3,8 @@ package integration
 import (
 	"testing"
 
+	"avito/internal/dto"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -66,3 +68,40 @@ func TestStatisticsIntegration_AssignmentsByPR(t *testing.T) {
 	assert.Equal(t, "pr-single", prStat["id"])
 	assert.Equal(t, float64(2), prStat["count"])
 }
+
+func TestStatisticsIntegration_FilterByMetadata(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createPRWithMetadata(t, env.BaseURL(), "pr-api", "author", dto.PullRequestMetadata{
+		Repository: "org/api", Labels: []string{"security"}, Additions: 600,
+	})
+	createPRWithMetadata(t, env.BaseURL(), "pr-web", "author", dto.PullRequestMetadata{
+		Repository: "org/web", Additions: 10,
+	})
+
+	filtered := func(query string) dto.StatisticsResponse {
+		resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: "GET", Path: "/statistics?" + query})
+		assertStatusCode(t, resp, 200)
+		var stats dto.StatisticsResponse
+		parseJSON(t, resp, &stats)
+		return stats
+	}
+
+	byRepo := filtered("repository=org/api")
+	assert.Equal(t, 1, byRepo.TotalPRs)
+	assert.Equal(t, []dto.AssignmentStat{{ID: "pr-api", Count: 1}}, byRepo.AssignmentsByPR)
+
+	byLabel := filtered("label=Security")
+	assert.Equal(t, 1, byLabel.TotalPRs)
+
+	bySize := filtered("min_lines=500")
+	assert.Equal(t, 1, bySize.TotalPRs)
+	assert.Equal(t, 1, bySize.TotalAssignments)
+
+	small := filtered("max_lines=100&repository=org/web")
+	assert.Equal(t, 1, small.TotalPRs)
+	assert.Equal(t, 2, small.ActiveUsers)
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: "GET", Path: "/statistics?min_lines=-1"})
+	assertStatusCode(t, resp, 400)
+}
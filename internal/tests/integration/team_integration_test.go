@@ -46,7 +46,7 @@ func TestTeamIntegration_CreateTeamWithEmptyMembers(t *testing.T) {
 	env := setupTestEnvironment(t)
 	reqBody := dto.TeamRequest{Name: "empty_team", Members: []dto.TeamMember{}}
 	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/add", Body: reqBody})
-	assertStatusCode(t, resp, http.StatusInternalServerError)
+	assertStatusCode(t, resp, http.StatusBadRequest)
 	assertErrorCode(t, resp, "INVALID_INPUT")
 }
 
@@ -93,7 +93,7 @@ func TestTeamIntegration_ValidateTeamName(t *testing.T) {
 		teamName     string
 		expectStatus int
 	}{
-		{"empty name", "", http.StatusInternalServerError},
+		{"empty name", "", http.StatusBadRequest},
 		{"valid name", "valid_team", http.StatusCreated},
 		{"name with spaces", "Team Name", http.StatusCreated},
 	}
@@ -218,3 +218,141 @@ func TestTeamIntegration_MassDeactivate_NoReplacement(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, prUpdated.AssignedReviewers, "Should remove assignment if no candidates available")
 }
+
+func TestTeamIntegration_DefaultSettings(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/settings?team_name=backend"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var settings dto.TeamSettingsResponse
+	parseJSON(t, resp, &settings)
+	assert.Equal(t, 1, settings.MinReviewers)
+	assert.Equal(t, 2, settings.MaxReviewers)
+	assert.Equal(t, "random", settings.ReviewerStrategy)
+	assert.True(t, settings.AllowNoReviewers)
+}
+
+func TestTeamIntegration_CreateTeamWithStrategy(t *testing.T) {
+	env := setupTestEnvironment(t)
+	reqBody := dto.TeamRequest{
+		Name:             "backend",
+		ReviewerStrategy: domain.ReviewerStrategyRoundRobin,
+		Members:          []dto.TeamMember{{UserID: "alice", Username: "Alice", IsActive: true}},
+	}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/add", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	var team dto.TeamResponse
+	parseJSON(t, resp, &team)
+	assert.Equal(t, domain.ReviewerStrategyRoundRobin, team.ReviewerStrategy)
+
+	// Стратегия из /team/add - та же настройка, что в /team/settings
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/settings?team_name=backend"})
+	assertStatusCode(t, resp, http.StatusOK)
+	var settings dto.TeamSettingsResponse
+	parseJSON(t, resp, &settings)
+	assert.Equal(t, domain.ReviewerStrategyRoundRobin, settings.ReviewerStrategy)
+}
+
+func TestTeamIntegration_SettingsControlReviewerCount(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "r1", "r2", "r3", "r4")
+
+	reqBody := map[string]interface{}{"team_name": "backend", "min_reviewers": 3, "max_reviewers": 3}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	pr := createPR(t, env.BaseURL(), "pr-three", "Feature", "author")
+	assert.Len(t, pr.AssignedReviewers, 3)
+
+	setUserActive(t, env.BaseURL(), "r1", false)
+	setUserActive(t, env.BaseURL(), "r2", false)
+
+	createReq := map[string]string{"pull_request_id": "pr-short", "pull_request_name": "Feature", "author_id": "author"}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: createReq})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NO_CANDIDATE")
+}
+
+func TestTeamIntegration_SettingsRejectEmptyReviewers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "solo", "author")
+
+	reqBody := map[string]interface{}{"team_name": "solo", "allow_no_reviewers": false}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	createReq := map[string]string{"pull_request_id": "pr-solo", "pull_request_name": "Feature", "author_id": "author"}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: createReq})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "NO_CANDIDATE")
+}
+
+func TestTeamIntegration_SettingsInvalidRange(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+
+	reqBody := map[string]interface{}{"team_name": "backend", "min_reviewers": 3, "max_reviewers": 1}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: reqBody})
+	assertErrorCode(t, resp, "INVALID_INPUT")
+}
+
+func setFallbacks(t *testing.T, baseURL, teamName string, fallbackTeams ...string) *http.Response {
+	reqBody := dto.TeamFallbacks{TeamName: teamName, FallbackTeams: fallbackTeams}
+	return doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/team/fallbacks/set", Body: reqBody})
+}
+
+func TestTeamIntegration_FallbackTeamFillsReviewers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "small", "author", "teammate")
+	createTeamWithUsers(t, env.BaseURL(), "empty", "idle")
+	setUserActive(t, env.BaseURL(), "idle", false)
+	createTeamWithUsers(t, env.BaseURL(), "platform", "helper")
+
+	resp := setFallbacks(t, env.BaseURL(), "small", "empty", "platform")
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/fallbacks?team_name=small"})
+	assertStatusCode(t, resp, http.StatusOK)
+	var fallbacks dto.TeamFallbacks
+	parseJSON(t, resp, &fallbacks)
+	assert.Equal(t, []string{"empty", "platform"}, fallbacks.FallbackTeams)
+
+	pr := createPR(t, env.BaseURL(), "pr-fallback", "Test", "author")
+	assert.ElementsMatch(t, []string{"teammate", "helper"}, pr.AssignedReviewers)
+
+	byUser := map[string]dto.ReviewerDecision{}
+	for _, d := range pr.AssignmentDecisions {
+		byUser[d.UserID] = d
+	}
+	assert.Empty(t, byUser["teammate"].FallbackTeam)
+	assert.Equal(t, "platform", byUser["helper"].FallbackTeam)
+}
+
+func TestTeamIntegration_FallbackUsedForReassign(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "small", "author", "teammate")
+	createTeamWithUsers(t, env.BaseURL(), "platform", "helper")
+	createPR(t, env.BaseURL(), "pr-reassign", "Test", "author")
+
+	resp := setFallbacks(t, env.BaseURL(), "small", "platform")
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	result := reassignReviewer(t, env.BaseURL(), "pr-reassign", "teammate")
+	assert.Equal(t, "helper", result.ReplacedBy)
+	require.Len(t, result.PR.AssignmentDecisions, 1)
+	assert.Equal(t, "platform", result.PR.AssignmentDecisions[0].FallbackTeam)
+}
+
+func TestTeamIntegration_FallbackUnknownTeam(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "small", "author")
+
+	resp := setFallbacks(t, env.BaseURL(), "small", "ghost")
+	assertStatusCode(t, resp, http.StatusNotFound)
+	assertErrorCode(t, resp, "NOT_FOUND")
+}
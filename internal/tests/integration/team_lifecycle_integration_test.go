@@ -0,0 +1,160 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+)
+
+func deleteTeam(t *testing.T, baseURL string, req dto.DeleteTeamRequest) *http.Response {
+	return doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/team/delete", Body: req})
+}
+
+func TestTeamLifecycleIntegration_Rename(t *testing.T) {
+	env := setupTestEnvironment(t)
+	ctx := context.Background()
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createTeamWithUsers(t, env.BaseURL(), "platform", "helper")
+	createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "reviewer_strategy": domain.ReviewerStrategyLeastLoaded}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/rename",
+		Body:   dto.RenameTeamRequest{TeamName: "backend", NewTeamName: "core"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var team dto.TeamResponse
+	parseJSON(t, resp, &team)
+	assert.Equal(t, "core", team.TeamName)
+	assert.ElementsMatch(t, []string{"author", "rev1"}, teamMemberIDs(&team))
+
+	_, resp = getTeam(t, env.BaseURL(), "backend")
+	assertStatusCode(t, resp, http.StatusNotFound)
+
+	author, err := env.UserRepo.Get(ctx, "author")
+	require.NoError(t, err)
+	assert.Equal(t, "core", author.TeamName)
+
+	settings, err := env.SettingsRepo.Get(ctx, "core")
+	require.NoError(t, err)
+	assert.Equal(t, domain.ReviewerStrategyLeastLoaded, settings.ReviewerStrategy)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/rename",
+		Body:   dto.RenameTeamRequest{TeamName: "core", NewTeamName: "platform"},
+	})
+	assertStatusCode(t, resp, http.StatusBadRequest)
+	assertErrorCode(t, resp, "TEAM_EXISTS")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/rename",
+		Body:   dto.RenameTeamRequest{TeamName: "ghost", NewTeamName: "spirit"},
+	})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestTeamLifecycleIntegration_DeleteRequiresChoiceForOpenPRs(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+
+	resp := deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "backend"})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "INVALID_STATE")
+
+	team, _ := getTeam(t, env.BaseURL(), "backend")
+	assert.Len(t, team.Members, 2)
+
+	// Без незавершённых PR команда удаляется без выбора, а участники и их PR остаются
+	mergePR(t, env.BaseURL(), "pr-1")
+	resp = deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "backend"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	_, resp = getTeam(t, env.BaseURL(), "backend")
+	assertStatusCode(t, resp, http.StatusNotFound)
+
+	author, err := env.UserRepo.Get(context.Background(), "author")
+	require.NoError(t, err)
+	assert.Empty(t, author.TeamName)
+	assert.False(t, author.IsActive)
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.Equal(t, domain.PRStatusMerged, details.PR.Status)
+}
+
+func TestTeamLifecycleIntegration_DeleteClosesOpenPRs(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+	createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+
+	resp := deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "backend", OpenPRs: domain.OpenPRsClose})
+	assertStatusCode(t, resp, http.StatusOK)
+	var result dto.DeleteTeamResponse
+	parseJSON(t, resp, &result)
+	assert.Equal(t, []string{"pr-1"}, result.PullRequests)
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.Equal(t, domain.PRStatusClosed, details.PR.Status)
+	assert.Empty(t, details.PR.AssignedReviewers)
+	last := details.History[len(details.History)-1]
+	assert.Equal(t, domain.AssignmentEventRemoved, last.Event)
+	assert.Equal(t, "team_deletion", last.Reason)
+}
+
+func TestTeamLifecycleIntegration_DeleteReassignsOpenPRs(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2")
+	createTeamWithUsers(t, env.BaseURL(), "platform", "p1", "p2")
+	created := createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+	require.NotEmpty(t, created.AssignedReviewers)
+
+	resp := deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "backend", OpenPRs: domain.OpenPRsReassign, ReassignTo: "missing"})
+	assertStatusCode(t, resp, http.StatusNotFound)
+
+	resp = deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "backend", OpenPRs: domain.OpenPRsReassign, ReassignTo: "platform"})
+	assertStatusCode(t, resp, http.StatusOK)
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.Equal(t, domain.PRStatusOpen, details.PR.Status)
+	assert.Equal(t, "platform", details.PR.ReviewTeam)
+	assert.Len(t, details.PR.AssignedReviewers, len(created.AssignedReviewers))
+	for _, reviewer := range details.PR.AssignedReviewers {
+		assert.Contains(t, []string{"p1", "p2"}, reviewer)
+	}
+}
+
+func TestTeamLifecycleIntegration_DeleteKeepsOtherTeamsRules(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+	createTeamWithUsers(t, env.BaseURL(), "security", "sec1")
+	resp := setRules(t, env.BaseURL(), "backend",
+		dto.AssignmentRule{Name: "security", When: dto.AssignmentCondition{Labels: []string{"security"}}, Then: dto.AssignmentAction{ExtraTeam: "security", ExtraReviewers: 1}},
+	)
+	assertStatusCode(t, resp, http.StatusOK)
+
+	resp = deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "security"})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "INVALID_STATE")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/team/rules?team_name=backend"})
+	var rules dto.TeamRules
+	parseJSON(t, resp, &rules)
+	require.Len(t, rules.Rules, 1)
+
+	// После изменения правил команду можно удалить
+	assertStatusCode(t, setRules(t, env.BaseURL(), "backend"), http.StatusOK)
+	resp = deleteTeam(t, env.BaseURL(), dto.DeleteTeamRequest{TeamName: "security"})
+	assertStatusCode(t, resp, http.StatusOK)
+}
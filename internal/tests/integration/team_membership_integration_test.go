@@ -0,0 +1,225 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+)
+
+func teamMemberIDs(team *dto.TeamResponse) []string {
+	ids := make([]string, len(team.Members))
+	for i, m := range team.Members {
+		ids[i] = m.UserID
+	}
+	return ids
+}
+
+func TestTeamMembershipIntegration_AddMembers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+	createTeamWithUsers(t, env.BaseURL(), "frontend", "carol")
+
+	addUserToTeam(t, env.BaseURL(), "backend", "bob", true)
+	team, _ := getTeam(t, env.BaseURL(), "backend")
+	assert.ElementsMatch(t, []string{"alice", "bob"}, teamMemberIDs(team))
+
+	// Повторное добавление и молчаливый перевод из другой команды запрещены
+	for _, userID := range []string{"bob", "carol"} {
+		resp := doRequest(t, env.BaseURL(), HTTPRequest{
+			Method: http.MethodPost,
+			Path:   "/team/members/add",
+			Body: dto.AddMembersRequest{
+				TeamName: "backend",
+				Members:  []dto.TeamMember{{UserID: userID, Username: "Someone", IsActive: true}},
+			},
+		})
+		assertStatusCode(t, resp, http.StatusConflict)
+		assertErrorCode(t, resp, "USER_IN_TEAM")
+	}
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/add",
+		Body:   dto.TeamRequest{Name: "platform", Members: []dto.TeamMember{{UserID: "carol", Username: "Carol", IsActive: true}}},
+	})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "USER_IN_TEAM")
+
+	carol, err := env.UserRepo.Get(context.Background(), "carol")
+	require.NoError(t, err)
+	assert.Equal(t, "frontend", carol.TeamName)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/add",
+		Body:   dto.AddMembersRequest{TeamName: "ghost", Members: []dto.TeamMember{{UserID: "dave", Username: "Dave", IsActive: true}}},
+	})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestTeamMembershipIntegration_ConcurrentAddToDifferentTeams(t *testing.T) {
+	env := setupTestEnvironment(t)
+	teams := []string{"backend", "frontend", "platform", "mobile"}
+	for _, teamName := range teams {
+		createTeamWithUsers(t, env.BaseURL(), teamName, teamName+"-lead")
+	}
+
+	var wg sync.WaitGroup
+	statuses := make([]int, len(teams))
+	for i, teamName := range teams {
+		wg.Add(1)
+		go func(idx int, teamName string) {
+			defer wg.Done()
+			resp := doRequest(t, env.BaseURL(), HTTPRequest{
+				Method: http.MethodPost,
+				Path:   "/team/members/add",
+				Body: dto.AddMembersRequest{
+					TeamName: teamName,
+					Members:  []dto.TeamMember{{UserID: "newcomer", Username: "Newcomer", IsActive: true}},
+				},
+			})
+			resp.Body.Close()
+			statuses[idx] = resp.StatusCode
+		}(i, teamName)
+	}
+	wg.Wait()
+
+	// Новый пользователь попадает ровно в одну команду, остальные получают USER_IN_TEAM
+	winner := ""
+	for i, status := range statuses {
+		if status == http.StatusOK {
+			assert.Empty(t, winner, "only one team should get the user")
+			winner = teams[i]
+			continue
+		}
+		assert.Equal(t, http.StatusConflict, status)
+	}
+	require.NotEmpty(t, winner)
+
+	user, err := env.UserRepo.Get(context.Background(), "newcomer")
+	require.NoError(t, err)
+	assert.Equal(t, winner, user.TeamName)
+}
+
+func TestTeamMembershipIntegration_RemoveMembers(t *testing.T) {
+	env := setupTestEnvironment(t)
+	ctx := context.Background()
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+	created := createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+	require.NotEmpty(t, created.AssignedReviewers)
+	removed := created.AssignedReviewers[0]
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/remove",
+		Body:   dto.RemoveMembersRequest{TeamName: "backend", UserIDs: []string{removed}},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var team dto.TeamResponse
+	parseJSON(t, resp, &team)
+	assert.NotContains(t, teamMemberIDs(&team), removed)
+
+	user, err := env.UserRepo.Get(ctx, removed)
+	require.NoError(t, err)
+	assert.Empty(t, user.TeamName)
+	assert.False(t, user.IsActive)
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.NotContains(t, details.PR.AssignedReviewers, removed)
+	assert.Len(t, details.PR.AssignedReviewers, len(created.AssignedReviewers))
+	last := details.History[len(details.History)-1]
+	assert.Equal(t, domain.AssignmentEventReassigned, last.Event)
+	assert.Equal(t, removed, last.PreviousReviewerID)
+	assert.Equal(t, "removal", last.Reason)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/remove",
+		Body:   dto.RemoveMembersRequest{TeamName: "backend", UserIDs: []string{removed}},
+	})
+	assertStatusCode(t, resp, http.StatusNotFound)
+
+	// Исключённого можно снова добавить в команду
+	addUserToTeam(t, env.BaseURL(), "backend", removed, true)
+	user, err = env.UserRepo.Get(ctx, removed)
+	require.NoError(t, err)
+	assert.Equal(t, "backend", user.TeamName)
+	assert.True(t, user.IsActive)
+}
+
+func TestTeamMembershipIntegration_TransferUser(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1", "rev2", "rev3")
+	createTeamWithUsers(t, env.BaseURL(), "frontend", "fe")
+	created := createPR(t, env.BaseURL(), "pr-1", "Feature", "author")
+	require.NotEmpty(t, created.AssignedReviewers)
+	moved := created.AssignedReviewers[0]
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/transfer",
+		Body:   dto.TransferUserRequest{UserID: moved, TeamName: "frontend"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var user dto.UserResponse
+	parseJSON(t, resp, &user)
+	assert.Equal(t, "frontend", user.TeamName)
+	assert.True(t, user.IsActive)
+
+	frontend, _ := getTeam(t, env.BaseURL(), "frontend")
+	assert.ElementsMatch(t, []string{"fe", moved}, teamMemberIDs(frontend))
+
+	details := getPRDetails(t, env.BaseURL(), "pr-1")
+	assert.NotContains(t, details.PR.AssignedReviewers, moved)
+	for _, reviewer := range details.PR.AssignedReviewers {
+		assert.Contains(t, []string{"rev1", "rev2", "rev3"}, reviewer)
+	}
+	last := details.History[len(details.History)-1]
+	assert.Equal(t, moved, last.PreviousReviewerID)
+	assert.Equal(t, "transfer", last.Reason)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/transfer",
+		Body:   dto.TransferUserRequest{UserID: moved, TeamName: "frontend"},
+	})
+	assertStatusCode(t, resp, http.StatusConflict)
+	assertErrorCode(t, resp, "USER_IN_TEAM")
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/team/members/transfer",
+		Body:   dto.TransferUserRequest{UserID: "nobody", TeamName: "frontend"},
+	})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestTeamMembershipIntegration_SetUsername(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/users/setUsername",
+		Body:   dto.SetUsernameRequest{UserID: "alice", Username: "Alice Cooper"},
+	})
+	assertStatusCode(t, resp, http.StatusOK)
+	var user dto.UserResponse
+	parseJSON(t, resp, &user)
+	assert.Equal(t, "Alice Cooper", user.Username)
+	assert.Equal(t, "backend", user.TeamName)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/users/setUsername",
+		Body:   dto.SetUsernameRequest{UserID: "nobody", Username: "Nobody"},
+	})
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
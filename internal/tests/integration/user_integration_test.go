@@ -1,12 +1,16 @@
 package integration
 
 import (
+	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"avito/internal/domain"
+	"avito/internal/handlers"
 )
 
 func TestUserIntegration_SetActiveDeactivate(t *testing.T) {
@@ -57,6 +61,51 @@ func TestUserIntegration_GetReviewWithPRs(t *testing.T) {
 	assert.Equal(t, "OPEN", review.PullRequests[0].Status)
 }
 
+func TestUserIntegration_GetReviewStatusAndPagination(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1")
+	for _, prID := range []string{"pr-1", "pr-2", "pr-3", "pr-4"} {
+		createPR(t, env.BaseURL(), prID, "Feature", "author")
+	}
+	mergePR(t, env.BaseURL(), "pr-2")
+
+	getPage := func(query string) handlers.UserReviewResponse {
+		resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/users/getReview?user_id=reviewer1" + query})
+		assertStatusCode(t, resp, http.StatusOK)
+		var page handlers.UserReviewResponse
+		parseJSON(t, resp, &page)
+		return page
+	}
+
+	page := getPage("&status=OPEN&limit=2")
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.PullRequests, 2)
+	assert.Equal(t, "pr-4", page.PullRequests[0].ID)
+	assert.Equal(t, "pr-3", page.PullRequests[1].ID)
+	require.NotEmpty(t, page.NextCursor)
+
+	page = getPage("&status=OPEN&limit=2&cursor=" + page.NextCursor)
+	assert.Equal(t, 3, page.Total)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-1", page.PullRequests[0].ID)
+	assert.Empty(t, page.NextCursor)
+
+	page = getPage("&status=MERGED")
+	assert.Equal(t, 1, page.Total)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-2", page.PullRequests[0].ID)
+
+	page = getPage("")
+	assert.Equal(t, 4, page.Total)
+	assert.Len(t, page.PullRequests, 4)
+	assert.Empty(t, page.NextCursor)
+
+	for _, query := range []string{"&status=DONE", "&limit=0", "&cursor=garbage"} {
+		resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/users/getReview?user_id=reviewer1" + query})
+		assertStatusCode(t, resp, http.StatusBadRequest)
+	}
+}
+
 func TestUserIntegration_InactiveUserNotAssigned(t *testing.T) {
 	env := setupTestEnvironment(t)
 	members := []domain.TeamMember{
@@ -77,3 +126,91 @@ func TestUserIntegration_GetReviewUserNotFound(t *testing.T) {
 	assertStatusCode(t, resp, http.StatusNotFound)
 	assertErrorCode(t, resp, "NOT_FOUND")
 }
+
+func createAbsence(t *testing.T, baseURL, userID string, startsAt, endsAt time.Time) handlers.AbsenceResponse {
+	reqBody := map[string]interface{}{"user_id": userID, "starts_at": startsAt, "ends_at": endsAt, "reason": "vacation"}
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/users/absences/create", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	var result handlers.AbsenceResponse
+	parseJSON(t, resp, &result)
+	return result
+}
+
+func TestUserIntegration_AbsentUserNotAssigned(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "away", "present")
+	createAbsence(t, env.BaseURL(), "away", time.Now().Add(-time.Hour), time.Now().Add(24*time.Hour))
+
+	pr := createPR(t, env.BaseURL(), "pr-absence", "Test", "author")
+	assert.Equal(t, []string{"present"}, pr.AssignedReviewers)
+
+	user, err := env.UserRepo.Get(context.Background(), "away")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive, "absence must not touch is_active")
+}
+
+func TestUserIntegration_FutureAbsenceDoesNotExclude(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "later")
+	createAbsence(t, env.BaseURL(), "later", time.Now().Add(24*time.Hour), time.Now().Add(48*time.Hour))
+
+	pr := createPR(t, env.BaseURL(), "pr-future", "Test", "author")
+	assert.Equal(t, []string{"later"}, pr.AssignedReviewers)
+}
+
+func TestUserIntegration_AbsenceListAndDelete(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+	created := createAbsence(t, env.BaseURL(), "alice", time.Now(), time.Now().Add(time.Hour))
+	assert.Equal(t, "vacation", created.Absence.Reason)
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/users/absences/list?user_id=alice"})
+	assertStatusCode(t, resp, http.StatusOK)
+	var list handlers.UserAbsencesResponse
+	parseJSON(t, resp, &list)
+	require.Len(t, list.Absences, 1)
+	assert.Equal(t, created.Absence.ID, list.Absences[0].ID)
+
+	deleteBody := map[string]interface{}{"absence_id": created.Absence.ID}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/users/absences/delete", Body: deleteBody})
+	assertStatusCode(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/users/absences/delete", Body: deleteBody})
+	assertStatusCode(t, resp, http.StatusNotFound)
+	assertErrorCode(t, resp, "NOT_FOUND")
+}
+
+func TestUserIntegration_AbsenceInvalidPeriod(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "alice")
+	now := time.Now()
+	reqBody := map[string]interface{}{"user_id": "alice", "starts_at": now, "ends_at": now.Add(-time.Hour)}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/users/absences/create", Body: reqBody})
+	assertErrorCode(t, resp, "INVALID_INPUT")
+}
+
+func TestUserIntegration_StartedAbsenceReassignsReviews(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "reviewer1", "reviewer2", "reviewer3")
+	pr := createPR(t, env.BaseURL(), "pr-away", "Test", "author")
+	require.Len(t, pr.AssignedReviewers, 2)
+	away := pr.AssignedReviewers[0]
+
+	createAbsence(t, env.BaseURL(), away, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+	require.NoError(t, env.AbsenceService.ReassignStartedAbsences(context.Background()))
+
+	review := getUserReview(t, env.BaseURL(), away)
+	assert.Len(t, review.PullRequests, 0)
+
+	reviewers, err := env.PRRepo.GetReviewersByPRs(context.Background(), []string{"pr-away"})
+	require.NoError(t, err)
+	assert.Len(t, reviewers["pr-away"], 2)
+	assert.NotContains(t, reviewers["pr-away"], away)
+
+	absences, err := env.AbsenceRepo.ListByUser(context.Background(), away)
+	require.NoError(t, err)
+	require.Len(t, absences, 1)
+	assert.NotNil(t, absences[0].ReviewsReassignedAt)
+}
@@ -0,0 +1,252 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"avito/internal/domain"
+	"avito/internal/dto"
+	"avito/internal/handlers"
+	"avito/internal/service"
+)
+
+type receivedWebhook struct {
+	Event     string
+	Signature string
+	Body      []byte
+}
+
+// webhookReceiver - тестовый получатель, отвечающий заданными кодами по очереди (последний повторяется)
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	received []receivedWebhook
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	rcv.received = append(rcv.received, receivedWebhook{
+		Event:     r.Header.Get(service.WebhookEventHeader),
+		Signature: r.Header.Get(service.WebhookSignatureHeader),
+		Body:      body,
+	})
+
+	status := http.StatusOK
+	if len(rcv.statuses) > 0 {
+		status = rcv.statuses[0]
+		if len(rcv.statuses) > 1 {
+			rcv.statuses = rcv.statuses[1:]
+		}
+	}
+	w.WriteHeader(status)
+}
+
+func (rcv *webhookReceiver) events() []string {
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	events := make([]string, len(rcv.received))
+	for i, r := range rcv.received {
+		events[i] = r.Event
+	}
+	return events
+}
+
+func createWebhook(t *testing.T, baseURL string, req dto.CreateWebhookRequest) handlers.CreatedWebhookResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodPost, Path: "/webhooks/create", Body: req})
+	assertStatusCode(t, resp, http.StatusCreated)
+	var result handlers.CreatedWebhookResponse
+	parseJSON(t, resp, &result)
+	return result
+}
+
+func getDeliveries(t *testing.T, baseURL string, subscriptionID int64) []dto.WebhookDeliveryResponse {
+	resp := doRequest(t, baseURL, HTTPRequest{Method: http.MethodGet, Path: fmt.Sprintf("/webhooks/deliveries?subscription_id=%d", subscriptionID)})
+	assertStatusCode(t, resp, http.StatusOK)
+	var result handlers.WebhookDeliveriesResponse
+	parseJSON(t, resp, &result)
+	return result.Deliveries
+}
+
+func TestWebhookIntegration_CRUD(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	created := createWebhook(t, env.BaseURL(), dto.CreateWebhookRequest{
+		URL:        "https://example.com/hook",
+		EventTypes: []string{domain.EventPRCreated},
+	})
+	assert.NotEmpty(t, created.Secret)
+	assert.True(t, created.Webhook.IsActive)
+
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: fmt.Sprintf("/webhooks/get?id=%d", created.Webhook.ID)})
+	assertStatusCode(t, resp, http.StatusOK)
+	var got map[string]map[string]interface{}
+	parseJSON(t, resp, &got)
+	assert.NotContains(t, got["webhook"], "secret")
+
+	update := dto.UpdateWebhookRequest{ID: created.Webhook.ID, URL: "https://example.com/other", EventTypes: []string{domain.EventPRMerged}}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/webhooks/update", Body: update})
+	assertStatusCode(t, resp, http.StatusOK)
+	var updated handlers.WebhookResponse
+	parseJSON(t, resp, &updated)
+	assert.Equal(t, "https://example.com/other", updated.Webhook.URL)
+	assert.Equal(t, []string{domain.EventPRMerged}, updated.Webhook.EventTypes)
+	assert.False(t, updated.Webhook.IsActive)
+
+	stored, err := env.WebhookRepo.Get(context.Background(), created.Webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Secret, stored.Secret)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodGet, Path: "/webhooks/list"})
+	assertStatusCode(t, resp, http.StatusOK)
+	var list handlers.WebhooksResponse
+	parseJSON(t, resp, &list)
+	assert.Len(t, list.Webhooks, 1)
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/webhooks/delete", Body: dto.DeleteWebhookRequest{ID: created.Webhook.ID}})
+	assertStatusCode(t, resp, http.StatusNoContent)
+	resp.Body.Close()
+
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/webhooks/delete", Body: dto.DeleteWebhookRequest{ID: created.Webhook.ID}})
+	assertStatusCode(t, resp, http.StatusNotFound)
+	assertErrorCode(t, resp, "NOT_FOUND")
+}
+
+func TestWebhookIntegration_UnknownEventType(t *testing.T) {
+	env := setupTestEnvironment(t)
+
+	req := dto.CreateWebhookRequest{URL: "https://example.com/hook", EventTypes: []string{"pr.deleted"}}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/webhooks/create", Body: req})
+	assertErrorCode(t, resp, "INVALID_INPUT")
+}
+
+func TestWebhookIntegration_SignedDeliveryWithFilter(t *testing.T) {
+	env := setupTestEnvironment(t)
+	receiver := &webhookReceiver{}
+	target := httptest.NewServer(receiver)
+	t.Cleanup(target.Close)
+
+	created := createWebhook(t, env.BaseURL(), dto.CreateWebhookRequest{
+		URL:        target.URL,
+		EventTypes: []string{domain.EventPRCreated, domain.EventReviewersAssigned},
+	})
+
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+	createPR(t, env.BaseURL(), "pr-hook", "Feature", "author")
+	mergePR(t, env.BaseURL(), "pr-hook")
+
+	require.NoError(t, env.WebhookService.Dispatch(context.Background()))
+
+	assert.ElementsMatch(t, []string{domain.EventPRCreated, domain.EventReviewersAssigned}, receiver.events())
+	for _, r := range receiver.received {
+		assert.Equal(t, service.SignWebhookPayload(created.Secret, r.Body), r.Signature)
+
+		var body struct {
+			Event string          `json:"event"`
+			Data  json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(r.Body, &body))
+		assert.Equal(t, r.Event, body.Event)
+		assert.Contains(t, string(body.Data), "pr-hook")
+	}
+
+	deliveries := getDeliveries(t, env.BaseURL(), created.Webhook.ID)
+	require.Len(t, deliveries, 2)
+	for _, d := range deliveries {
+		assert.Equal(t, domain.DeliveryStatusDelivered, d.Status)
+		assert.Equal(t, 1, d.Attempts)
+	}
+}
+
+func TestWebhookIntegration_FailedDeliveryIsRetried(t *testing.T) {
+	env := setupTestEnvironment(t)
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError, http.StatusOK}}
+	target := httptest.NewServer(receiver)
+	t.Cleanup(target.Close)
+
+	created := createWebhook(t, env.BaseURL(), dto.CreateWebhookRequest{URL: target.URL, EventTypes: []string{domain.EventPRCreated}})
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+	createPR(t, env.BaseURL(), "pr-retry", "Feature", "author")
+
+	require.NoError(t, env.WebhookService.Dispatch(context.Background()))
+
+	deliveries := getDeliveries(t, env.BaseURL(), created.Webhook.ID)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, domain.DeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].ResponseStatus)
+	assert.Equal(t, http.StatusInternalServerError, *deliveries[0].ResponseStatus)
+	require.NotNil(t, deliveries[0].NextAttemptAt)
+
+	// Не ждём backoff: переносим следующую попытку на текущий момент
+	_, err := env.DB.Exec(`UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP - INTERVAL '1 second'`)
+	require.NoError(t, err)
+	require.NoError(t, env.WebhookService.Dispatch(context.Background()))
+
+	deliveries = getDeliveries(t, env.BaseURL(), created.Webhook.ID)
+	assert.Equal(t, domain.DeliveryStatusDelivered, deliveries[0].Status)
+	assert.Equal(t, 2, deliveries[0].Attempts)
+	assert.Len(t, receiver.events(), 2)
+}
+
+func TestWebhookIntegration_DeactivatedSubscriptionStopsRetries(t *testing.T) {
+	env := setupTestEnvironment(t)
+	receiver := &webhookReceiver{statuses: []int{http.StatusInternalServerError}}
+	target := httptest.NewServer(receiver)
+	t.Cleanup(target.Close)
+
+	created := createWebhook(t, env.BaseURL(), dto.CreateWebhookRequest{URL: target.URL, EventTypes: []string{domain.EventPRCreated}})
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author")
+	createPR(t, env.BaseURL(), "pr-inactive", "Feature", "author")
+	require.NoError(t, env.WebhookService.Dispatch(context.Background()))
+
+	updateReq := dto.UpdateWebhookRequest{ID: created.Webhook.ID, URL: target.URL, EventTypes: []string{domain.EventPRCreated}, IsActive: false}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/webhooks/update", Body: updateReq})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	_, err := env.DB.Exec(`UPDATE webhook_deliveries SET next_attempt_at = CURRENT_TIMESTAMP - INTERVAL '1 second'`)
+	require.NoError(t, err)
+	require.NoError(t, env.WebhookService.Dispatch(context.Background()))
+
+	deliveries := getDeliveries(t, env.BaseURL(), created.Webhook.ID)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, domain.DeliveryStatusPending, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Len(t, receiver.events(), 1)
+}
+
+func TestWebhookIntegration_RolledBackChangePublishesNothing(t *testing.T) {
+	env := setupTestEnvironment(t)
+	createTeamWithUsers(t, env.BaseURL(), "backend", "author", "rev1")
+
+	settingsReq := map[string]interface{}{"team_name": "backend", "default_max_open_reviews": 1}
+	resp := doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/team/settings/update", Body: settingsReq})
+	assertStatusCode(t, resp, http.StatusOK)
+	resp.Body.Close()
+
+	createPR(t, env.BaseURL(), "pr-cap-1", "Feature", "author")
+
+	// pr.created пишется до подбора ревьюверов, ошибка лимита откатывает его вместе с PR
+	reqBody := map[string]string{"pull_request_id": "pr-cap-2", "pull_request_name": "Feature", "author_id": "author"}
+	resp = doRequest(t, env.BaseURL(), HTTPRequest{Method: http.MethodPost, Path: "/pullRequest/create", Body: reqBody})
+	assertStatusCode(t, resp, http.StatusConflict)
+	resp.Body.Close()
+
+	var count int
+	require.NoError(t, env.DB.QueryRow(`SELECT COUNT(*) FROM outbox_events WHERE payload->>'pull_request_id' = 'pr-cap-2'`).Scan(&count))
+	assert.Equal(t, 0, count)
+	require.NoError(t, env.DB.QueryRow(`SELECT COUNT(*) FROM outbox_events WHERE payload->>'pull_request_id' = 'pr-cap-1'`).Scan(&count))
+	assert.Equal(t, 2, count)
+}
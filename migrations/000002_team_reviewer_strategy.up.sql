@@ -0,0 +1 @@
+ALTER TABLE teams ADD COLUMN reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random';
@@ -0,0 +1,7 @@
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random';
+
+UPDATE teams t SET reviewer_strategy = s.reviewer_strategy
+FROM team_settings s
+WHERE s.team_name = t.name;
+
+DROP TABLE IF EXISTS team_settings;
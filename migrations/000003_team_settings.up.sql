@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS team_settings (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    min_reviewers INT NOT NULL DEFAULT 1,
+    max_reviewers INT NOT NULL DEFAULT 2,
+    reviewer_strategy VARCHAR(32) NOT NULL DEFAULT 'random',
+    allow_no_reviewers BOOLEAN NOT NULL DEFAULT true,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT team_settings_reviewers_range CHECK (min_reviewers >= 0 AND max_reviewers >= min_reviewers)
+);
+
+INSERT INTO team_settings (team_name, reviewer_strategy)
+SELECT name, reviewer_strategy FROM teams
+WHERE reviewer_strategy <> 'random';
+
+ALTER TABLE teams DROP COLUMN IF EXISTS reviewer_strategy;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS review_queue;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS capacity_overflow;
+ALTER TABLE team_settings DROP COLUMN IF EXISTS default_max_open_reviews;
+
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN max_open_reviews INT CHECK (max_open_reviews >= 0);
+
+ALTER TABLE team_settings ADD COLUMN default_max_open_reviews INT CHECK (default_max_open_reviews >= 0);
+ALTER TABLE team_settings ADD COLUMN capacity_overflow VARCHAR(16) NOT NULL DEFAULT 'reject';
+
+-- Отложенные назначения: по строке на каждое недостающее место ревьювера
+CREATE TABLE IF NOT EXISTS review_queue (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_review_queue_team ON review_queue(team_name, id);
+CREATE INDEX idx_review_queue_pr ON review_queue(pull_request_id);
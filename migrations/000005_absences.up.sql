@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS absences (
+    id BIGSERIAL PRIMARY KEY,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    -- когда открытые ревью пользователя были переназначены фоновой задачей
+    reviews_reassigned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT absences_period CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_absences_user_period ON absences(user_id, starts_at, ends_at);
+CREATE INDEX idx_absences_pending ON absences(starts_at) WHERE reviews_reassigned_at IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS review_verdicts (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    state VARCHAR(32) NOT NULL CHECK (state IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    body TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Последний вердикт ревьювера по PR - запись с максимальным id
+CREATE INDEX idx_review_verdicts_pr_reviewer ON review_verdicts(pull_request_id, reviewer_id, id DESC);
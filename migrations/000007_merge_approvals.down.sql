@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS merge_forced;
+
+ALTER TABLE team_settings DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,5 @@
+-- 0 - мерж без проверки одобрений
+ALTER TABLE team_settings ADD COLUMN required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+-- Смержен администратором в обход проверки одобрений
+ALTER TABLE pull_requests ADD COLUMN merge_forced BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+-- Запасные команды ревьюверов: используются по возрастанию priority, когда в своей команде не хватает кандидатов
+CREATE TABLE IF NOT EXISTS team_fallbacks (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    fallback_team VARCHAR(255) NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    priority INT NOT NULL,
+    PRIMARY KEY (team_name, fallback_team),
+    UNIQUE (team_name, priority),
+    CHECK (team_name <> fallback_team)
+);
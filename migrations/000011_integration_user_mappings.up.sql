@@ -0,0 +1,10 @@
+-- Соответствие логинов во внешних системах (GitHub, ...) пользователям сервиса
+CREATE TABLE IF NOT EXISTS integration_user_mappings (
+    provider VARCHAR(32) NOT NULL,
+    external_login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, external_login)
+);
+
+CREATE INDEX idx_integration_user_mappings_user ON integration_user_mappings(user_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS integration_project_teams;
+
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS review_team;
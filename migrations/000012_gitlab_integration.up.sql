@@ -0,0 +1,11 @@
+-- Команда ревьюверов PR, если она задана явно (например, по проекту GitLab); NULL - команда автора
+ALTER TABLE pull_requests ADD COLUMN review_team VARCHAR(255) REFERENCES teams(name) ON DELETE SET NULL;
+
+-- Привязка проектов внешних систем к командам ревьюверов
+CREATE TABLE IF NOT EXISTS integration_project_teams (
+    provider VARCHAR(32) NOT NULL,
+    project_path VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (provider, project_path)
+);
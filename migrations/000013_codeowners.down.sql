@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS changed_files;
+
+DROP TABLE IF EXISTS team_codeowners;
@@ -0,0 +1,8 @@
+-- Файл CODEOWNERS команды хранится как есть и разбирается при подборе ревьюверов
+CREATE TABLE IF NOT EXISTS team_codeowners (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE pull_requests ADD COLUMN changed_files TEXT[] NOT NULL DEFAULT '{}';
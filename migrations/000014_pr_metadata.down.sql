@@ -0,0 +1,16 @@
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS large_pr_reviewers,
+    DROP COLUMN IF EXISTS large_pr_lines;
+
+DROP INDEX IF EXISTS idx_pull_requests_labels;
+DROP INDEX IF EXISTS idx_pull_requests_repository;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS changed_files_count,
+    DROP COLUMN IF EXISTS deletions,
+    DROP COLUMN IF EXISTS additions,
+    DROP COLUMN IF EXISTS labels,
+    DROP COLUMN IF EXISTS url,
+    DROP COLUMN IF EXISTS target_branch,
+    DROP COLUMN IF EXISTS source_branch,
+    DROP COLUMN IF EXISTS repository;
@@ -0,0 +1,18 @@
+-- Метаданные PR из системы контроля версий
+ALTER TABLE pull_requests
+    ADD COLUMN repository VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN source_branch VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN target_branch VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN url VARCHAR(2048) NOT NULL DEFAULT '',
+    ADD COLUMN labels TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN additions INT NOT NULL DEFAULT 0 CHECK (additions >= 0),
+    ADD COLUMN deletions INT NOT NULL DEFAULT 0 CHECK (deletions >= 0),
+    ADD COLUMN changed_files_count INT NOT NULL DEFAULT 0 CHECK (changed_files_count >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository ON pull_requests(repository);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_labels ON pull_requests USING GIN (labels);
+
+-- Большим PR (additions + deletions > large_pr_lines) назначается large_pr_reviewers ревьюверов; 0 - правило выключено
+ALTER TABLE team_settings
+    ADD COLUMN large_pr_lines INT NOT NULL DEFAULT 0 CHECK (large_pr_lines >= 0),
+    ADD COLUMN large_pr_reviewers INT NOT NULL DEFAULT 0 CHECK (large_pr_reviewers >= 0);
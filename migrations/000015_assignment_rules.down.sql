@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS assignment_rule_firings;
+DROP TABLE IF EXISTS assignment_rules;
@@ -0,0 +1,33 @@
+-- Правила назначения ревьюверов команды: условие по метаданным PR и действие.
+-- Правила проверяются по возрастанию priority, срабатывают все подходящие.
+CREATE TABLE IF NOT EXISTS assignment_rules (
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(name) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    priority INT NOT NULL,
+    repository VARCHAR(255) NOT NULL DEFAULT '',
+    target_branch VARCHAR(255) NOT NULL DEFAULT '',
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    min_lines INT CHECK (min_lines >= 0),
+    max_lines INT CHECK (max_lines >= 0),
+    reviewers INT NOT NULL DEFAULT 0 CHECK (reviewers >= 0),
+    extra_team VARCHAR(255) REFERENCES teams(name) ON DELETE CASCADE,
+    extra_reviewers INT NOT NULL DEFAULT 0 CHECK (extra_reviewers >= 0),
+    PRIMARY KEY (team_name, name),
+    UNIQUE (team_name, priority)
+);
+
+-- Сработавшие на PR правила; хранятся копией, чтобы история не менялась при правке правил
+CREATE TABLE IF NOT EXISTS assignment_rule_firings (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    team_name VARCHAR(255) NOT NULL,
+    rule_name VARCHAR(100) NOT NULL,
+    reviewers INT NOT NULL DEFAULT 0,
+    extra_team VARCHAR(255) NOT NULL DEFAULT '',
+    extra_reviewers INT NOT NULL DEFAULT 0,
+    assigned_reviewers TEXT[] NOT NULL DEFAULT '{}',
+    source VARCHAR(32) NOT NULL,
+    fired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_rule_firings_pr ON assignment_rule_firings(pull_request_id, id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+DROP INDEX IF EXISTS idx_pull_requests_review_team;
+DROP INDEX IF EXISTS idx_pull_requests_author_created;
+DROP INDEX IF EXISTS idx_pull_requests_status_created;
+DROP INDEX IF EXISTS idx_pull_requests_merged;
+DROP INDEX IF EXISTS idx_pull_requests_created;
@@ -0,0 +1,10 @@
+-- Индексы для списка PR: постраничный обход по (created_at, id) и (merged_at, id) от новых к старым
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created ON pull_requests(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged ON pull_requests(merged_at DESC, id DESC) WHERE merged_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created ON pull_requests(status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_created ON pull_requests(author_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_review_team ON pull_requests(review_team) WHERE review_team IS NOT NULL;
+
+-- Поиск по подстроке названия (ILIKE '%...%')
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_pull_requests_name_trgm ON pull_requests USING GIN (name gin_trgm_ops);
@@ -0,0 +1,13 @@
+-- История назначений ревьюверов: каждое добавление, замена и снятие ревьювера PR
+CREATE TABLE IF NOT EXISTS assignment_events (
+    id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL DEFAULT '',
+    previous_reviewer_id VARCHAR(255) NOT NULL DEFAULT '',
+    reason VARCHAR(32) NOT NULL,
+    actor VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_events_pr ON assignment_events(pull_request_id, id);
@@ -0,0 +1,2 @@
+-- Откат невозможен, пока в базе есть исключённые из команд пользователи
+ALTER TABLE users ALTER COLUMN team_name SET NOT NULL;
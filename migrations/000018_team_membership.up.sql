@@ -0,0 +1,2 @@
+-- Исключённый из команды пользователь остаётся в базе ради истории PR и ревью, но без команды
+ALTER TABLE users ALTER COLUMN team_name DROP NOT NULL;
@@ -0,0 +1,32 @@
+-- Переименование команды каскадом обновляет все ссылки на неё.
+-- Удаление команды больше не удаляет её участников: они остаются в базе без команды.
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_team_name_fkey;
+ALTER TABLE users ADD CONSTRAINT users_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE SET NULL;
+
+ALTER TABLE team_settings DROP CONSTRAINT IF EXISTS team_settings_team_name_fkey;
+ALTER TABLE team_settings ADD CONSTRAINT team_settings_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE review_queue DROP CONSTRAINT IF EXISTS review_queue_team_name_fkey;
+ALTER TABLE review_queue ADD CONSTRAINT review_queue_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_team_name_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE team_fallbacks DROP CONSTRAINT IF EXISTS team_fallbacks_fallback_team_fkey;
+ALTER TABLE team_fallbacks ADD CONSTRAINT team_fallbacks_fallback_team_fkey FOREIGN KEY (fallback_team) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_review_team_fkey;
+ALTER TABLE pull_requests ADD CONSTRAINT pull_requests_review_team_fkey FOREIGN KEY (review_team) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE SET NULL;
+
+ALTER TABLE integration_project_teams DROP CONSTRAINT IF EXISTS integration_project_teams_team_name_fkey;
+ALTER TABLE integration_project_teams ADD CONSTRAINT integration_project_teams_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE team_codeowners DROP CONSTRAINT IF EXISTS team_codeowners_team_name_fkey;
+ALTER TABLE team_codeowners ADD CONSTRAINT team_codeowners_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE assignment_rules DROP CONSTRAINT IF EXISTS assignment_rules_team_name_fkey;
+ALTER TABLE assignment_rules ADD CONSTRAINT assignment_rules_team_name_fkey FOREIGN KEY (team_name) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
+
+ALTER TABLE assignment_rules DROP CONSTRAINT IF EXISTS assignment_rules_extra_team_fkey;
+ALTER TABLE assignment_rules ADD CONSTRAINT assignment_rules_extra_team_fkey FOREIGN KEY (extra_team) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+-- Версия PR для оптимистичной блокировки: увеличивается при каждом изменении PR или его ревьюверов
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+-- Кто смержил PR в обход политики одобрений
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS merge_forced_by VARCHAR(255);
@@ -0,0 +1,3 @@
+-- Правила других команд, добирающие ревьюверов из удаляемой, не удаляются молча: их нужно изменить до удаления
+ALTER TABLE assignment_rules DROP CONSTRAINT IF EXISTS assignment_rules_extra_team_fkey;
+ALTER TABLE assignment_rules ADD CONSTRAINT assignment_rules_extra_team_fkey FOREIGN KEY (extra_team) REFERENCES teams(name) ON UPDATE CASCADE ON DELETE RESTRICT;
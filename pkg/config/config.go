@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,18 @@ type Config struct {
 	DBSSLMode  string
 	ServerPort string
 	LogLevel   string
+	// AdminToken - токен для действий администратора (X-Admin-Token), пустой - такие действия запрещены
+	AdminToken string
+	// GitHubWebhookSecret - секрет для проверки X-Hub-Signature-256, пустой - входящие события GitHub отклоняются
+	GitHubWebhookSecret string
+	// GitLabWebhookToken - ожидаемое значение X-Gitlab-Token, пустой - входящие события GitLab отклоняются
+	GitLabWebhookToken string
+	// ReviewQueueInterval - как часто раздавать отложенные из-за лимитов места ревьюверов
+	ReviewQueueInterval time.Duration
+	// AbsenceJobInterval - как часто переназначать ревью пользователей, у которых началось отсутствие
+	AbsenceJobInterval time.Duration
+	// WebhookDispatchInterval - как часто раздавать события outbox и отправлять webhook
+	WebhookDispatchInterval time.Duration
 }
 
 func Load() (*Config, error) {
@@ -30,6 +43,21 @@ func Load() (*Config, error) {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 		LogLevel:   getEnv("LOG_LEVEL", "info"),
+		AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+		GitHubWebhookSecret: getEnv("GITHUB_WEBHOOK_SECRET", ""),
+		GitLabWebhookToken:  getEnv("GITLAB_WEBHOOK_TOKEN", ""),
+	}
+
+	var err error
+	if cfg.ReviewQueueInterval, err = getInterval("REVIEW_QUEUE_INTERVAL", "30s"); err != nil {
+		return nil, err
+	}
+	if cfg.AbsenceJobInterval, err = getInterval("ABSENCE_JOB_INTERVAL", "1m"); err != nil {
+		return nil, err
+	}
+	if cfg.WebhookDispatchInterval, err = getInterval("WEBHOOK_DISPATCH_INTERVAL", "5s"); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
@@ -48,3 +76,16 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// getInterval разбирает период фоновой задачи; нулевой или отрицательный период не допускается
+func getInterval(key, defaultVal string) (time.Duration, error) {
+	raw := getEnv(key, defaultVal)
+	interval, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	if interval <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q must be a positive duration", key, raw)
+	}
+	return interval, nil
+}
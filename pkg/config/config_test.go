@@ -0,0 +1,28 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad_Intervals(t *testing.T) {
+	t.Setenv("REVIEW_QUEUE_INTERVAL", "10s")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ReviewQueueInterval != 10*time.Second || cfg.AbsenceJobInterval != time.Minute {
+		t.Errorf("unexpected intervals: %v, %v", cfg.ReviewQueueInterval, cfg.AbsenceJobInterval)
+	}
+
+	for _, key := range []string{"REVIEW_QUEUE_INTERVAL", "ABSENCE_JOB_INTERVAL", "WEBHOOK_DISPATCH_INTERVAL"} {
+		for _, value := range []string{"0s", "-1m", "soon"} {
+			t.Run(key+"="+value, func(t *testing.T) {
+				t.Setenv(key, value)
+				if _, err := Load(); err == nil {
+					t.Errorf("Load() expected error for %s=%s", key, value)
+				}
+			})
+		}
+	}
+}